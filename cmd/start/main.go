@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 
@@ -18,6 +19,7 @@ func main() {
 	fs := &adapters.RealFileSystem{}
 	runner := &adapters.RealRunner{}
 	commandRunner := adapters.NewRealCommandRunner()
+	processRunner := adapters.NewRealProcessRunner()
 	githubClient := adapters.NewRealGitHubClient()
 
 	// Create config loader
@@ -40,15 +42,32 @@ func main() {
 	cacheBase := filepath.Join(home, ".config", "start", "assets")
 	cache := adapters.NewFileCache(fs, cacheBase)
 
+	// Create history log in the state directory
+	stateBase := os.Getenv("XDG_STATE_HOME")
+	if stateBase == "" {
+		stateBase = filepath.Join(home, ".local", "state")
+	}
+	history := adapters.NewJSONLHistory(filepath.Join(stateBase, "start", "history.jsonl"))
+
+	// Create command output, url and model caches in the user cache directory
+	cacheHome := os.Getenv("XDG_CACHE_HOME")
+	if cacheHome == "" {
+		cacheHome = filepath.Join(home, ".cache")
+	}
+	outputCache := adapters.NewFileOutputCache(filepath.Join(cacheHome, "start", "commands"))
+	urlFetcher := adapters.NewHTTPURLFetcher(filepath.Join(cacheHome, "start", "urls"))
+	modelCache := adapters.NewFileModelCache(filepath.Join(cacheHome, "start", "models"))
+
 	// Create engine components
-	placeholderResolver := engine.NewPlaceholderResolver()
-	utdProcessor := engine.NewUTDProcessor(fs, commandRunner, workDir)
+	placeholderResolver := engine.NewPlaceholderResolver(commandRunner, workDir)
+	utdProcessor := engine.NewUTDProcessor(fs, commandRunner, workDir, outputCache, urlFetcher)
 	roleSelector := engine.NewRoleSelector()
 	roleLoader := engine.NewRoleLoader(utdProcessor, fs)
 	contextLoader := engine.NewContextLoader(utdProcessor)
 	taskLoader := engine.NewTaskLoader(utdProcessor, placeholderResolver)
 	taskResolver := engine.NewTaskResolver()
-	executor := engine.NewExecutor(runner, placeholderResolver)
+	executor := engine.NewExecutor(runner, processRunner, placeholderResolver, fs, history)
+	modelCatalog := engine.NewModelCatalog(commandRunner, urlFetcher, modelCache)
 
 	// Create asset resolver
 	assetResolver := assets.NewResolver(fs, cache, githubClient, configLoader)
@@ -63,12 +82,22 @@ func main() {
 		contextLoader,
 		taskLoader,
 		taskResolver,
+		placeholderResolver,
+		utdProcessor,
 		assetResolver,
+		history,
+		outputCache,
+		modelCatalog,
 		version,
 	)
 
 	// Execute
 	if err := rootCmd.Execute(); err != nil {
+		// Headless runs exit with the agent's exit code
+		var exitErr *cli.ExitCodeError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.Code)
+		}
 		os.Exit(1)
 	}
 }
@@ -20,10 +20,46 @@ func (r *RealRunner) Exec(shell, command string) error {
 	}
 
 	// Replace current process with shell running command
-	// Args: [0] = shell name, [1] = "-c", [2] = command
+	// Args: [0] = shell name, [1] = shell flag ("-c", "-e", ...), [2] = command
 	// Env: inherit current environment
-	err = syscall.Exec(shellPath, []string{shell, "-c", command}, os.Environ())
+	err = syscall.Exec(shellPath, []string{shell, getShellFlag(shell), command}, os.Environ())
 
 	// Only reached if exec fails
 	return fmt.Errorf("exec failed: %w", err)
 }
+
+// ExecArgs replaces the current process with args[0] without a shell
+// This never returns on success - the process is replaced
+func (r *RealRunner) ExecArgs(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("no command to execute")
+	}
+
+	// Find agent binary
+	binPath, err := exec.LookPath(args[0])
+	if err != nil {
+		return fmt.Errorf("binary not found: %w", err)
+	}
+
+	// Replace current process with agent, inheriting the environment
+	err = syscall.Exec(binPath, args, os.Environ())
+
+	// Only reached if exec fails
+	return fmt.Errorf("exec failed: %w", err)
+}
+
+// RedirectStdin replaces standard input with the file at path
+// The descriptor survives exec, so the agent reads the file as its stdin
+func (r *RealRunner) RedirectStdin(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open stdin file: %w", err)
+	}
+	defer f.Close()
+
+	if err := dupStdin(int(f.Fd())); err != nil {
+		return fmt.Errorf("failed to redirect stdin: %w", err)
+	}
+
+	return nil
+}
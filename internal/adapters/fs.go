@@ -28,12 +28,37 @@ func (fs *RealFileSystem) Exists(path string) bool {
 	return err == nil
 }
 
+// IsDir checks if a path is a directory
+func (fs *RealFileSystem) IsDir(path string) bool {
+	info, err := os.Stat(expandPath(path))
+	return err == nil && info.IsDir()
+}
+
 // Glob returns paths matching a pattern
 func (fs *RealFileSystem) Glob(pattern string) ([]string, error) {
 	expanded := expandPath(pattern)
 	return filepath.Glob(expanded)
 }
 
+// WalkFiles returns all regular files under root, recursively
+// .git directories are skipped and symlinks are not followed
+func (fs *RealFileSystem) WalkFiles(root string) ([]string, error) {
+	var files []string
+	err := filepath.WalkDir(expandPath(root), func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if d.Type().IsRegular() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	return files, err
+}
+
 // MkdirAll creates a directory and all parents
 func (fs *RealFileSystem) MkdirAll(path string, perm os.FileMode) error {
 	expanded := expandPath(path)
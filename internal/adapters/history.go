@@ -0,0 +1,76 @@
+package adapters
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/grantcarthew/start/internal/domain"
+)
+
+// JSONLHistory implements the HistoryStore interface as a JSON Lines file
+// Location: $XDG_STATE_HOME/start/history.jsonl (default ~/.local/state/start)
+type JSONLHistory struct {
+	Path string
+}
+
+// NewJSONLHistory creates a history log at path
+func NewJSONLHistory(path string) *JSONLHistory {
+	return &JSONLHistory{Path: path}
+}
+
+// Append writes entry as a single line at the end of the log
+func (h *JSONLHistory) Append(entry domain.HistoryEntry) error {
+	if err := os.MkdirAll(filepath.Dir(h.Path), 0700); err != nil {
+		return fmt.Errorf("failed to create state directory: %w", err)
+	}
+
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode history entry: %w", err)
+	}
+
+	f, err := os.OpenFile(h.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open history log: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write history log: %w", err)
+	}
+
+	return nil
+}
+
+// List reads all entries, oldest first
+// Lines that cannot be decoded are skipped
+func (h *JSONLHistory) List() ([]domain.HistoryEntry, error) {
+	f, err := os.Open(h.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history log: %w", err)
+	}
+	defer f.Close()
+
+	var entries []domain.HistoryEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024) // Entries embed full commands
+	for scanner.Scan() {
+		var entry domain.HistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return entries, fmt.Errorf("failed to read history log: %w", err)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,71 @@
+package adapters
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileModelCache implements the ModelCache interface with one JSON file per agent
+// Location: $XDG_CACHE_HOME/start/models (default ~/.cache/start/models)
+type FileModelCache struct {
+	Dir string
+}
+
+// cachedModels is the on-disk format of an agent's model IDs
+type cachedModels struct {
+	Agent    string    `json:"agent"`
+	IDs      []string  `json:"ids"`
+	StoredAt time.Time `json:"stored_at"`
+}
+
+// NewFileModelCache creates a model ID cache in dir
+func NewFileModelCache(dir string) *FileModelCache {
+	return &FileModelCache{Dir: dir}
+}
+
+// Get returns the model IDs stored for an agent
+func (c *FileModelCache) Get(agent string) ([]string, time.Time, bool) {
+	data, err := os.ReadFile(c.path(agent))
+	if err != nil {
+		return nil, time.Time{}, false
+	}
+
+	var entry cachedModels
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Agent != agent {
+		return nil, time.Time{}, false
+	}
+
+	return entry.IDs, entry.StoredAt, true
+}
+
+// Set stores the model IDs for an agent
+func (c *FileModelCache) Set(agent string, ids []string) error {
+	if err := os.MkdirAll(c.Dir, 0700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	data, err := json.Marshal(cachedModels{Agent: agent, IDs: ids, StoredAt: time.Now()})
+	if err != nil {
+		return fmt.Errorf("failed to encode model list: %w", err)
+	}
+
+	// Write then rename so concurrent readers never see a partial file
+	tmp := c.path(agent) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write model list: %w", err)
+	}
+	if err := os.Rename(tmp, c.path(agent)); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write model list: %w", err)
+	}
+
+	return nil
+}
+
+// path returns the cache file for an agent
+func (c *FileModelCache) path(agent string) string {
+	return filepath.Join(c.Dir, agent+".json")
+}
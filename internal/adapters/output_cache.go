@@ -0,0 +1,82 @@
+package adapters
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// FileOutputCache implements the OutputCache interface with one JSON file per key
+// Location: $XDG_CACHE_HOME/start/commands (default ~/.cache/start/commands)
+type FileOutputCache struct {
+	Dir string
+}
+
+// cachedOutput is the on-disk format of a cached command output
+type cachedOutput struct {
+	Output   string    `json:"output"`
+	StoredAt time.Time `json:"stored_at"`
+}
+
+// NewFileOutputCache creates a command output cache in dir
+func NewFileOutputCache(dir string) *FileOutputCache {
+	return &FileOutputCache{Dir: dir}
+}
+
+// Get returns the output stored for key if it is younger than ttl
+func (c *FileOutputCache) Get(key string, ttl time.Duration) (string, time.Time, bool) {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return "", time.Time{}, false
+	}
+
+	var entry cachedOutput
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return "", time.Time{}, false
+	}
+
+	if time.Since(entry.StoredAt) > ttl {
+		return "", time.Time{}, false
+	}
+
+	return entry.Output, entry.StoredAt, true
+}
+
+// Set stores output for key
+func (c *FileOutputCache) Set(key, output string) error {
+	if err := os.MkdirAll(c.Dir, 0700); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	data, err := json.Marshal(cachedOutput{Output: output, StoredAt: time.Now()})
+	if err != nil {
+		return fmt.Errorf("failed to encode cached output: %w", err)
+	}
+
+	// Write then rename so concurrent readers never see a partial file
+	tmp := c.path(key) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write cached output: %w", err)
+	}
+	if err := os.Rename(tmp, c.path(key)); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write cached output: %w", err)
+	}
+
+	return nil
+}
+
+// Clear removes all cached output
+func (c *FileOutputCache) Clear() error {
+	if err := os.RemoveAll(c.Dir); err != nil {
+		return fmt.Errorf("failed to clear command cache: %w", err)
+	}
+	return nil
+}
+
+// path returns the file for a cache key
+func (c *FileOutputCache) path(key string) string {
+	return filepath.Join(c.Dir, key+".json")
+}
@@ -0,0 +1,62 @@
+package adapters
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+)
+
+// RealProcessRunner runs agents as child processes for headless mode
+type RealProcessRunner struct{}
+
+// NewRealProcessRunner creates a new process runner
+func NewRealProcessRunner() *RealProcessRunner {
+	return &RealProcessRunner{}
+}
+
+// Run runs the command through the shell and returns its exit code
+func (r *RealProcessRunner) Run(shell, command string, stdin io.Reader, stdout io.Writer) (int, error) {
+	shellPath, err := exec.LookPath(shell)
+	if err != nil {
+		return -1, fmt.Errorf("shell not found: %w", err)
+	}
+
+	return r.run(exec.Command(shellPath, getShellFlag(shell), command), stdin, stdout)
+}
+
+// RunArgs runs args[0] directly without a shell and returns its exit code
+func (r *RealProcessRunner) RunArgs(args []string, stdin io.Reader, stdout io.Writer) (int, error) {
+	if len(args) == 0 {
+		return -1, fmt.Errorf("no command to execute")
+	}
+
+	binPath, err := exec.LookPath(args[0])
+	if err != nil {
+		return -1, fmt.Errorf("binary not found: %w", err)
+	}
+
+	return r.run(exec.Command(binPath, args[1:]...), stdin, stdout)
+}
+
+// run starts the child, streams its stdout and waits for it to exit
+// Stderr is passed through so progress and errors stay visible
+func (r *RealProcessRunner) run(cmd *exec.Cmd, stdin io.Reader, stdout io.Writer) (int, error) {
+	cmd.Stdin = stdin
+	cmd.Stdout = stdout
+	cmd.Stderr = os.Stderr
+
+	err := cmd.Run()
+	if err == nil {
+		return 0, nil
+	}
+
+	// Non-zero exit is a result, not a failure to run
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitCode(), nil
+	}
+
+	return -1, fmt.Errorf("failed to run agent: %w", err)
+}
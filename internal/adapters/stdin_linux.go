@@ -0,0 +1,9 @@
+package adapters
+
+import "syscall"
+
+// dupStdin duplicates fd onto standard input
+// Dup2 is not available on every Linux architecture, Dup3 is
+func dupStdin(fd int) error {
+	return syscall.Dup3(fd, 0, 0)
+}
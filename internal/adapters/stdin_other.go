@@ -0,0 +1,10 @@
+//go:build !linux
+
+package adapters
+
+import "syscall"
+
+// dupStdin duplicates fd onto standard input
+func dupStdin(fd int) error {
+	return syscall.Dup2(fd, 0)
+}
@@ -0,0 +1,151 @@
+package adapters
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/grantcarthew/start/internal/domain"
+)
+
+// HTTPURLFetcher implements the URLFetcher interface over HTTP with an on-disk
+// cache revalidated through ETag and Last-Modified
+// Location: $XDG_CACHE_HOME/start/urls (default ~/.cache/start/urls)
+type HTTPURLFetcher struct {
+	Dir    string
+	client *http.Client
+}
+
+// cachedURL is the on-disk format of a fetched URL
+type cachedURL struct {
+	URL          string    `json:"url"`
+	Body         string    `json:"body"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	FetchedAt    time.Time `json:"fetched_at"`
+}
+
+// NewHTTPURLFetcher creates a URL fetcher caching responses in dir
+func NewHTTPURLFetcher(dir string) *HTTPURLFetcher {
+	return &HTTPURLFetcher{Dir: dir, client: &http.Client{}}
+}
+
+// Fetch returns the body at url
+// A cached copy is sent for revalidation; 304 Not Modified reuses it, and a
+// network failure or server error falls back to it
+func (f *HTTPURLFetcher) Fetch(ctx context.Context, url string, timeout time.Duration) (domain.FetchedURL, error) {
+	cached, hasCache := f.load(url)
+
+	body, notModified, err := f.get(ctx, url, timeout, cached, hasCache)
+	if err != nil {
+		if hasCache {
+			return domain.FetchedURL{Body: cached.Body, FetchedAt: cached.FetchedAt, Stale: true, StaleErr: err}, nil
+		}
+		return domain.FetchedURL{}, err
+	}
+
+	if notModified {
+		cached.FetchedAt = time.Now()
+		f.store(cached)
+		return domain.FetchedURL{Body: cached.Body, FetchedAt: cached.FetchedAt}, nil
+	}
+
+	f.store(body)
+	return domain.FetchedURL{Body: body.Body, FetchedAt: body.FetchedAt}, nil
+}
+
+// get performs the conditional request
+// Returns the new response, or notModified when the cached copy is current
+func (f *HTTPURLFetcher) get(ctx context.Context, url string, timeout time.Duration, cached cachedURL, hasCache bool) (cachedURL, bool, error) {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return cachedURL{}, false, fmt.Errorf("failed to create request: %w", err)
+	}
+	if hasCache {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return cachedURL{}, false, fmt.Errorf("failed to fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && hasCache {
+		return cachedURL{}, true, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return cachedURL{}, false, fmt.Errorf("failed to fetch %s: HTTP %d", url, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return cachedURL{}, false, fmt.Errorf("failed to read %s: %w", url, err)
+	}
+
+	return cachedURL{
+		URL:          url,
+		Body:         string(data),
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		FetchedAt:    time.Now(),
+	}, false, nil
+}
+
+// load returns the cached copy of url
+func (f *HTTPURLFetcher) load(url string) (cachedURL, bool) {
+	data, err := os.ReadFile(f.path(url))
+	if err != nil {
+		return cachedURL{}, false
+	}
+
+	var entry cachedURL
+	if err := json.Unmarshal(data, &entry); err != nil || entry.URL != url {
+		return cachedURL{}, false
+	}
+	return entry, true
+}
+
+// store saves a fetched URL, best effort since the cache is only a fallback
+func (f *HTTPURLFetcher) store(entry cachedURL) {
+	if err := os.MkdirAll(f.Dir, 0700); err != nil {
+		return
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	// Write then rename so concurrent readers never see a partial file
+	tmp := f.path(entry.URL) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return
+	}
+	if err := os.Rename(tmp, f.path(entry.URL)); err != nil {
+		os.Remove(tmp)
+	}
+}
+
+// path returns the cache file for a URL
+func (f *HTTPURLFetcher) path(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(f.Dir, hex.EncodeToString(sum[:])+".json")
+}
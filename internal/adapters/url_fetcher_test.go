@@ -0,0 +1,90 @@
+package adapters_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/grantcarthew/start/internal/adapters"
+	"github.com/grantcarthew/start/test/assert"
+)
+
+func TestHTTPURLFetcher_RevalidatesAndFallsBack(t *testing.T) {
+	var requests, notModified int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte("# Coding standards"))
+	}))
+
+	fetcher := adapters.NewHTTPURLFetcher(t.TempDir())
+	url := server.URL + "/standards.md"
+
+	// First fetch stores the body and its ETag
+	fetched, err := fetcher.Fetch(context.Background(), url, 5*time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, "# Coding standards", fetched.Body)
+	assert.False(t, fetched.Stale, "first fetch should be fresh")
+
+	// Second fetch revalidates and reuses the cached body on 304
+	fetched, err = fetcher.Fetch(context.Background(), url, 5*time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, "# Coding standards", fetched.Body)
+	assert.Equal(t, 2, requests)
+	assert.Equal(t, 1, notModified)
+
+	// Offline, the cached copy is returned as stale
+	server.Close()
+	fetched, err = fetcher.Fetch(context.Background(), url, 5*time.Second)
+	assert.NoError(t, err)
+	assert.True(t, fetched.Stale, "offline fetch should fall back to the cache")
+	assert.Equal(t, "# Coding standards", fetched.Body)
+	assert.Error(t, fetched.StaleErr)
+
+	// Without a cached copy an unreachable server is an error
+	_, err = fetcher.Fetch(context.Background(), server.URL+"/other.md", 5*time.Second)
+	assert.Error(t, err)
+}
+
+func TestHTTPURLFetcher_LastModifiedAndErrors(t *testing.T) {
+	const modified = "Wed, 01 Jan 2025 00:00:00 GMT"
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		if r.Header.Get("If-Modified-Since") == modified {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Last-Modified", modified)
+		w.Write([]byte("v1"))
+	}))
+	defer server.Close()
+
+	fetcher := adapters.NewHTTPURLFetcher(t.TempDir())
+
+	fetched, err := fetcher.Fetch(context.Background(), server.URL, time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, "v1", fetched.Body)
+
+	fetched, err = fetcher.Fetch(context.Background(), server.URL, time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, "v1", fetched.Body)
+	assert.False(t, fetched.Stale, "304 should not be stale")
+
+	// A server error falls back to the cached copy
+	status = http.StatusInternalServerError
+	fetched, err = fetcher.Fetch(context.Background(), server.URL, time.Second)
+	assert.NoError(t, err)
+	assert.True(t, fetched.Stale, "server error should fall back to the cache")
+	assert.Contains(t, fetched.StaleErr.Error(), "HTTP 500")
+}
@@ -12,7 +12,7 @@ import (
 
 // mockCache for testing
 type mockCache struct {
-	data  map[string]map[string][]byte // assetType -> name -> data
+	data     map[string]map[string][]byte // assetType -> name -> data
 	setError error
 	getError error
 }
@@ -104,10 +104,18 @@ func (m *mockFS) Exists(path string) bool {
 	return ok
 }
 
+func (m *mockFS) IsDir(path string) bool {
+	return false
+}
+
 func (m *mockFS) Glob(pattern string) ([]string, error) {
 	return nil, nil
 }
 
+func (m *mockFS) WalkFiles(root string) ([]string, error) {
+	return nil, nil
+}
+
 func (m *mockFS) MkdirAll(path string, perm os.FileMode) error {
 	return nil
 }
@@ -0,0 +1,37 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/grantcarthew/start/internal/domain"
+	"github.com/spf13/cobra"
+)
+
+// NewCacheCommand creates the cache command
+func NewCacheCommand(outputCache domain.OutputCache) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage cached command output",
+		Long: `Manage the command output cache. Roles, contexts and tasks with a cache_ttl
+reuse their command output until it expires or a cache_key_files file changes.`,
+	}
+
+	cmd.AddCommand(&cobra.Command{
+		Use:   "clear",
+		Short: "Remove all cached command output",
+		Long: `Removes all cached command output. The next run executes every command again.
+
+Examples:
+  start cache clear`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := outputCache.Clear(); err != nil {
+				return err
+			}
+			fmt.Println("✓ Command output cache cleared")
+			return nil
+		},
+	})
+
+	return cmd
+}
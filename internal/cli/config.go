@@ -5,12 +5,13 @@ import (
 	"os"
 
 	"github.com/grantcarthew/start/internal/config"
+	"github.com/grantcarthew/start/internal/engine"
 	"github.com/pelletier/go-toml/v2"
 	"github.com/spf13/cobra"
 )
 
 // NewConfigCommand creates the config command
-func NewConfigCommand(configLoader *config.Loader, validator *config.Validator) *cobra.Command {
+func NewConfigCommand(configLoader *config.Loader, validator *config.Validator, roleLoader *engine.RoleLoader, modelCatalog *engine.ModelCatalog) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "config",
 		Short: "Manage configuration",
@@ -20,8 +21,8 @@ func NewConfigCommand(configLoader *config.Loader, validator *config.Validator)
 	// Add subcommands
 	cmd.AddCommand(NewConfigShowCommand(configLoader, validator))
 	cmd.AddCommand(NewConfigEditCommand(configLoader, validator))
-	cmd.AddCommand(NewConfigAgentCommand(configLoader, validator))
-	cmd.AddCommand(NewConfigRoleCommand(configLoader, validator))
+	cmd.AddCommand(NewConfigAgentCommand(configLoader, validator, modelCatalog))
+	cmd.AddCommand(NewConfigRoleCommand(configLoader, validator, roleLoader))
 	cmd.AddCommand(NewConfigContextCommand(configLoader, validator))
 	cmd.AddCommand(NewConfigTaskCommand(configLoader, validator))
 
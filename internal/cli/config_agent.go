@@ -9,11 +9,12 @@ import (
 
 	"github.com/grantcarthew/start/internal/config"
 	"github.com/grantcarthew/start/internal/domain"
+	"github.com/grantcarthew/start/internal/engine"
 	"github.com/spf13/cobra"
 )
 
 // NewConfigAgentCommand creates the config agent command
-func NewConfigAgentCommand(configLoader *config.Loader, validator *config.Validator) *cobra.Command {
+func NewConfigAgentCommand(configLoader *config.Loader, validator *config.Validator, modelCatalog *engine.ModelCatalog) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "agent",
 		Short: "Manage AI agent configurations",
@@ -24,6 +25,7 @@ func NewConfigAgentCommand(configLoader *config.Loader, validator *config.Valida
 	cmd.AddCommand(NewConfigAgentListCommand(configLoader))
 	cmd.AddCommand(NewConfigAgentShowCommand(configLoader))
 	cmd.AddCommand(NewConfigAgentTestCommand(configLoader))
+	cmd.AddCommand(NewConfigAgentModelsCommand(configLoader, modelCatalog))
 	cmd.AddCommand(NewConfigAgentNewCommand(configLoader))
 	cmd.AddCommand(NewConfigAgentEditCommand(configLoader))
 	cmd.AddCommand(NewConfigAgentRemoveCommand(configLoader))
@@ -47,6 +49,7 @@ func NewConfigAgentListCommand(configLoader *config.Loader) *cobra.Command {
 			}
 
 			var agents map[string]domain.Agent
+			var order []string
 
 			if localOnly {
 				// Load local only
@@ -55,6 +58,7 @@ func NewConfigAgentListCommand(configLoader *config.Loader) *cobra.Command {
 					return fmt.Errorf("failed to load local config: %w", err)
 				}
 				agents = localCfg.Agents
+				order = localCfg.AgentOrder
 			} else {
 				// Load and merge global + local
 				globalCfg, err := configLoader.LoadGlobal()
@@ -66,8 +70,10 @@ func NewConfigAgentListCommand(configLoader *config.Loader) *cobra.Command {
 				if err == nil {
 					mergedCfg := config.Merge(globalCfg, localCfg)
 					agents = mergedCfg.Agents
+					order = mergedCfg.AgentOrder
 				} else {
-					agents = globalCfg.Agents
+					agents = config.ResolveAgents(globalCfg.Agents)
+					order = globalCfg.AgentOrder
 				}
 			}
 
@@ -79,12 +85,16 @@ func NewConfigAgentListCommand(configLoader *config.Loader) *cobra.Command {
 				return nil
 			}
 
-			// Sort agents by name for consistent output
-			names := make([]string, 0, len(agents))
-			for name := range agents {
-				names = append(names, name)
+			// Definition order, explicit order first
+			names := orderedNames(agents, order)
+
+			// Variants by parent, in list order
+			variants := make(map[string][]string)
+			for _, name := range names {
+				if parent := agents[name].Extends; parent != "" {
+					variants[parent] = append(variants[parent], name)
+				}
 			}
-			sort.Strings(names)
 
 			fmt.Println("Configured agents:")
 			fmt.Println()
@@ -94,10 +104,20 @@ func NewConfigAgentListCommand(configLoader *config.Loader) *cobra.Command {
 				if agent.Description != "" {
 					fmt.Printf("  %s\n", agent.Description)
 				}
+				if agent.Extends != "" {
+					fmt.Printf("  Extends: %s\n", agent.Extends)
+				}
+				if len(variants[name]) > 0 {
+					fmt.Printf("  Variants: %s\n", strings.Join(variants[name], ", "))
+				}
 				if agent.URL != "" {
 					fmt.Printf("  %s\n", agent.URL)
 				}
-				fmt.Printf("  Command: %s\n", agent.Command)
+				if len(agent.Args) > 0 {
+					fmt.Printf("  Args: %s\n", formatArgsTemplate(agent.Args))
+				} else {
+					fmt.Printf("  Command: %s\n", agent.Command)
+				}
 
 				// Show default model
 				if agent.DefaultModel != "" {
@@ -193,7 +213,7 @@ func NewConfigAgentShowCommand(configLoader *config.Loader) *cobra.Command {
 						scope = "global"
 					}
 				} else {
-					agents = globalCfg.Agents
+					agents = config.ResolveAgents(globalCfg.Agents)
 					scope = "global"
 				}
 			}
@@ -208,6 +228,9 @@ func NewConfigAgentShowCommand(configLoader *config.Loader) *cobra.Command {
 			fmt.Println("═══════════════════════════════════════════════════════════")
 			fmt.Println()
 
+			if agent.Extends != "" {
+				fmt.Printf("Extends: %s\n", agent.Extends)
+			}
 			if agent.Description != "" {
 				fmt.Printf("Description: %s\n", agent.Description)
 			}
@@ -217,13 +240,28 @@ func NewConfigAgentShowCommand(configLoader *config.Loader) *cobra.Command {
 			if agent.ModelsURL != "" {
 				fmt.Printf("Models URL: %s\n", agent.ModelsURL)
 			}
+			if agent.ModelsCommand != "" {
+				fmt.Printf("Models command: %s\n", agent.ModelsCommand)
+			}
 			if agent.Bin != "" {
 				fmt.Printf("Binary: %s\n", agent.Bin)
 			}
 			fmt.Println()
 
-			fmt.Println("Command template:")
-			fmt.Printf("  %s\n", agent.Command)
+			if len(agent.Args) > 0 {
+				fmt.Println("Args template (no shell):")
+				fmt.Printf("  %s\n", formatArgsTemplate(agent.Args))
+			} else {
+				fmt.Println("Command template:")
+				fmt.Printf("  %s\n", agent.Command)
+			}
+			if agent.HeadlessCommand != "" {
+				fmt.Println("Headless command template (--capture/--output):")
+				fmt.Printf("  %s\n", agent.HeadlessCommand)
+			}
+			if agent.PromptDelivery != "" {
+				fmt.Printf("Prompt delivery: %s\n", agent.PromptDelivery)
+			}
 			fmt.Println()
 
 			if agent.DefaultModel != "" {
@@ -281,7 +319,7 @@ func NewConfigAgentTestCommand(configLoader *config.Loader) *cobra.Command {
 				mergedCfg := config.Merge(globalCfg, localCfg)
 				agents = mergedCfg.Agents
 			} else {
-				agents = globalCfg.Agents
+				agents = config.ResolveAgents(globalCfg.Agents)
 			}
 
 			agent, exists := agents[agentName]
@@ -316,50 +354,71 @@ func NewConfigAgentTestCommand(configLoader *config.Loader) *cobra.Command {
 			// Check configuration
 			fmt.Println("Configuration:")
 
-			// Check command template
-			if agent.Command == "" {
-				fmt.Println("  ✗ No command template defined")
+			// Check command or args template
+			template := agent.Command
+			templateName := "Command"
+			if len(agent.Args) > 0 {
+				template = strings.Join(agent.Args, " ")
+				templateName = "Args"
+			}
+
+			if template == "" {
+				fmt.Println("  ✗ No command or args template defined")
 				hasErrors = true
 			} else {
-				fmt.Println("  ✓ Command template valid")
-
-				// Check for {prompt} placeholder
-				if !strings.Contains(agent.Command, "{prompt}") {
-					fmt.Println("  ⚠ Command template missing {prompt} placeholder")
-					hasWarnings = true
+				if len(agent.Args) > 0 && agent.Command != "" {
+					fmt.Println("  ✗ Both command and args defined (use one)")
+					hasErrors = true
+				} else if len(agent.Args) > 0 && !strings.Contains(agent.Args[0], "{bin}") {
+					fmt.Println("  ✗ First element of args must contain {bin}")
+					hasErrors = true
 				} else {
-					fmt.Println("  ✓ Contains {prompt} placeholder")
+					fmt.Printf("  ✓ %s template valid\n", templateName)
 				}
 
-				// Check for unknown placeholders
-				knownPlaceholders := []string{"{bin}", "{model}", "{role}", "{role_file}", "{prompt}", "{date}"}
-				for _, ph := range []string{"{bin}", "{model}", "{role}", "{role_file}", "{prompt}", "{date}"} {
-					if strings.Contains(agent.Command, ph) {
-						// Valid placeholder
-						continue
+				// Check the prompt placeholder for the delivery mode
+				switch agent.PromptDelivery {
+				case "", engine.PromptDeliveryArg:
+					if !strings.Contains(template, "{prompt}") {
+						fmt.Printf("  ⚠ %s template missing {prompt} placeholder\n", templateName)
+						hasWarnings = true
+					} else {
+						fmt.Println("  ✓ Contains {prompt} placeholder")
 					}
+				case engine.PromptDeliveryFile:
+					if !strings.Contains(template, "{prompt_file}") {
+						fmt.Printf("  ✗ prompt_delivery \"file\" requires {prompt_file} in %s template\n", strings.ToLower(templateName))
+						hasErrors = true
+					} else {
+						fmt.Println("  ✓ Prompt delivered via {prompt_file}")
+					}
+				case engine.PromptDeliveryStdin:
+					fmt.Println("  ✓ Prompt delivered via stdin")
+				default:
+					fmt.Printf("  ✗ Invalid prompt_delivery %q (use arg, file or stdin)\n", agent.PromptDelivery)
+					hasErrors = true
+				}
+				if agent.PromptDelivery != "" && agent.PromptDelivery != engine.PromptDeliveryArg && strings.Contains(template, "{prompt}") {
+					fmt.Printf("  ⚠ {prompt} is empty when prompt_delivery is %q\n", agent.PromptDelivery)
+					hasWarnings = true
 				}
 
-				// Look for any other placeholders
-				if strings.Contains(agent.Command, "{") {
-					parts := strings.Split(agent.Command, "{")
-					for _, part := range parts[1:] {
-						if idx := strings.Index(part, "}"); idx > 0 {
-							ph := "{" + part[:idx] + "}"
-							isKnown := false
-							for _, known := range knownPlaceholders {
-								if ph == known {
-									isKnown = true
-									break
-								}
-							}
-							if !isKnown {
-								fmt.Printf("  ⚠ Unknown placeholder %s in command template\n", ph)
-								fmt.Println("    (did you mean one of: {bin}, {model}, {role}, {role_file}, {prompt}, {date}?)")
-								hasWarnings = true
-							}
+				// Check for unknown placeholders
+				knownPlaceholders := []string{"{bin}", "{model}", "{role}", "{role_file}", "{prompt}", "{prompt_file}", "{date}"}
+				for _, ph := range findPlaceholders(template) {
+					isKnown := engine.IsBuiltinPlaceholder(ph) || strings.HasPrefix(ph, "{var:")
+					for _, known := range knownPlaceholders {
+						if ph == known {
+							isKnown = true
+							break
 						}
 					}
+					if !isKnown {
+						fmt.Printf("  ⚠ Unknown placeholder %s in %s template\n", ph, strings.ToLower(templateName))
+						fmt.Println("    (did you mean one of: {bin}, {model}, {role}, {role_file}, {prompt}, {prompt_file}, {date}?)")
+						fmt.Println("    (built-ins: {env:VAR}, {cwd}, {project_root}, {git_branch}, {git_commit}, {user}, {hostname}, {date:<layout>})")
+						hasWarnings = true
+					}
 				}
 			}
 
@@ -389,21 +448,30 @@ func NewConfigAgentTestCommand(configLoader *config.Loader) *cobra.Command {
 
 			// Preview command
 			fmt.Println("Preview command:")
-			previewCmd := agent.Command
-			previewCmd = strings.ReplaceAll(previewCmd, "{bin}", agent.Bin)
-			if agent.DefaultModel != "" {
-				if fullModel, ok := agent.Models[agent.DefaultModel]; ok {
-					previewCmd = strings.ReplaceAll(previewCmd, "{model}", fullModel)
-				} else {
-					previewCmd = strings.ReplaceAll(previewCmd, "{model}", agent.DefaultModel)
+			previewModel := agent.DefaultModel
+			if fullModel, ok := agent.Models[agent.DefaultModel]; ok {
+				previewModel = fullModel
+			}
+			previewValues := map[string]string{
+				"bin":         agent.Bin,
+				"model":       previewModel,
+				"role":        "...",
+				"role_file":   "/tmp/role.txt",
+				"prompt":      "test",
+				"prompt_file": "/tmp/prompt.md",
+				"date":        "2025-01-01T00:00:00Z",
+			}
+			resolver := engine.NewPlaceholderResolver(nil, "")
+			if len(agent.Args) > 0 {
+				previewArgs := make([]string, len(agent.Args))
+				for i, arg := range agent.Args {
+					previewArgs[i] = resolver.ResolveArg(arg, previewValues)
 				}
+				fmt.Printf("  ❯ %s\n", engine.FormatArgs(previewArgs))
+				fmt.Println("  (executed directly, no shell)")
+			} else {
+				fmt.Printf("  ❯ %s\n", resolver.Resolve(agent.Command, previewValues))
 			}
-			previewCmd = strings.ReplaceAll(previewCmd, "{role}", "...")
-			previewCmd = strings.ReplaceAll(previewCmd, "{role_file}", "/tmp/role.txt")
-			previewCmd = strings.ReplaceAll(previewCmd, "{prompt}", "test")
-			previewCmd = strings.ReplaceAll(previewCmd, "{date}", "2025-01-01T00:00:00Z")
-
-			fmt.Printf("  ❯ %s\n", previewCmd)
 			fmt.Println()
 
 			// Summary
@@ -423,3 +491,12 @@ func NewConfigAgentTestCommand(configLoader *config.Loader) *cobra.Command {
 
 	return cmd
 }
+
+// formatArgsTemplate renders an args template as a TOML-style array for display
+func formatArgsTemplate(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = fmt.Sprintf("%q", arg)
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
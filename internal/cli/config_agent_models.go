@@ -0,0 +1,121 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+
+	"github.com/grantcarthew/start/internal/config"
+	"github.com/grantcarthew/start/internal/engine"
+	"github.com/spf13/cobra"
+)
+
+// NewConfigAgentModelsCommand creates the config agent models command
+func NewConfigAgentModelsCommand(configLoader *config.Loader, modelCatalog *engine.ModelCatalog) *cobra.Command {
+	var refresh bool
+
+	cmd := &cobra.Command{
+		Use:   "models <name>",
+		Short: "List configured and discovered models",
+		Long: `List an agent's configured models and the model IDs discovered through its
+models_command or models_url. Use --refresh to discover them again.
+
+Discovered IDs are cached and used to warn when --model names an unknown model.
+
+Examples:
+  start config agent models claude
+  start config agent models aichat --refresh`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			agentName := args[0]
+			workDir, err := os.Getwd()
+			if err != nil {
+				return fmt.Errorf("failed to get working directory: %w", err)
+			}
+
+			globalCfg, err := configLoader.LoadGlobal()
+			if err != nil {
+				return fmt.Errorf("failed to load global config: %w", err)
+			}
+			cfg := globalCfg
+			cfg.Agents = config.ResolveAgents(globalCfg.Agents)
+			scope := "global"
+			if localCfg, err := configLoader.LoadLocal(workDir); err == nil {
+				cfg = config.Merge(globalCfg, localCfg)
+				if _, hasLocal := localCfg.Agents[agentName]; hasLocal {
+					scope = "local"
+				}
+			}
+
+			agent, exists := cfg.Agents[agentName]
+			if !exists {
+				return fmt.Errorf("agent '%s' not found in configuration.\n\nUse 'start config agent list' to see available agents.", agentName)
+			}
+			agent.Name = agentName
+
+			var list engine.ModelList
+			var discovered bool
+			if refresh {
+				shell, timeout := shellAndTimeout(cfg)
+				list, err = modelCatalog.Refresh(agent, shell, timeout)
+				if err != nil {
+					return fmt.Errorf("failed to refresh models: %w", err)
+				}
+				discovered = true
+			} else {
+				list, discovered = modelCatalog.Known(agentName)
+			}
+
+			fmt.Printf("Models: %s (%s)\n", agentName, scope)
+			fmt.Println("═══════════════════════════════════════════════════════════")
+			fmt.Println()
+
+			if refresh {
+				if list.Warning != "" {
+					fmt.Printf("⚠ %s\n", list.Warning)
+				}
+				fmt.Printf("✓ Refreshed %d models from %s\n", len(list.IDs), list.Source)
+				fmt.Println()
+			}
+
+			if len(agent.Models) > 0 {
+				fmt.Println("Configured:")
+				names := make([]string, 0, len(agent.Models))
+				for name := range agent.Models {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+				for _, name := range names {
+					id := agent.Models[name]
+					if discovered && !slices.Contains(list.IDs, id) {
+						fmt.Printf("  ⚠ %s = %s (not in discovered models)\n", name, id)
+						continue
+					}
+					fmt.Printf("  %s = %s\n", name, id)
+				}
+			} else {
+				fmt.Println("Configured: (none)")
+			}
+			fmt.Println()
+
+			switch {
+			case discovered:
+				fmt.Printf("Discovered (%s):\n", list.StoredAt.Format("2006-01-02 15:04"))
+				for _, id := range list.IDs {
+					fmt.Printf("  %s\n", id)
+				}
+			case agent.ModelsCommand != "" || agent.ModelsURL != "":
+				fmt.Printf("Discovered: (none, run 'start config agent models %s --refresh')\n", agentName)
+			default:
+				fmt.Println("Discovered: (none, set models_command or models_url to discover models)")
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&refresh, "refresh", false, "Discover models again through models_command or models_url")
+
+	return cmd
+}
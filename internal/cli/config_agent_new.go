@@ -117,23 +117,65 @@ func NewConfigAgentNewCommand(configLoader *config.Loader) *cobra.Command {
 			}
 			agent.Bin = bin
 
-			// Command template
-			fmt.Println("\nCommand template")
-			fmt.Println("Available placeholders: {bin}, {model}, {role}, {role_file}, {prompt}, {date}")
-			fmt.Println()
-			fmt.Println("Example for Claude:")
-			fmt.Println(`  {bin} --model {model} --append-system-prompt '{role}' '{prompt}'`)
-			fmt.Println()
-			fmt.Println("Example for Gemini (file-based role):")
-			fmt.Println(`  GEMINI_SYSTEM_MD="{role_file}" {bin} --model {model} --prompt-interactive '{prompt}'`)
-			fmt.Println()
-			fmt.Println("Important: Use single quotes around '{role}' and '{prompt}' for bash safety")
-			fmt.Println()
-			command, err := prompter.Ask("Command: ")
+			// Invocation style
+			style, err := prompter.AskChoice("\nInvocation style:", []string{
+				"command (shell template)",
+				"args (argument list, no shell)",
+			})
 			if err != nil {
 				return err
 			}
-			agent.Command = command
+
+			if style == "args (argument list, no shell)" {
+				// Args template
+				fmt.Println("\nArgs template")
+				fmt.Println("Enter one argument per line. Each argument is passed as-is, so no quoting is needed.")
+				fmt.Println("Available placeholders: {bin}, {model}, {role}, {role_file}, {prompt}, {prompt_file}, {date}")
+				fmt.Println()
+				fmt.Println("Example for Claude:")
+				fmt.Println("  {bin}")
+				fmt.Println("  --model")
+				fmt.Println("  {model}")
+				fmt.Println("  --append-system-prompt")
+				fmt.Println("  {role}")
+				fmt.Println("  {prompt}")
+				fmt.Println()
+				fmt.Println("The first argument must be {bin}. Press enter with empty line to finish.")
+				fmt.Println()
+
+				for {
+					arg, err := prompter.Ask("Arg: ")
+					if err != nil {
+						return err
+					}
+					if arg == "" {
+						if len(agent.Args) == 0 {
+							fmt.Println("At least one argument is required.")
+							continue
+						}
+						break
+					}
+					agent.Args = append(agent.Args, arg)
+				}
+			} else {
+				// Command template
+				fmt.Println("\nCommand template")
+				fmt.Println("Available placeholders: {bin}, {model}, {role}, {role_file}, {prompt}, {prompt_file}, {date}")
+				fmt.Println()
+				fmt.Println("Example for Claude:")
+				fmt.Println(`  {bin} --model {model} --append-system-prompt '{role}' '{prompt}'`)
+				fmt.Println()
+				fmt.Println("Example for Gemini (file-based role):")
+				fmt.Println(`  GEMINI_SYSTEM_MD="{role_file}" {bin} --model {model} --prompt-interactive '{prompt}'`)
+				fmt.Println()
+				fmt.Println("Important: Use single quotes around '{role}' and '{prompt}' for bash safety")
+				fmt.Println()
+				command, err := prompter.Ask("Command: ")
+				if err != nil {
+					return err
+				}
+				agent.Command = command
+			}
 
 			// URL (optional)
 			url, err := prompter.AskOptional("\nURL")
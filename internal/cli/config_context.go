@@ -9,6 +9,7 @@ import (
 
 	"github.com/grantcarthew/start/internal/config"
 	"github.com/grantcarthew/start/internal/domain"
+	"github.com/grantcarthew/start/internal/engine"
 	"github.com/spf13/cobra"
 )
 
@@ -105,13 +106,19 @@ func NewConfigContextListCommand(configLoader *config.Loader) *cobra.Command {
 					if ctx.Description != "" {
 						fmt.Printf("    %s\n", ctx.Description)
 					}
+					if len(ctx.Tags) > 0 {
+						fmt.Printf("    Tags: %s\n", strings.Join(ctx.Tags, ", "))
+					}
 					if ctx.File != "" {
 						fmt.Printf("    File: %s\n", ctx.File)
 					}
 					if ctx.Command != "" {
 						fmt.Printf("    Command: %s\n", ctx.Command)
 					}
-					if ctx.Prompt != "" && ctx.File == "" && ctx.Command == "" {
+					if ctx.URL != "" {
+						fmt.Printf("    URL: %s\n", ctx.URL)
+					}
+					if ctx.Prompt != "" && ctx.File == "" && ctx.Command == "" && ctx.URL == "" {
 						// Show prompt preview for inline prompts
 						preview := ctx.Prompt
 						if len(preview) > 60 {
@@ -131,13 +138,19 @@ func NewConfigContextListCommand(configLoader *config.Loader) *cobra.Command {
 					if ctx.Description != "" {
 						fmt.Printf("    %s\n", ctx.Description)
 					}
+					if len(ctx.Tags) > 0 {
+						fmt.Printf("    Tags: %s\n", strings.Join(ctx.Tags, ", "))
+					}
 					if ctx.File != "" {
 						fmt.Printf("    File: %s\n", ctx.File)
 					}
 					if ctx.Command != "" {
 						fmt.Printf("    Command: %s\n", ctx.Command)
 					}
-					if ctx.Prompt != "" && ctx.File == "" && ctx.Command == "" {
+					if ctx.URL != "" {
+						fmt.Printf("    URL: %s\n", ctx.URL)
+					}
+					if ctx.Prompt != "" && ctx.File == "" && ctx.Command == "" && ctx.URL == "" {
 						preview := ctx.Prompt
 						if len(preview) > 60 {
 							preview = preview[:57] + "..."
@@ -222,6 +235,9 @@ func NewConfigContextShowCommand(configLoader *config.Loader) *cobra.Command {
 				fmt.Printf("Description: %s\n", ctx.Description)
 			}
 			fmt.Printf("Required: %v\n", ctx.Required)
+			if len(ctx.Tags) > 0 {
+				fmt.Printf("Tags: %s\n", strings.Join(ctx.Tags, ", "))
+			}
 			fmt.Println()
 
 			sourceType := getContextSourceType(ctx)
@@ -236,7 +252,14 @@ func NewConfigContextShowCommand(configLoader *config.Loader) *cobra.Command {
 				resolved := resolvePath(ctx.File, workDir)
 				fmt.Printf("  Resolved: %s\n", resolved)
 
-				if fileInfo, err := os.Stat(resolved); err == nil {
+				matcher := engine.NewFileMatcher(configLoader.GetFS(), workDir)
+				if matcher.IsMulti(ctx.File) {
+					if matches, _ := matcher.Match(ctx.File); len(matches) > 0 {
+						fmt.Printf("  ✓ %d files match\n", len(matches))
+					} else {
+						fmt.Println("  ✗ No files match")
+					}
+				} else if fileInfo, err := os.Stat(resolved); err == nil {
 					fmt.Printf("  ✓ File exists (%.1f KB)\n", float64(fileInfo.Size())/1024)
 				} else {
 					fmt.Println("  ✗ File not found")
@@ -257,9 +280,18 @@ func NewConfigContextShowCommand(configLoader *config.Loader) *cobra.Command {
 					fmt.Println("  Timeout: (default)")
 				}
 				fmt.Printf("  Command: %s\n", ctx.Command)
+				printCacheSettings(ctx.CacheTTL, ctx.CacheKeyFiles)
+				fmt.Println()
+			}
+
+			if ctx.URL != "" {
+				fmt.Println("URL:")
+				fmt.Printf("  %s\n", ctx.URL)
 				fmt.Println()
 			}
 
+			printWhen(ctx.When)
+
 			if ctx.Prompt != "" {
 				fmt.Println("Prompt template:")
 				// Show full prompt
@@ -350,7 +382,38 @@ func NewConfigContextTestCommand(configLoader *config.Loader) *cobra.Command {
 				resolved := resolvePath(ctx.File, workDir)
 				fmt.Printf("  Resolved: %s\n", resolved)
 
-				if fileInfo, err := os.Stat(resolved); err == nil {
+				matcher := engine.NewFileMatcher(configLoader.GetFS(), workDir)
+				if matcher.IsMulti(ctx.File) {
+					matches, err := matcher.Match(ctx.File)
+					if err != nil {
+						fmt.Printf("  ⚠ %v\n", err)
+					}
+					if len(matches) == 0 {
+						fmt.Println("  ✗ No files match")
+						if ctx.Required {
+							hasErrors = true
+						} else {
+							hasWarnings = true
+						}
+					} else {
+						fmt.Printf("  ✓ %d files match (.gitignore applied)\n", len(matches))
+						for i, match := range matches {
+							if i == 10 {
+								fmt.Printf("    ... (%d more)\n", len(matches)-i)
+								break
+							}
+							fmt.Printf("    %s\n", matcher.DisplayPath(match))
+						}
+						maxFiles := ctx.MaxFiles
+						if maxFiles == 0 {
+							maxFiles = engine.DefaultMaxFiles
+						}
+						if len(matches) > maxFiles {
+							fmt.Printf("  ⚠ Only the first %d files are included (max_files)\n", maxFiles)
+							hasWarnings = true
+						}
+					}
+				} else if fileInfo, err := os.Stat(resolved); err == nil {
 					fmt.Printf("  ✓ File exists (%.1f KB)\n", float64(fileInfo.Size())/1024)
 				} else {
 					fmt.Println("  ✗ File not found")
@@ -400,9 +463,9 @@ func NewConfigContextTestCommand(configLoader *config.Loader) *cobra.Command {
 					fmt.Printf("  ✓ Uses placeholders: %s\n", strings.Join(placeholders, ", "))
 
 					// Validate placeholders
-					validPlaceholders := []string{"{file}", "{file_contents}", "{command}", "{command_output}", "{date}"}
+					validPlaceholders := []string{"{file}", "{file_contents}", "{command}", "{command_output}", "{url}", "{url_contents}", "{date}"}
 					for _, ph := range placeholders {
-						isValid := false
+						isValid := engine.IsBuiltinPlaceholder(ph) || strings.HasPrefix(ph, "{var:")
 						for _, valid := range validPlaceholders {
 							if ph == valid {
 								isValid = true
@@ -411,7 +474,8 @@ func NewConfigContextTestCommand(configLoader *config.Loader) *cobra.Command {
 						}
 						if !isValid {
 							fmt.Printf("  ⚠ Unknown placeholder %s\n", ph)
-							fmt.Println("    Valid: {file}, {file_contents}, {command}, {command_output}, {date}")
+							fmt.Println("    Valid: {file}, {file_contents}, {command}, {command_output}, {url}, {url_contents}, {date}")
+							fmt.Println("    Built-ins: {env:VAR}, {cwd}, {project_root}, {git_branch}, {git_commit}, {user}, {hostname}, {date:<layout>}")
 							hasWarnings = true
 						}
 					}
@@ -433,6 +497,14 @@ func NewConfigContextTestCommand(configLoader *config.Loader) *cobra.Command {
 							fmt.Println("  ✓ Uses {command} placeholder (matches command field)")
 						}
 					}
+					if strings.Contains(ctx.Prompt, "{url}") || strings.Contains(ctx.Prompt, "{url_contents}") {
+						if ctx.URL == "" {
+							fmt.Println("  ⚠ Prompt uses {url} or {url_contents} but no url configured")
+							hasWarnings = true
+						} else {
+							fmt.Println("  ✓ Uses {url} placeholder (matches url field)")
+						}
+					}
 				} else {
 					fmt.Printf("  ✓ Valid inline prompt (%d characters)\n", len(ctx.Prompt))
 				}
@@ -440,9 +512,9 @@ func NewConfigContextTestCommand(configLoader *config.Loader) *cobra.Command {
 			}
 
 			// Check UTD requirement
-			if ctx.File == "" && ctx.Command == "" && ctx.Prompt == "" {
+			if ctx.File == "" && ctx.Command == "" && ctx.URL == "" && ctx.Prompt == "" {
 				fmt.Println("✗ No content source defined")
-				fmt.Println("  At least one field required: file, command, or prompt")
+				fmt.Println("  At least one field required: file, command, url, or prompt")
 				hasErrors = true
 				fmt.Println()
 			}
@@ -484,8 +556,46 @@ func getContextSourceType(ctx domain.Context) string {
 		return "File only"
 	} else if hasCommand {
 		return "Command only"
+	} else if ctx.URL != "" && hasPrompt {
+		return "URL with template"
+	} else if ctx.URL != "" {
+		return "URL only"
 	} else if hasPrompt {
 		return "Inline prompt"
 	}
 	return "Invalid (no UTD fields)"
 }
+
+// printWhen prints the when predicates of a role, context or task
+func printWhen(when *domain.When) {
+	if when == nil {
+		return
+	}
+	fmt.Println("When:")
+	for _, p := range []struct{ key, value string }{
+		{"file_exists", when.FileExists},
+		{"dir_exists", when.DirExists},
+		{"env_set", when.EnvSet},
+		{"command_succeeds", when.CommandSucceeds},
+		{"path_glob", when.PathGlob},
+	} {
+		if p.value != "" {
+			fmt.Printf("  %s = %s\n", p.key, p.value)
+		}
+	}
+	if when.GitRepo != nil {
+		fmt.Printf("  git_repo = %t\n", *when.GitRepo)
+	}
+	fmt.Println()
+}
+
+// printCacheSettings prints command output cache settings of a UTD section
+func printCacheSettings(cacheTTL string, keyFiles []string) {
+	if cacheTTL == "" {
+		return
+	}
+	fmt.Printf("  Cache TTL: %s\n", cacheTTL)
+	if len(keyFiles) > 0 {
+		fmt.Printf("  Cache key files: %s\n", strings.Join(keyFiles, ", "))
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
 	"strings"
 
 	"github.com/grantcarthew/start/internal/config"
@@ -312,7 +313,7 @@ func NewConfigContextEditCommand(configLoader *config.Loader) *cobra.Command {
 			}
 
 			// Check if anything changed
-			if updatedContext == existingContext {
+			if reflect.DeepEqual(updatedContext, existingContext) {
 				fmt.Println()
 				fmt.Println("No changes detected.")
 				fmt.Println()
@@ -5,16 +5,16 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
-	"sort"
 	"strings"
 
 	"github.com/grantcarthew/start/internal/config"
 	"github.com/grantcarthew/start/internal/domain"
+	"github.com/grantcarthew/start/internal/engine"
 	"github.com/spf13/cobra"
 )
 
 // NewConfigRoleCommand creates the config role command
-func NewConfigRoleCommand(configLoader *config.Loader, validator *config.Validator) *cobra.Command {
+func NewConfigRoleCommand(configLoader *config.Loader, validator *config.Validator, roleLoader *engine.RoleLoader) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "role",
 		Short: "Manage role configurations",
@@ -23,7 +23,7 @@ func NewConfigRoleCommand(configLoader *config.Loader, validator *config.Validat
 
 	// Add subcommands
 	cmd.AddCommand(NewConfigRoleListCommand(configLoader))
-	cmd.AddCommand(NewConfigRoleShowCommand(configLoader))
+	cmd.AddCommand(NewConfigRoleShowCommand(configLoader, roleLoader))
 	cmd.AddCommand(NewConfigRoleTestCommand(configLoader))
 	cmd.AddCommand(NewConfigRoleNewCommand(configLoader))
 	cmd.AddCommand(NewConfigRoleEditCommand(configLoader))
@@ -48,6 +48,7 @@ func NewConfigRoleListCommand(configLoader *config.Loader) *cobra.Command {
 			}
 
 			var roles map[string]domain.Role
+			var order []string
 			var scope string
 
 			if localOnly {
@@ -57,6 +58,7 @@ func NewConfigRoleListCommand(configLoader *config.Loader) *cobra.Command {
 					return fmt.Errorf("failed to load local config: %w", err)
 				}
 				roles = localCfg.Roles
+				order = localCfg.RoleOrder
 				scope = "local"
 			} else {
 				// Load and merge global + local
@@ -69,9 +71,11 @@ func NewConfigRoleListCommand(configLoader *config.Loader) *cobra.Command {
 				if err == nil {
 					mergedCfg := config.Merge(globalCfg, localCfg)
 					roles = mergedCfg.Roles
+					order = mergedCfg.RoleOrder
 					scope = "merged"
 				} else {
 					roles = globalCfg.Roles
+					order = globalCfg.RoleOrder
 					scope = "global"
 				}
 			}
@@ -84,12 +88,8 @@ func NewConfigRoleListCommand(configLoader *config.Loader) *cobra.Command {
 				return nil
 			}
 
-			// Sort roles by name for consistent output
-			names := make([]string, 0, len(roles))
-			for name := range roles {
-				names = append(names, name)
-			}
-			sort.Strings(names)
+			// Definition order, explicit order first
+			names := orderedNames(roles, order)
 
 			fmt.Printf("Configured roles (%s):\n", scope)
 			fmt.Println("═══════════════════════════════════════════════════════════")
@@ -111,6 +111,12 @@ func NewConfigRoleListCommand(configLoader *config.Loader) *cobra.Command {
 				if role.Command != "" {
 					fmt.Printf("  Command: %s\n", role.Command)
 				}
+				if role.URL != "" {
+					fmt.Printf("  URL: %s\n", role.URL)
+				}
+				if len(role.Extends) > 0 {
+					fmt.Printf("  Extends: %s\n", strings.Join(role.Extends, ", "))
+				}
 
 				fmt.Println()
 			}
@@ -125,7 +131,7 @@ func NewConfigRoleListCommand(configLoader *config.Loader) *cobra.Command {
 }
 
 // NewConfigRoleShowCommand creates the config role show command
-func NewConfigRoleShowCommand(configLoader *config.Loader) *cobra.Command {
+func NewConfigRoleShowCommand(configLoader *config.Loader, roleLoader *engine.RoleLoader) *cobra.Command {
 	var localOnly bool
 
 	cmd := &cobra.Command{
@@ -140,7 +146,7 @@ func NewConfigRoleShowCommand(configLoader *config.Loader) *cobra.Command {
 				return fmt.Errorf("failed to get working directory: %w", err)
 			}
 
-			var roles map[string]domain.Role
+			var cfg domain.Config
 			var scope string
 
 			if localOnly {
@@ -148,7 +154,7 @@ func NewConfigRoleShowCommand(configLoader *config.Loader) *cobra.Command {
 				if err != nil {
 					return fmt.Errorf("failed to load local config: %w", err)
 				}
-				roles = localCfg.Roles
+				cfg = localCfg
 				scope = "local"
 			} else {
 				globalCfg, err := configLoader.LoadGlobal()
@@ -158,8 +164,7 @@ func NewConfigRoleShowCommand(configLoader *config.Loader) *cobra.Command {
 
 				localCfg, err := configLoader.LoadLocal(workDir)
 				if err == nil {
-					mergedCfg := config.Merge(globalCfg, localCfg)
-					roles = mergedCfg.Roles
+					cfg = config.Merge(globalCfg, localCfg)
 
 					// Determine scope
 					if _, hasLocal := localCfg.Roles[roleName]; hasLocal {
@@ -168,12 +173,12 @@ func NewConfigRoleShowCommand(configLoader *config.Loader) *cobra.Command {
 						scope = "global"
 					}
 				} else {
-					roles = globalCfg.Roles
+					cfg = globalCfg
 					scope = "global"
 				}
 			}
 
-			role, exists := roles[roleName]
+			role, exists := cfg.Roles[roleName]
 			if !exists {
 				return fmt.Errorf("role '%s' not found in configuration.\n\nUse 'start config role list' to see available roles.", roleName)
 			}
@@ -217,9 +222,18 @@ func NewConfigRoleShowCommand(configLoader *config.Loader) *cobra.Command {
 				if role.CommandTimeout > 0 {
 					fmt.Printf("  Timeout: %d seconds\n", role.CommandTimeout)
 				}
+				printCacheSettings(role.CacheTTL, role.CacheKeyFiles)
 				fmt.Println()
 			}
 
+			if role.URL != "" {
+				fmt.Println("URL:")
+				fmt.Printf("  %s\n", role.URL)
+				fmt.Println()
+			}
+
+			printWhen(role.When)
+
 			if role.Prompt != "" {
 				fmt.Println("Prompt template:")
 				// Show first few lines
@@ -238,6 +252,40 @@ func NewConfigRoleShowCommand(configLoader *config.Loader) *cobra.Command {
 				fmt.Println()
 			}
 
+			// Roles built from parents show the chain and the combined content
+			if len(role.Extends) > 0 {
+				role.Name = roleName
+				chain, err := engine.RoleChain(role, cfg.Roles)
+				if err != nil {
+					fmt.Printf("✗ %v\n", err)
+					return nil
+				}
+				names := make([]string, len(chain))
+				for i, r := range chain {
+					names[i] = r.Name
+				}
+				fmt.Printf("Extends: %s\n", strings.Join(role.Extends, ", "))
+				fmt.Printf("Chain: %s\n", strings.Join(names, " → "))
+				fmt.Println()
+
+				shell, timeout := shellAndTimeout(cfg)
+				loaded, err := roleLoader.LoadRole(role, cfg.Roles, shell, timeout, cfg.Variables)
+				if err != nil {
+					fmt.Printf("✗ %v\n", err)
+					return nil
+				}
+				defer roleLoader.CleanupRole(loaded)
+
+				fmt.Println("Flattened content:")
+				fmt.Println("─────────────────────────────────────────────────")
+				fmt.Println(loaded.Content)
+				fmt.Println("─────────────────────────────────────────────────")
+				for _, w := range loaded.Warnings {
+					fmt.Printf("⚠ %s\n", w)
+				}
+				fmt.Println()
+			}
+
 			return nil
 		},
 	}
@@ -361,9 +409,9 @@ func NewConfigRoleTestCommand(configLoader *config.Loader) *cobra.Command {
 					fmt.Printf("  ✓ Uses placeholders: %s\n", strings.Join(placeholders, ", "))
 
 					// Validate placeholders
-					validPlaceholders := []string{"{file}", "{file_contents}", "{command}", "{command_output}", "{date}"}
+					validPlaceholders := []string{"{file}", "{file_contents}", "{command}", "{command_output}", "{url}", "{url_contents}", "{date}"}
 					for _, ph := range placeholders {
-						isValid := false
+						isValid := engine.IsBuiltinPlaceholder(ph) || strings.HasPrefix(ph, "{var:")
 						for _, valid := range validPlaceholders {
 							if ph == valid {
 								isValid = true
@@ -372,7 +420,8 @@ func NewConfigRoleTestCommand(configLoader *config.Loader) *cobra.Command {
 						}
 						if !isValid {
 							fmt.Printf("  ⚠ Unknown placeholder %s\n", ph)
-							fmt.Println("    Valid: {file}, {file_contents}, {command}, {command_output}, {date}")
+							fmt.Println("    Valid: {file}, {file_contents}, {command}, {command_output}, {url}, {url_contents}, {date}")
+							fmt.Println("    Built-ins: {env:VAR}, {cwd}, {project_root}, {git_branch}, {git_commit}, {user}, {hostname}, {date:<layout>}")
 							hasWarnings = true
 						}
 					}
@@ -386,16 +435,36 @@ func NewConfigRoleTestCommand(configLoader *config.Loader) *cobra.Command {
 						fmt.Println("  ⚠ Prompt uses {command_output} but no command configured")
 						hasWarnings = true
 					}
+					if strings.Contains(role.Prompt, "{url_contents}") && role.URL == "" {
+						fmt.Println("  ⚠ Prompt uses {url_contents} but no url configured")
+						hasWarnings = true
+					}
 				} else {
 					fmt.Println("  ✓ Valid prompt (no placeholders)")
 				}
 				fmt.Println()
 			}
 
+			// Check parent roles
+			if len(role.Extends) > 0 {
+				role.Name = roleName
+				if chain, err := engine.RoleChain(role, roles); err != nil {
+					fmt.Printf("✗ %v\n", err)
+					hasErrors = true
+				} else {
+					names := make([]string, len(chain))
+					for i, r := range chain {
+						names[i] = r.Name
+					}
+					fmt.Printf("✓ Inheritance chain: %s\n", strings.Join(names, " → "))
+				}
+				fmt.Println()
+			}
+
 			// Check UTD requirement
-			if role.File == "" && role.Command == "" && role.Prompt == "" {
+			if role.File == "" && role.Command == "" && role.URL == "" && role.Prompt == "" && len(role.Extends) == 0 {
 				fmt.Println("✗ No content source defined")
-				fmt.Println("  At least one field required: file, command, or prompt")
+				fmt.Println("  At least one field required: file, command, url, prompt, or extends")
 				hasErrors = true
 			}
 
@@ -436,8 +505,14 @@ func getRoleSourceType(role domain.Role) string {
 		return "File only"
 	} else if hasCommand {
 		return "Command only"
+	} else if role.URL != "" && hasPrompt {
+		return "URL with template"
+	} else if role.URL != "" {
+		return "URL only"
 	} else if hasPrompt {
 		return "Inline prompt"
+	} else if len(role.Extends) > 0 {
+		return "Extends only"
 	}
 	return "Invalid (no UTD fields)"
 }
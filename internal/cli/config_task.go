@@ -4,11 +4,11 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
-	"sort"
 	"strings"
 
 	"github.com/grantcarthew/start/internal/config"
 	"github.com/grantcarthew/start/internal/domain"
+	"github.com/grantcarthew/start/internal/engine"
 	"github.com/spf13/cobra"
 )
 
@@ -46,6 +46,7 @@ func NewConfigTaskListCommand(configLoader *config.Loader) *cobra.Command {
 			}
 
 			var tasks map[string]domain.Task
+			var order []string
 			var scope string
 
 			if localOnly {
@@ -55,6 +56,7 @@ func NewConfigTaskListCommand(configLoader *config.Loader) *cobra.Command {
 					return fmt.Errorf("failed to load local config: %w", err)
 				}
 				tasks = localCfg.Tasks
+				order = localCfg.TaskOrder
 				scope = "local"
 			} else {
 				// Load and merge global + local
@@ -63,13 +65,15 @@ func NewConfigTaskListCommand(configLoader *config.Loader) *cobra.Command {
 					return fmt.Errorf("failed to load global config: %w", err)
 				}
 
+				// Effective tasks, with inherited fields from extends
 				localCfg, err := configLoader.LoadLocal(workDir)
 				if err == nil {
-					mergedCfg := config.Merge(globalCfg, localCfg)
-					tasks = mergedCfg.Tasks
+					tasks = engine.NewTaskResolver().ListAllTasks(localCfg.Tasks, globalCfg.Tasks)
+					order = config.Merge(globalCfg, localCfg).TaskOrder
 					scope = "merged"
 				} else {
-					tasks = globalCfg.Tasks
+					tasks = engine.NewTaskResolver().ListAllTasks(nil, globalCfg.Tasks)
+					order = globalCfg.TaskOrder
 					scope = "global"
 				}
 			}
@@ -82,12 +86,8 @@ func NewConfigTaskListCommand(configLoader *config.Loader) *cobra.Command {
 				return nil
 			}
 
-			// Sort tasks by name for consistent output
-			names := make([]string, 0, len(tasks))
-			for name := range tasks {
-				names = append(names, name)
-			}
-			sort.Strings(names)
+			// Definition order, explicit order first
+			names := orderedNames(tasks, order)
 
 			fmt.Printf("Configured tasks (%s):\n", scope)
 			fmt.Println("═══════════════════════════════════════════════════════════")
@@ -120,6 +120,10 @@ func NewConfigTaskListCommand(configLoader *config.Loader) *cobra.Command {
 					fmt.Printf("  Agent: %s\n", task.Agent)
 				}
 
+				if task.Extends != "" {
+					fmt.Printf("  Extends: %s\n", task.Extends)
+				}
+
 				// Task type
 				sourceType := getTaskSourceType(task)
 				fmt.Printf("  Task: %s\n", sourceType)
@@ -154,6 +158,7 @@ func NewConfigTaskShowCommand(configLoader *config.Loader) *cobra.Command {
 
 			var tasks map[string]domain.Task
 			var scope string
+			var localTasks, globalTasks map[string]domain.Task
 
 			if localOnly {
 				localCfg, err := configLoader.LoadLocal(workDir)
@@ -167,11 +172,13 @@ func NewConfigTaskShowCommand(configLoader *config.Loader) *cobra.Command {
 				if err != nil {
 					return fmt.Errorf("failed to load global config: %w", err)
 				}
+				globalTasks = globalCfg.Tasks
 
 				localCfg, err := configLoader.LoadLocal(workDir)
 				if err == nil {
 					mergedCfg := config.Merge(globalCfg, localCfg)
 					tasks = mergedCfg.Tasks
+					localTasks = localCfg.Tasks
 
 					// Determine scope
 					if _, hasLocal := localCfg.Tasks[taskName]; hasLocal {
@@ -193,11 +200,32 @@ func NewConfigTaskShowCommand(configLoader *config.Loader) *cobra.Command {
 				return fmt.Errorf("task not found")
 			}
 
+			// Merged views show the effective task, local only shows it as written
+			var extendsErr error
+			if task.Extends != "" && !localOnly {
+				task.Name = taskName
+				if effective, err := engine.NewTaskResolver().Effective(task, scope == "local", localTasks, globalTasks); err != nil {
+					extendsErr = err
+				} else {
+					task = effective
+				}
+			}
+
 			// Display task configuration
 			fmt.Printf("Task configuration: %s (%s)\n", taskName, scope)
 			fmt.Println("═══════════════════════════════════════════════════════════")
 			fmt.Println()
 
+			if task.Extends != "" {
+				fmt.Printf("Extends: %s\n", task.Extends)
+				if extendsErr != nil {
+					fmt.Printf("  ✗ %v\n", extendsErr)
+				} else if !localOnly {
+					fmt.Println("  (effective task, inherited fields included)")
+				}
+				fmt.Println()
+			}
+
 			if task.Alias != "" {
 				fmt.Printf("Alias: %s\n", task.Alias)
 			}
@@ -217,6 +245,9 @@ func NewConfigTaskShowCommand(configLoader *config.Loader) *cobra.Command {
 			} else {
 				fmt.Println("Agent: (default)")
 			}
+			if len(task.Contexts) > 0 {
+				fmt.Printf("Contexts: %s (plus required)\n", strings.Join(task.Contexts, ", "))
+			}
 			fmt.Println()
 
 			sourceType := getTaskSourceType(task)
@@ -252,9 +283,18 @@ func NewConfigTaskShowCommand(configLoader *config.Loader) *cobra.Command {
 					fmt.Println("  Timeout: (default)")
 				}
 				fmt.Printf("  Command: %s\n", task.Command)
+				printCacheSettings(task.CacheTTL, task.CacheKeyFiles)
+				fmt.Println()
+			}
+
+			if task.URL != "" {
+				fmt.Println("URL:")
+				fmt.Printf("  %s\n", task.URL)
 				fmt.Println()
 			}
 
+			printWhen(task.When)
+
 			if task.Prompt != "" {
 				fmt.Println("Prompt template:")
 				// Show full prompt
@@ -326,12 +366,30 @@ func NewConfigTaskTestCommand(configLoader *config.Loader) *cobra.Command {
 				return fmt.Errorf("task not found")
 			}
 
+			hasErrors := false
+			hasWarnings := false
+
+			// Test the effective task, with inherited fields from extends
+			var extendsErr error
+			if task.Extends != "" {
+				task.Name = taskName
+				if effective, err := engine.NewTaskResolver().Effective(task, scope == "local", localCfg.Tasks, globalCfg.Tasks); err != nil {
+					extendsErr = err
+					hasErrors = true
+				} else {
+					task = effective
+				}
+			}
+
 			fmt.Printf("Testing task: %s\n", taskName)
 			fmt.Println("─────────────────────────────────────────────────")
 			fmt.Println()
 
 			fmt.Println("Configuration:")
 			fmt.Printf("  Scope: %s\n", scope)
+			if task.Extends != "" {
+				fmt.Printf("  Extends: %s\n", task.Extends)
+			}
 			if task.Alias != "" {
 				fmt.Printf("  Alias: %s\n", task.Alias)
 			}
@@ -351,8 +409,10 @@ func NewConfigTaskTestCommand(configLoader *config.Loader) *cobra.Command {
 			fmt.Printf("  Type: %s\n", getTaskSourceType(task))
 			fmt.Println()
 
-			hasErrors := false
-			hasWarnings := false
+			if extendsErr != nil {
+				fmt.Printf("✗ %v\n", extendsErr)
+				fmt.Println()
+			}
 
 			// Check file availability
 			if task.File != "" {
@@ -407,9 +467,16 @@ func NewConfigTaskTestCommand(configLoader *config.Loader) *cobra.Command {
 					fmt.Printf("  ✓ Uses placeholders: %s\n", strings.Join(placeholders, ", "))
 
 					// Validate placeholders
-					validPlaceholders := []string{"{file}", "{file_contents}", "{command}", "{command_output}", "{instructions}", "{date}"}
+					validPlaceholders := []string{"{file}", "{file_contents}", "{command}", "{command_output}", "{url}", "{url_contents}", "{instructions}", "{date}"}
 					for _, ph := range placeholders {
-						isValid := false
+						isValid := engine.IsBuiltinPlaceholder(ph) || strings.HasPrefix(ph, "{var:")
+						if name, ok := strings.CutPrefix(ph, "{param:"); ok {
+							if _, declared := task.Params[strings.TrimSuffix(name, "}")]; !declared {
+								fmt.Printf("  ⚠ Undeclared parameter %s\n", ph)
+								hasWarnings = true
+							}
+							continue
+						}
 						for _, valid := range validPlaceholders {
 							if ph == valid {
 								isValid = true
@@ -418,7 +485,8 @@ func NewConfigTaskTestCommand(configLoader *config.Loader) *cobra.Command {
 						}
 						if !isValid {
 							fmt.Printf("  ⚠ Unknown placeholder %s\n", ph)
-							fmt.Println("    Valid: {file}, {file_contents}, {command}, {command_output}, {instructions}, {date}")
+							fmt.Println("    Valid: {file}, {file_contents}, {command}, {command_output}, {url}, {url_contents}, {instructions}, {date}")
+							fmt.Println("    Built-ins: {env:VAR}, {cwd}, {project_root}, {git_branch}, {git_commit}, {user}, {hostname}, {date:<layout>}")
 							hasWarnings = true
 						}
 					}
@@ -442,16 +510,56 @@ func NewConfigTaskTestCommand(configLoader *config.Loader) *cobra.Command {
 							hasWarnings = true
 						}
 					}
+					if strings.Contains(task.Prompt, "{url}") || strings.Contains(task.Prompt, "{url_contents}") {
+						if task.URL == "" {
+							fmt.Println("  ⚠ Prompt uses {url} or {url_contents} but no url configured")
+							hasWarnings = true
+						}
+					}
 				} else {
 					fmt.Printf("  ✓ Valid inline prompt (%d characters)\n", len(task.Prompt))
 				}
 				fmt.Println()
 			}
 
+			// Show parameters and the prompt rendered with sample values
+			if len(task.Params) > 0 {
+				samples := engine.SampleParams(task)
+				fmt.Println("Parameters:")
+				for _, name := range engine.ParamNames(task) {
+					fmt.Printf("  %s (sample: %s)\n", engine.DescribeParam(name, task.Params[name]), samples[name])
+				}
+				fmt.Println()
+
+				if task.Prompt != "" {
+					sample, _ := engine.ExpandParams(task.Prompt, samples)
+					fmt.Println("Sample prompt:")
+					for _, line := range strings.Split(sample, "\n") {
+						fmt.Printf("  %s\n", line)
+					}
+					fmt.Println()
+				}
+			}
+
+			// Pipeline steps
+			if len(task.Steps) > 0 {
+				fmt.Println("Steps:")
+				for i, step := range task.Steps {
+					fmt.Printf("  %d. %s\n", i+1, describeStep(step))
+					if step.Task != "" {
+						if _, ok := tasks[step.Task]; !ok {
+							fmt.Printf("     ✗ Task '%s' not found\n", step.Task)
+							hasErrors = true
+						}
+					}
+				}
+				fmt.Println()
+			}
+
 			// Check UTD requirement
-			if task.File == "" && task.Command == "" && task.Prompt == "" {
+			if task.File == "" && task.Command == "" && task.URL == "" && task.Prompt == "" && len(task.Steps) == 0 {
 				fmt.Println("✗ No task prompt defined")
-				fmt.Println("  At least one field required: file, command, or prompt")
+				fmt.Println("  At least one field required: file, command, url, or prompt")
 				hasErrors = true
 				fmt.Println()
 			}
@@ -476,6 +584,10 @@ func NewConfigTaskTestCommand(configLoader *config.Loader) *cobra.Command {
 
 // getTaskSourceType returns a human-readable source type for a task
 func getTaskSourceType(task domain.Task) string {
+	if len(task.Steps) > 0 {
+		return fmt.Sprintf("Pipeline (%d steps)", len(task.Steps))
+	}
+
 	hasFile := task.File != ""
 	hasCommand := task.Command != ""
 	hasPrompt := task.Prompt != ""
@@ -492,6 +604,10 @@ func getTaskSourceType(task domain.Task) string {
 		return "File only"
 	} else if hasCommand {
 		return "Command only"
+	} else if task.URL != "" && hasPrompt {
+		return "URL with template"
+	} else if task.URL != "" {
+		return "URL only"
 	} else if hasPrompt {
 		return "Inline prompt"
 	}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"sort"
 	"strings"
 
@@ -382,7 +383,7 @@ func NewConfigTaskEditCommand(configLoader *config.Loader) *cobra.Command {
 			}
 
 			// Check if anything changed
-			if updatedTask == existingTask {
+			if reflect.DeepEqual(updatedTask, existingTask) {
 				fmt.Println()
 				fmt.Println("No changes detected.")
 				fmt.Println()
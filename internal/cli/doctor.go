@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/grantcarthew/start/internal/config"
+	"github.com/grantcarthew/start/internal/engine"
 	"github.com/grantcarthew/start/internal/version"
 	"github.com/spf13/cobra"
 )
@@ -374,42 +375,67 @@ func (dc *DoctorCommand) checkContexts() []string {
 	requiredCount := 0
 	optionalCount := 0
 
+	workDir, err := os.Getwd()
+	if err != nil {
+		workDir = "."
+	}
+	matcher := engine.NewFileMatcher(dc.configLoader.GetFS(), workDir)
+
 	for name, ctx := range cfg.Contexts {
 		if ctx.File == "" {
 			continue
 		}
 
-		// Expand home directory
-		path := ctx.File
-		if strings.HasPrefix(path, "~/") {
-			home, _ := os.UserHomeDir()
-			path = filepath.Join(home, path[2:])
+		// Conditional contexts are often missing on purpose, when decides at load time
+		if ctx.When != nil {
+			if !dc.quiet && dc.verbose {
+				fmt.Printf("  - %s (conditional) - %s\n", name, ctx.File)
+			}
+			continue
 		}
 
-		_, err := os.Stat(path)
-		exists := err == nil
+		// Globs and directories must match at least one file
+		var exists bool
+		missing := "File not found"
+		found := ctx.File
+		multi := matcher.IsMulti(ctx.File)
+		if multi {
+			matches, _ := matcher.Match(ctx.File)
+			exists = len(matches) > 0
+			missing = "No files match"
+			found = fmt.Sprintf("%s (%d files)", ctx.File, len(matches))
+		} else {
+			// Expand home directory
+			path := ctx.File
+			if strings.HasPrefix(path, "~/") {
+				home, _ := os.UserHomeDir()
+				path = filepath.Join(home, path[2:])
+			}
+			_, err := os.Stat(path)
+			exists = err == nil
+		}
 
 		if ctx.Required {
 			requiredCount++
 			if !exists {
 				if !dc.quiet {
-					fmt.Printf("  ✗ %s (required) - File not found: %s\n", name, ctx.File)
+					fmt.Printf("  ✗ %s (required) - %s: %s\n", name, missing, ctx.File)
 				}
 				warnings = append(warnings, fmt.Sprintf("Required context '%s' file not found", name))
 			} else {
-				if !dc.quiet && dc.verbose {
-					fmt.Printf("  ✓ %s (required) - %s\n", name, ctx.File)
+				if !dc.quiet && (dc.verbose || multi) {
+					fmt.Printf("  ✓ %s (required) - %s\n", name, found)
 				}
 			}
 		} else {
 			optionalCount++
 			if !exists {
 				if !dc.quiet && dc.verbose {
-					fmt.Printf("  ⚠ %s (optional) - File not found: %s\n", name, ctx.File)
+					fmt.Printf("  ⚠ %s (optional) - %s: %s\n", name, missing, ctx.File)
 				}
 			} else {
-				if !dc.quiet && dc.verbose {
-					fmt.Printf("  ✓ %s (optional) - %s\n", name, ctx.File)
+				if !dc.quiet && (dc.verbose || multi) {
+					fmt.Printf("  ✓ %s (optional) - %s\n", name, found)
 				}
 			}
 		}
@@ -0,0 +1,142 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/grantcarthew/start/internal/config"
+	"github.com/grantcarthew/start/internal/domain"
+	"github.com/grantcarthew/start/internal/engine"
+	"github.com/spf13/cobra"
+)
+
+// defaultFanOutDir is where --agents writes results when --outdir is not set
+const defaultFanOutDir = "start-agents"
+
+// addFanOutFlags registers the multi-agent flags on an agent-launching command
+func addFanOutFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSlice("agents", nil, "Run the prompt headless on several agents concurrently (comma-separated)")
+	cmd.Flags().String("outdir", defaultFanOutDir, "Directory for --agents results (<outdir>/<agent>.md)")
+}
+
+// fanOutAgents returns the --agents list, or nil when not fanning out
+// Flags that only make sense for a single agent are rejected
+func fanOutAgents(cmd *cobra.Command) ([]string, error) {
+	names, _ := cmd.Flags().GetStringSlice("agents")
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	for _, flag := range []string{"agent", "capture", "output"} {
+		if cmd.Flags().Changed(flag) {
+			return nil, fmt.Errorf("--%s cannot be used with --agents", flag)
+		}
+	}
+
+	var agents []string
+	seen := make(map[string]bool)
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		agents = append(agents, name)
+	}
+	if len(agents) == 0 {
+		return nil, fmt.Errorf("--agents requires at least one agent name")
+	}
+
+	return agents, nil
+}
+
+// runFanOut sends a prepared launch to each agent headless and concurrently,
+// then prints a summary of the results
+// The prompt, role and contexts are composed once; each agent only gets its
+// own model and prompt layout. --model applies to the agents it resolves for,
+// the others use their default model
+func runFanOut(cmd *cobra.Command, executor *engine.Executor, catalog *engine.ModelCatalog, cfg domain.Config, launch preparedLaunch, agents []string) error {
+	outDir, _ := cmd.Flags().GetString("outdir")
+	modelFlag, _ := cmd.Flags().GetString("model")
+
+	// Names resolve by prefix, so duplicates only show once resolved
+	var runs []engine.ExecuteParams
+	var names []string
+	modelErrs := make(map[string]error)
+	for _, name := range agents {
+		agent, err := selectAgent(cfg, name, "")
+		if err != nil {
+			return err
+		}
+		if slices.Contains(names, agent.Name) {
+			continue
+		}
+		names = append(names, agent.Name)
+
+		known, _ := catalog.Known(agent.Name)
+		modelName, modelID, warning, err := selectModel(agent, modelFlag, known.IDs)
+		if err == nil && warning != "" {
+			// The model is not one of this agent's, so it keeps its default
+			modelName, modelID, _, err = selectModel(agent, "", nil)
+		}
+		if err != nil {
+			// A model failure only fails this agent
+			modelErrs[agent.Name] = err
+			continue
+		}
+
+		params := launch.Params
+		params.Agent = agent
+		params.Model = modelID
+		params.ModelName = modelName
+		params.Layout = config.MergePromptLayout(cfg.Settings.PromptLayout, agent.PromptLayout)
+		runs = append(runs, params)
+	}
+
+	fmt.Fprintf(os.Stderr, "Running %d agents: %s\n", len(names), strings.Join(names, ", "))
+	ran := executor.FanOut(runs, outDir)
+
+	// Agents without a model never ran, they report in their place in the list
+	results := make([]engine.FanOutResult, 0, len(names))
+	for _, name := range names {
+		if err, ok := modelErrs[name]; ok {
+			results = append(results, engine.FanOutResult{Agent: name, Err: err})
+			continue
+		}
+		results = append(results, ran[0])
+		ran = ran[1:]
+	}
+
+	failed := 0
+	fmt.Println()
+	fmt.Printf("Results (%s):\n", outDir)
+	for _, result := range results {
+		marker := "✓"
+		if result.Failed() {
+			marker = "✗"
+			failed++
+		}
+		if result.Err != nil {
+			fmt.Printf("  %s %-15s %8s  error: %v\n", marker, result.Agent, result.Duration.Round(100*time.Millisecond), result.Err)
+			continue
+		}
+		fmt.Printf("  %s %-15s %8s  exit %-3d %-12s %s\n",
+			marker,
+			result.Agent,
+			result.Duration.Round(100*time.Millisecond),
+			result.ExitCode,
+			formatSize(result.Bytes),
+			result.Path,
+		)
+	}
+
+	if failed > 0 {
+		cmd.SilenceUsage = true
+		return fmt.Errorf("%d of %d agents failed", failed, len(results))
+	}
+
+	return nil
+}
@@ -0,0 +1,319 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/grantcarthew/start/internal/config"
+	"github.com/grantcarthew/start/internal/domain"
+	"github.com/grantcarthew/start/internal/engine"
+	"github.com/spf13/cobra"
+)
+
+// NewHistoryCommand creates the history command
+func NewHistoryCommand(history domain.HistoryStore) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Show recorded agent invocations",
+		Long: `Shows the invocation history log. Every agent launch records the agent, model,
+role, context documents (with content hashes), task, instructions and the final
+command so a session can be reproduced with 'start replay'.
+
+Examples:
+  start history                      # Recent invocations
+  start history --task code-review   # Only runs of one task
+  start history --json               # Full records as JSON`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			taskFilter, _ := cmd.Flags().GetString("task")
+			limit, _ := cmd.Flags().GetInt("limit")
+			asJSON, _ := cmd.Flags().GetBool("json")
+
+			entries, err := history.List()
+			if err != nil {
+				return err
+			}
+
+			// Filter by task
+			if taskFilter != "" {
+				var filtered []domain.HistoryEntry
+				for _, entry := range entries {
+					if entry.Task == taskFilter {
+						filtered = append(filtered, entry)
+					}
+				}
+				entries = filtered
+			}
+
+			// Keep the most recent entries
+			if limit > 0 && len(entries) > limit {
+				entries = entries[len(entries)-limit:]
+			}
+
+			if asJSON {
+				if entries == nil {
+					entries = []domain.HistoryEntry{}
+				}
+				enc := json.NewEncoder(os.Stdout)
+				enc.SetIndent("", "  ")
+				if err := enc.Encode(entries); err != nil {
+					return fmt.Errorf("failed to encode history: %w", err)
+				}
+				return nil
+			}
+
+			if len(entries) == 0 {
+				fmt.Println("No invocations recorded.")
+				return nil
+			}
+
+			fmt.Println("Invocation history (newest last):")
+			fmt.Println()
+			for _, entry := range entries {
+				what := entry.Mode
+				if entry.Task != "" {
+					what = "task " + entry.Task
+				}
+				fmt.Printf("  %s  %s  %-20s %s (%s)  role: %s\n",
+					entry.ID,
+					entry.Timestamp.Local().Format("2006-01-02 15:04"),
+					what,
+					entry.Agent,
+					entry.Model,
+					entry.Role,
+				)
+				fmt.Printf("  %-20s  %s\n", "", entry.Cwd)
+			}
+			fmt.Println()
+			fmt.Println("Use 'start replay <id>' to re-run an invocation.")
+
+			return nil
+		},
+	}
+
+	cmd.Flags().String("task", "", "Only show invocations of this task")
+	cmd.Flags().IntP("limit", "n", 20, "Number of entries to show (0 for all)")
+	cmd.Flags().Bool("json", false, "Output full records as JSON")
+
+	return cmd
+}
+
+// ReplayCommand holds the dependencies for the replay command
+type ReplayCommand struct {
+	configLoader *config.Loader
+	validator    *config.Validator
+	executor     *engine.Executor
+	history      domain.HistoryStore
+	taskResolver *engine.TaskResolver
+	resolver     *engine.PlaceholderResolver
+	utdProcessor *engine.UTDProcessor
+	launcher     *launcher
+}
+
+// NewReplayCommand creates the replay command
+func NewReplayCommand(
+	configLoader *config.Loader,
+	validator *config.Validator,
+	executor *engine.Executor,
+	history domain.HistoryStore,
+	roleSelector *engine.RoleSelector,
+	roleLoader *engine.RoleLoader,
+	contextLoader *engine.ContextLoader,
+	taskLoader *engine.TaskLoader,
+	taskResolver *engine.TaskResolver,
+	resolver *engine.PlaceholderResolver,
+	utdProcessor *engine.UTDProcessor,
+	modelCatalog *engine.ModelCatalog,
+) *cobra.Command {
+	rc := &ReplayCommand{
+		configLoader: configLoader,
+		validator:    validator,
+		executor:     executor,
+		history:      history,
+		taskResolver: taskResolver,
+		resolver:     resolver,
+		utdProcessor: utdProcessor,
+		launcher: &launcher{
+			roleSelector:  roleSelector,
+			roleLoader:    roleLoader,
+			contextLoader: contextLoader,
+			taskLoader:    taskLoader,
+			modelCatalog:  modelCatalog,
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "replay <id>",
+		Short: "Re-run a recorded invocation",
+		Long: `Re-runs an invocation from the history log in its original working directory.
+
+By default the exact recorded command is executed again. With --reread the same
+selection (agent, model, role, context flags, task, instructions and prompt) is
+run through the current configuration, re-reading context documents. Contexts
+whose content changed since the recording are reported.
+
+A headless invocation is replayed headless, to its recorded output file if it
+had one. --capture or --output override the recorded destination.
+
+The id may be a unique prefix, or 'last' for the most recent invocation.
+
+Examples:
+  start replay 20250104-143000-ab12
+  start replay last --reread
+  start replay last --output answer.md`,
+		Args: cobra.ExactArgs(1),
+		RunE: rc.run,
+	}
+
+	cmd.Flags().Bool("reread", false, "Re-read contexts and roles from current configuration")
+	addHeadlessFlags(cmd)
+
+	return cmd
+}
+
+// run executes the replay command
+func (rc *ReplayCommand) run(cmd *cobra.Command, args []string) error {
+	entries, err := rc.history.List()
+	if err != nil {
+		return err
+	}
+	entry, err := engine.FindHistoryEntry(entries, args[0])
+	if err != nil {
+		return err
+	}
+
+	// Replay in the original working directory, placeholders and relative
+	// files included
+	if entry.Cwd != "" {
+		if err := os.Chdir(entry.Cwd); err != nil {
+			return fmt.Errorf("cannot change to recorded directory: %w", err)
+		}
+		rc.resolver.SetWorkDir(entry.Cwd)
+		rc.utdProcessor.SetWorkDir(entry.Cwd)
+	}
+
+	// Headless entries replay headless unless --capture or --output is given
+	if entry.Headless && !cmd.Flags().Changed("capture") && !cmd.Flags().Changed("output") {
+		if entry.Output != "" {
+			cmd.Flags().Set("output", entry.Output)
+		} else {
+			cmd.Flags().Set("capture", "true")
+		}
+	}
+	capture, _ := cmd.Flags().GetBool("capture")
+	outputPath, _ := cmd.Flags().GetString("output")
+
+	reread, _ := cmd.Flags().GetBool("reread")
+	if !reread {
+		if capture || outputPath != "" {
+			if !entry.Headless {
+				return fmt.Errorf("entry %s is interactive, use --reread to run it headless", entry.ID)
+			}
+			entry.Output = outputPath
+			return runHeadless(cmd, outputPath, func(stdout io.Writer) (int, error) {
+				return rc.executor.ReplayCapture(entry, stdout)
+			})
+		}
+
+		// Execute recorded command (replaces current process, never returns on success)
+		if err := rc.executor.Replay(entry); err != nil {
+			return fmt.Errorf("execution failed: %w", err)
+		}
+		return nil
+	}
+
+	cfg, globalCfg, localCfg, err := loadMergedConfig(rc.configLoader, rc.validator)
+	if err != nil {
+		return err
+	}
+
+	req := launchRequest{
+		AgentFlag:    entry.Agent,
+		ModelFlag:    entry.Model,
+		RoleFlag:     entry.Role,
+		CommandType:  engine.CommandType(entry.Mode),
+		UserPrompt:   entry.UserPrompt,
+		Instructions: entry.Instructions,
+	}
+	if flags := entry.ContextFlags; flags != nil {
+		req.Contexts = engine.ContextSelection{
+			Include:    flags.Include,
+			Exclude:    flags.Exclude,
+			Tags:       flags.Tags,
+			NoDefaults: flags.NoDefaults,
+		}
+	}
+
+	// Model alias removed from config: fall back to the recorded model ID
+	if agent, ok := cfg.Agents[entry.Agent]; ok {
+		if _, ok := agent.Models[entry.Model]; !ok {
+			req.ModelFlag = entry.ModelID
+		}
+	}
+
+	if entry.Task != "" {
+		task, err := rc.taskResolver.Resolve(entry.Task, localCfg.Tasks, globalCfg.Tasks)
+		if err != nil {
+			return fmt.Errorf("task %q from history not found: %w", entry.Task, err)
+		}
+		if len(task.Steps) > 0 {
+			return fmt.Errorf("task %q runs steps, replay a step entry without --reread", entry.Task)
+		}
+		req.Task = &task
+
+		// Recorded parameters are checked again against the current definition
+		params, _, err := rc.launcher.taskLoader.ResolveParams(task, entry.Params, nil)
+		if err != nil {
+			return err
+		}
+		req.Params = params
+	}
+
+	launch, err := rc.launcher.prepare(cfg, req)
+	if err != nil {
+		return err
+	}
+	// Cleanup temp role file if needed (deferred)
+	defer rc.launcher.cleanup(launch)
+
+	for _, change := range contextChanges(entry.Contexts, launch.Contexts) {
+		fmt.Fprintf(os.Stderr, "⚠ %s\n", change)
+	}
+
+	// Execute agent (replaces current process, never returns on success)
+	return runAgent(cmd, rc.executor, launch)
+}
+
+// contextChanges describes differences between recorded and freshly loaded contexts
+func contextChanges(recorded []domain.HistoryContext, loaded []engine.LoadedContext) []string {
+	var changes []string
+
+	current := make(map[string]string)
+	for _, ctx := range loaded {
+		if !ctx.Skipped {
+			current[ctx.Name] = engine.HashContent(ctx.Content)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, ctx := range recorded {
+		seen[ctx.Name] = true
+		hash, ok := current[ctx.Name]
+		switch {
+		case !ok:
+			changes = append(changes, fmt.Sprintf("Context %s is no longer loaded", ctx.Name))
+		case hash != ctx.Hash:
+			changes = append(changes, fmt.Sprintf("Context %s changed since recording", ctx.Name))
+		}
+	}
+
+	for _, ctx := range loaded {
+		if !ctx.Skipped && !seen[ctx.Name] {
+			changes = append(changes, fmt.Sprintf("Context %s is new since recording", ctx.Name))
+		}
+	}
+
+	return changes
+}
@@ -0,0 +1,476 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/grantcarthew/start/internal/config"
+	"github.com/grantcarthew/start/internal/domain"
+	"github.com/grantcarthew/start/internal/engine"
+	"github.com/spf13/cobra"
+)
+
+// launcher runs the selection path shared by every command that starts an agent:
+// agent, model, role, contexts, task and placeholder resolution
+type launcher struct {
+	roleSelector  *engine.RoleSelector
+	roleLoader    *engine.RoleLoader
+	contextLoader *engine.ContextLoader
+	taskLoader    *engine.TaskLoader
+	modelCatalog  *engine.ModelCatalog
+}
+
+// launchRequest describes what the user asked for
+type launchRequest struct {
+	AgentFlag    string
+	ModelFlag    string
+	RoleFlag     string
+	CommandType  engine.CommandType
+	UserPrompt   string            // Prompt text (root command)
+	Task         *domain.Task      // Task to run (task command)
+	Instructions string            // Task instructions
+	Params       map[string]string // Task parameter values from resolveTaskArgs
+	StepOutputs  map[string]string // Earlier step outputs for {step:name} (pipeline steps)
+	Contexts     engine.ContextSelection
+	FanOut       bool // Each agent's model is selected by runFanOut
+}
+
+// preparedLaunch holds everything needed to execute (or preview) an agent run
+type preparedLaunch struct {
+	Agent      domain.Agent
+	ModelName  string // Model alias as given (flag or default_model)
+	ModelID    string // Resolved model ID
+	Role       engine.LoadedRole
+	RoleSource string // Where the role selection came from
+	Contexts   []engine.LoadedContext
+	Task       *engine.LoadedTask
+	Warnings   []string // Selection warnings, such as a --model passed through
+	Shell      string
+	Timeout    int
+	Params     engine.ExecuteParams
+}
+
+// loadMergedConfig loads global and local config, merges and validates them
+// Returns merged, global and local configs
+func loadMergedConfig(configLoader *config.Loader, validator *config.Validator) (domain.Config, domain.Config, domain.Config, error) {
+	return loadMergedConfigWithVars(configLoader, validator, nil)
+}
+
+// loadMergedConfigWithVars loads config like loadMergedConfig, applying --var
+// overrides on top of [variables] before validation
+func loadMergedConfigWithVars(configLoader *config.Loader, validator *config.Validator, overrides map[string]string) (domain.Config, domain.Config, domain.Config, error) {
+	globalCfg, err := configLoader.LoadGlobal()
+	if err != nil {
+		return domain.Config{}, domain.Config{}, domain.Config{}, fmt.Errorf("failed to load global config: %w", err)
+	}
+
+	// Get current working directory for local config
+	workDir := "."
+	localCfg, err := configLoader.LoadLocal(workDir)
+	if err != nil {
+		// Local config is optional, use empty config
+		localCfg = globalCfg
+	}
+
+	// Merge configs
+	cfg := config.Merge(globalCfg, localCfg)
+	cfg.Variables = config.MergeVariables(cfg.Variables, overrides)
+
+	// Validate merged config
+	if err := validator.Validate(cfg); err != nil {
+		return cfg, globalCfg, localCfg, fmt.Errorf("config validation failed: %w", err)
+	}
+
+	return cfg, globalCfg, localCfg, nil
+}
+
+// variableOverrides parses the --var flags of cmd
+func variableOverrides(cmd *cobra.Command) (map[string]string, error) {
+	flags, _ := cmd.Flags().GetStringArray("var")
+	return engine.ParseVariableOverrides(flags)
+}
+
+// selectAgent chooses an agent with precedence: --agent flag > task agent > default_agent
+func selectAgent(cfg domain.Config, agentFlag, taskAgent string) (domain.Agent, error) {
+	var agentName string
+	if agentFlag != "" {
+		name, err := resolveName("agent", agentFlag, agentCandidates(cfg))
+		if err != nil {
+			return domain.Agent{}, err
+		}
+		agentName = name
+	} else if taskAgent != "" {
+		agentName = taskAgent
+	} else if cfg.Settings.DefaultAgent != "" {
+		agentName = cfg.Settings.DefaultAgent
+	} else {
+		return domain.Agent{}, fmt.Errorf("no agent specified and no default agent configured")
+	}
+
+	agent, ok := cfg.Agents[agentName]
+	if !ok {
+		return domain.Agent{}, fmt.Errorf("agent %q not found in configuration", agentName)
+	}
+	agent.Name = agentName
+
+	return agent, nil
+}
+
+// selectModel resolves the model for an agent
+// known holds the model IDs discovered through models_command or models_url
+// Returns the model name (alias), full model ID and a warning when --model
+// is unknown and passes through to the agent as a raw model ID
+func selectModel(agent domain.Agent, modelFlag string, known []string) (string, string, string, error) {
+	if modelFlag != "" {
+		// Model name, full ID, discovered ID or a unique prefix of any of them
+		name, err := resolveName("model", modelFlag, modelCandidates(agent, known))
+		var unknown *engine.UnknownNameError
+		if errors.As(err, &unknown) {
+			return modelFlag, modelFlag, unknownModelWarning(agent, modelFlag, known, unknown.Suggestion), nil
+		} else if err != nil {
+			return "", "", "", err
+		}
+		if fullID, ok := agent.Models[name]; ok {
+			return name, fullID, "", nil
+		}
+		return name, name, "", nil
+	}
+
+	if agent.DefaultModel != "" {
+		if fullID, ok := agent.Models[agent.DefaultModel]; ok {
+			return agent.DefaultModel, fullID, "", nil
+		}
+		return "", "", "", fmt.Errorf("default model %q not found in agent %q models", agent.DefaultModel, agent.Name)
+	}
+
+	return "", "", "", fmt.Errorf("no model specified and no default model for agent %q", agent.Name)
+}
+
+// unknownModelWarning describes a --model that is neither configured nor discovered
+func unknownModelWarning(agent domain.Agent, model string, known []string, suggestion string) string {
+	var warning string
+	switch {
+	case len(known) > 0:
+		warning = fmt.Sprintf("Model %q is not a known model for agent %q, passing through to CLI", model, agent.Name)
+	case agent.ModelsCommand != "" || agent.ModelsURL != "":
+		warning = fmt.Sprintf("Model %q not in config, passing through to CLI (run 'start config agent models %s --refresh' to check it)", model, agent.Name)
+	default:
+		warning = fmt.Sprintf("Model %q not in config, passing through to CLI", model)
+	}
+	if suggestion != "" {
+		warning += fmt.Sprintf(", did you mean %q?", suggestion)
+	}
+	return warning
+}
+
+// shellAndTimeout returns the configured shell and command timeout with defaults applied
+func shellAndTimeout(cfg domain.Config) (string, int) {
+	shell := cfg.Settings.Shell
+	if shell == "" {
+		shell = engine.DetectShell()
+	}
+	timeout := cfg.Settings.CommandTimeout
+	if timeout == 0 {
+		timeout = 30 // Default 30 seconds
+	}
+	return shell, timeout
+}
+
+// contextLoadOptions returns the context command pool size and run deadline with defaults applied
+func contextLoadOptions(cfg domain.Config) engine.LoadOptions {
+	deadline := cfg.Settings.CommandDeadline
+	if deadline == 0 {
+		deadline = 60 // Default 60 seconds for all context commands
+	}
+	return engine.LoadOptions{
+		MaxParallel: cfg.Settings.MaxParallelCommands,
+		Deadline:    time.Duration(deadline) * time.Second,
+		Variables:   cfg.Variables,
+	}
+}
+
+// prepare runs the full selection path and returns the resolved launch
+// Callers must call cleanup on the result when done
+func (l *launcher) prepare(cfg domain.Config, req launchRequest) (preparedLaunch, error) {
+	var result preparedLaunch
+
+	taskAgent, taskRole := "", ""
+	if req.Task != nil {
+		taskAgent = req.Task.Agent
+		taskRole = req.Task.Role
+	}
+
+	// Context selection: flags plus the optional contexts a task pulls in
+	contextOpts := contextLoadOptions(cfg)
+	contextOpts.Selection = req.Contexts
+	if req.Task != nil {
+		contextOpts.Selection.Include = append(slices.Clone(req.Task.Contexts), req.Contexts.Include...)
+	}
+	if err := checkContextSelection(cfg, contextOpts.Selection); err != nil {
+		return result, err
+	}
+
+	// Select agent and model
+	agent, err := selectAgent(cfg, req.AgentFlag, taskAgent)
+	if err != nil {
+		return result, err
+	}
+	var modelName, modelID string
+	var warnings []string
+	if !req.FanOut {
+		known, _ := l.modelCatalog.Known(agent.Name)
+		var modelWarning string
+		modelName, modelID, modelWarning, err = selectModel(agent, req.ModelFlag, known.IDs)
+		if err != nil {
+			return result, err
+		}
+		if modelWarning != "" {
+			warnings = append(warnings, modelWarning)
+		}
+	}
+
+	roleFlag := req.RoleFlag
+	if roleFlag != "" {
+		if roleFlag, err = resolveName("role", roleFlag, roleCandidates(cfg)); err != nil {
+			return result, err
+		}
+	}
+
+	// Select role with precedence: --role flag > task role > default_role > first role
+	selectionCtx := engine.SelectionContext{
+		RoleFlag:    roleFlag,
+		TaskRole:    taskRole,
+		DefaultRole: cfg.Settings.DefaultRole,
+		RoleOrder:   cfg.RoleOrder,
+	}
+	role, err := l.roleSelector.Select(selectionCtx, cfg.Roles)
+	if err != nil {
+		return result, fmt.Errorf("role selection failed: %w", err)
+	}
+
+	shell, timeout := shellAndTimeout(cfg)
+
+	// A task role whose when predicates are not met gives way to default_role
+	var roleWarnings []string
+	if ok, reason := l.roleLoader.Applies(role, shell, timeout); !ok {
+		if selectionCtx.RoleFlag != "" || selectionCtx.TaskRole == "" || selectionCtx.DefaultRole == "" {
+			return result, fmt.Errorf("role %q does not apply here (%s)", role.Name, reason)
+		}
+		roleWarnings = append(roleWarnings, fmt.Sprintf("Role %s skipped (%s), using default_role", role.Name, reason))
+		selectionCtx.TaskRole = ""
+		if role, err = l.roleSelector.Select(selectionCtx, cfg.Roles); err != nil {
+			return result, fmt.Errorf("role selection failed: %w", err)
+		}
+		if ok, reason := l.roleLoader.Applies(role, shell, timeout); !ok {
+			return result, fmt.Errorf("role %q does not apply here (%s)", role.Name, reason)
+		}
+	}
+
+	// Load role
+	loadedRole, err := l.roleLoader.LoadRole(role, cfg.Roles, shell, timeout, cfg.Variables)
+	if err != nil {
+		return result, fmt.Errorf("failed to load role: %w", err)
+	}
+	loadedRole.Warnings = append(roleWarnings, loadedRole.Warnings...)
+
+	// Load contexts (interactive mode = all, prompt/task = required only)
+	contexts := l.contextLoader.LoadContexts(
+		cfg.Contexts,
+		cfg.ContextOrder,
+		req.CommandType,
+		shell,
+		timeout,
+		contextOpts,
+	)
+
+	userPrompt := req.UserPrompt
+
+	// Load task with instructions
+	var loadedTask *engine.LoadedTask
+	taskName := ""
+	if req.Task != nil {
+		taskName = req.Task.Name
+		task, err := l.taskLoader.LoadTask(*req.Task, req.Instructions, shell, timeout, cfg.Variables, req.Params)
+		if err != nil {
+			l.roleLoader.CleanupRole(loadedRole)
+			return result, fmt.Errorf("failed to load task: %w", err)
+		}
+		// Pipeline steps see the outputs of earlier steps, expanded last so
+		// agent output is never treated as a template
+		if req.StepOutputs != nil {
+			prompt, warnings := engine.ExpandStepOutputs(task.Prompt, req.StepOutputs)
+			task.Prompt = prompt
+			task.Warnings = append(task.Warnings, warnings...)
+		}
+		loadedTask = &task
+		userPrompt = task.Prompt
+	}
+
+	result = preparedLaunch{
+		Agent:      agent,
+		ModelName:  modelName,
+		ModelID:    modelID,
+		Role:       loadedRole,
+		RoleSource: roleSource(selectionCtx),
+		Contexts:   contexts,
+		Task:       loadedTask,
+		Warnings:   warnings,
+		Shell:      shell,
+		Timeout:    timeout,
+		Params: engine.ExecuteParams{
+			Agent:        agent,
+			Model:        modelID,
+			UserPrompt:   userPrompt,
+			RoleContent:  loadedRole.Content,
+			RoleFilePath: loadedRole.FilePath,
+			Contexts:     contexts,
+			Shell:        shell,
+			Mode:         req.CommandType,
+			ModelName:    modelName,
+			RoleName:     loadedRole.Name,
+			TaskName:     taskName,
+			Instructions: req.Instructions,
+			TaskParams:   req.Params,
+			Selection:    req.Contexts,
+			Layout:       config.MergePromptLayout(cfg.Settings.PromptLayout, agent.PromptLayout),
+			Variables:    cfg.Variables,
+		},
+	}
+
+	return result, nil
+}
+
+// cleanup removes temporary files created while preparing a launch
+func (l *launcher) cleanup(p preparedLaunch) {
+	l.roleLoader.CleanupRole(p.Role)
+}
+
+// roleSource describes which precedence rule selected the role
+func roleSource(ctx engine.SelectionContext) string {
+	switch {
+	case ctx.RoleFlag != "":
+		return "--role flag"
+	case ctx.TaskRole != "":
+		return "task configuration"
+	case ctx.DefaultRole != "":
+		return "default_role setting"
+	default:
+		return "first role in config"
+	}
+}
+
+// ExitCodeError carries a headless agent's non-zero exit code back to main
+type ExitCodeError struct {
+	Code int
+}
+
+func (e *ExitCodeError) Error() string {
+	return fmt.Sprintf("agent exited with status %d", e.Code)
+}
+
+// addContextFlags registers the per-run context selection flags, inherited by subcommands
+func addContextFlags(cmd *cobra.Command) {
+	cmd.PersistentFlags().StringSlice("context", nil, "Add a context by name (repeatable)")
+	cmd.PersistentFlags().StringSlice("no-context", nil, "Leave out a context by name (repeatable)")
+	cmd.PersistentFlags().StringSlice("context-tag", nil, "Add the contexts with this tag (repeatable)")
+	cmd.PersistentFlags().Bool("no-contexts", false, "Leave out the contexts loaded by default")
+}
+
+// contextSelection returns the context selection flags of cmd
+func contextSelection(cmd *cobra.Command) engine.ContextSelection {
+	include, _ := cmd.Flags().GetStringSlice("context")
+	exclude, _ := cmd.Flags().GetStringSlice("no-context")
+	tags, _ := cmd.Flags().GetStringSlice("context-tag")
+	none, _ := cmd.Flags().GetBool("no-contexts")
+	return engine.ContextSelection{Include: include, Exclude: exclude, Tags: tags, NoDefaults: none}
+}
+
+// checkContextSelection rejects context names and tags that match no context
+func checkContextSelection(cfg domain.Config, sel engine.ContextSelection) error {
+	for _, name := range append(slices.Clone(sel.Include), sel.Exclude...) {
+		if _, ok := cfg.Contexts[name]; !ok {
+			return fmt.Errorf("context %q not found in configuration", name)
+		}
+	}
+	for _, tag := range sel.Tags {
+		found := false
+		for _, ctx := range cfg.Contexts {
+			if slices.Contains(ctx.Tags, tag) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("no context has tag %q", tag)
+		}
+	}
+	return nil
+}
+
+// addHeadlessFlags registers the headless capture flags on an agent-launching command
+func addHeadlessFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool("capture", false, "Run the agent headless and print its output")
+	cmd.Flags().String("output", "", "Run the agent headless and write its output to a file")
+}
+
+// runAgent executes a prepared launch, as a child process when --capture or
+// --output is set, otherwise by replacing the current process
+func runAgent(cmd *cobra.Command, executor *engine.Executor, launch preparedLaunch) error {
+	capture, _ := cmd.Flags().GetBool("capture")
+	outputPath, _ := cmd.Flags().GetString("output")
+
+	for _, w := range launch.Warnings {
+		fmt.Fprintf(os.Stderr, "⚠ %s\n", w)
+	}
+
+	if !capture && outputPath == "" {
+		// Execute agent (replaces current process, never returns on success)
+		if err := executor.Execute(launch.Params); err != nil {
+			return fmt.Errorf("execution failed: %w", err)
+		}
+		return nil
+	}
+
+	params := launch.Params
+	params.Headless = true
+	params.Output = outputPath
+	return runHeadless(cmd, outputPath, func(stdout io.Writer) (int, error) {
+		return executor.Capture(params, stdout)
+	})
+}
+
+// runHeadless runs an agent as a child process through run, writing its
+// output to outputPath or stdout, and passes a non-zero exit code through
+func runHeadless(cmd *cobra.Command, outputPath string, run func(stdout io.Writer) (int, error)) error {
+	var stdout io.Writer = os.Stdout
+	if outputPath != "" {
+		f, err := os.Create(outputPath)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer f.Close()
+		stdout = f
+	}
+
+	code, err := run(stdout)
+	if err != nil {
+		return fmt.Errorf("execution failed: %w", err)
+	}
+
+	if outputPath != "" {
+		fmt.Fprintf(os.Stderr, "✓ Agent output written to %s\n", outputPath)
+	}
+
+	if code != 0 {
+		// The agent reported its own failure, pass the exit code through quietly
+		cmd.SilenceUsage = true
+		cmd.SilenceErrors = true
+		return &ExitCodeError{Code: code}
+	}
+
+	return nil
+}
@@ -0,0 +1,127 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/grantcarthew/start/internal/domain"
+	"github.com/grantcarthew/start/internal/engine"
+)
+
+// resolveName resolves a flag value or task name against configured names (DR-038)
+// An ambiguous prefix opens a numbered picker when stdin is a terminal,
+// otherwise the matches are returned in the error
+func resolveName(kind, input string, candidates []engine.NameCandidate) (string, error) {
+	name, err := engine.ResolveName(kind, input, candidates)
+	var ambiguous *engine.AmbiguousNameError
+	if errors.As(err, &ambiguous) && stdinIsTerminal() {
+		return NewPromptHelper().AskChoice(fmt.Sprintf("Multiple %ss match %q:", kind, input), ambiguous.Matches)
+	}
+	return name, err
+}
+
+// stdinIsTerminal reports whether stdin is an interactive terminal
+// /dev/null is a character device too, so it is ruled out explicitly
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+	if null, err := os.Stat(os.DevNull); err == nil && os.SameFile(info, null) {
+		return false
+	}
+	return true
+}
+
+// agentCandidates lists the configured agent names
+func agentCandidates(cfg domain.Config) []engine.NameCandidate {
+	var candidates []engine.NameCandidate
+	for name := range cfg.Agents {
+		candidates = append(candidates, engine.NameCandidate{Name: name})
+	}
+	return candidates
+}
+
+// roleCandidates lists the configured role names
+func roleCandidates(cfg domain.Config) []engine.NameCandidate {
+	var candidates []engine.NameCandidate
+	for name := range cfg.Roles {
+		candidates = append(candidates, engine.NameCandidate{Name: name})
+	}
+	return candidates
+}
+
+// contextCandidates lists the configured context names
+func contextCandidates(cfg domain.Config) []engine.NameCandidate {
+	var candidates []engine.NameCandidate
+	for name := range cfg.Contexts {
+		candidates = append(candidates, engine.NameCandidate{Name: name})
+	}
+	return candidates
+}
+
+// modelCandidates lists an agent's model names, with the full model ID as
+// alias, followed by the discovered model IDs not already configured
+func modelCandidates(agent domain.Agent, known []string) []engine.NameCandidate {
+	var candidates []engine.NameCandidate
+	configured := make(map[string]bool)
+	for name, id := range agent.Models {
+		candidates = append(candidates, engine.NameCandidate{Name: name, Alias: id})
+		configured[name] = true
+		configured[id] = true
+	}
+	for _, id := range known {
+		if !configured[id] {
+			candidates = append(candidates, engine.NameCandidate{Name: id})
+		}
+	}
+	return candidates
+}
+
+// taskCandidates lists the configured task names and aliases
+func taskCandidates(cfg domain.Config) []engine.NameCandidate {
+	var candidates []engine.NameCandidate
+	for name, task := range cfg.Tasks {
+		candidates = append(candidates, engine.NameCandidate{Name: name, Alias: task.Alias})
+	}
+	return candidates
+}
+
+// resolveTask resolves a task by name or alias, falling back to a unique
+// prefix of the merged task names
+func resolveTask(resolver *engine.TaskResolver, input string, cfg, globalCfg, localCfg domain.Config) (domain.Task, error) {
+	task, err := resolver.Resolve(input, localCfg.Tasks, globalCfg.Tasks)
+	if !errors.Is(err, engine.ErrTaskNotFound) {
+		return task, err
+	}
+	name, err := resolveName("task", input, taskCandidates(cfg))
+	if err != nil {
+		return domain.Task{}, err
+	}
+	return resolver.Resolve(name, localCfg.Tasks, globalCfg.Tasks)
+}
+
+// orderedNames returns the names in m in definition order, followed by any
+// names missing from order sorted alphabetically
+func orderedNames[T any](m map[string]T, order []string) []string {
+	names := make([]string, 0, len(m))
+	listed := make(map[string]bool)
+	for _, name := range order {
+		if _, ok := m[name]; ok && !listed[name] {
+			names = append(names, name)
+			listed[name] = true
+		}
+	}
+
+	var rest []string
+	for name := range m {
+		if !listed[name] {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+
+	return append(names, rest...)
+}
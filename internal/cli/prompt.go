@@ -0,0 +1,93 @@
+package cli
+
+import (
+	"strings"
+
+	"github.com/grantcarthew/start/internal/config"
+	"github.com/grantcarthew/start/internal/engine"
+	"github.com/spf13/cobra"
+)
+
+// PromptCommand holds the dependencies for the prompt command
+type PromptCommand struct {
+	configLoader *config.Loader
+	validator    *config.Validator
+	executor     *engine.Executor
+	launcher     *launcher
+}
+
+// NewPromptCommand creates the prompt command
+func NewPromptCommand(
+	configLoader *config.Loader,
+	validator *config.Validator,
+	executor *engine.Executor,
+	roleSelector *engine.RoleSelector,
+	roleLoader *engine.RoleLoader,
+	contextLoader *engine.ContextLoader,
+	taskLoader *engine.TaskLoader,
+	modelCatalog *engine.ModelCatalog,
+) *cobra.Command {
+	pc := &PromptCommand{
+		configLoader: configLoader,
+		validator:    validator,
+		executor:     executor,
+		launcher: &launcher{
+			roleSelector:  roleSelector,
+			roleLoader:    roleLoader,
+			contextLoader: contextLoader,
+			taskLoader:    taskLoader,
+			modelCatalog:  modelCatalog,
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "prompt [text]",
+		Short: "Launch agent with a custom prompt and required contexts",
+		Long: `Launches an AI agent with an optional custom prompt combined with required
+context documents only. Optional contexts are excluded to keep the prompt focused.
+
+Examples:
+  start prompt "analyze this codebase for security vulnerabilities"
+  start prompt                            # Required contexts only, no prompt
+  start prompt "explain" --agent gemini   # Override agent
+  start prompt "summarise" --capture      # Run headless, print the answer`,
+		RunE: pc.run,
+		Args: cobra.ArbitraryArgs,
+	}
+
+	addHeadlessFlags(cmd)
+
+	return cmd
+}
+
+// run executes the prompt command
+func (pc *PromptCommand) run(cmd *cobra.Command, args []string) error {
+	// Load and validate configuration
+	cfg, _, _, err := loadMergedConfig(pc.configLoader, pc.validator)
+	if err != nil {
+		return err
+	}
+
+	// Get flags
+	agentFlag, _ := cmd.Flags().GetString("agent")
+	modelFlag, _ := cmd.Flags().GetString("model")
+	roleFlag, _ := cmd.Flags().GetString("role")
+
+	// Select agent, model and role, then load required contexts only
+	launch, err := pc.launcher.prepare(cfg, launchRequest{
+		AgentFlag:   agentFlag,
+		ModelFlag:   modelFlag,
+		RoleFlag:    roleFlag,
+		CommandType: engine.CommandTypePrompt,
+		UserPrompt:  strings.Join(args, " "),
+		Contexts:    contextSelection(cmd),
+	})
+	if err != nil {
+		return err
+	}
+	// Cleanup temp role file if needed (deferred)
+	defer pc.launcher.cleanup(launch)
+
+	// Execute agent (replaces current process unless running headless)
+	return runAgent(cmd, pc.executor, launch)
+}
@@ -6,22 +6,26 @@ import (
 
 	"github.com/grantcarthew/start/internal/assets"
 	"github.com/grantcarthew/start/internal/config"
+	"github.com/grantcarthew/start/internal/domain"
 	"github.com/grantcarthew/start/internal/engine"
 	"github.com/spf13/cobra"
 )
 
 // RootCommand holds the dependencies for the root command
 type RootCommand struct {
-	configLoader   *config.Loader
-	validator      *config.Validator
-	executor       *engine.Executor
-	roleSelector   *engine.RoleSelector
-	roleLoader     *engine.RoleLoader
-	contextLoader  *engine.ContextLoader
-	taskLoader     *engine.TaskLoader
-	taskResolver   *engine.TaskResolver
-	assetResolver  *assets.Resolver
-	version        string
+	configLoader  *config.Loader
+	validator     *config.Validator
+	executor      *engine.Executor
+	roleSelector  *engine.RoleSelector
+	roleLoader    *engine.RoleLoader
+	contextLoader *engine.ContextLoader
+	taskLoader    *engine.TaskLoader
+	taskResolver  *engine.TaskResolver
+	assetResolver *assets.Resolver
+	history       domain.HistoryStore
+	outputCache   domain.OutputCache
+	launcher      *launcher
+	version       string
 }
 
 // NewRootCommand creates the root command
@@ -34,20 +38,34 @@ func NewRootCommand(
 	contextLoader *engine.ContextLoader,
 	taskLoader *engine.TaskLoader,
 	taskResolver *engine.TaskResolver,
+	placeholderResolver *engine.PlaceholderResolver,
+	utdProcessor *engine.UTDProcessor,
 	assetResolver *assets.Resolver,
+	history domain.HistoryStore,
+	outputCache domain.OutputCache,
+	modelCatalog *engine.ModelCatalog,
 	version string,
 ) *cobra.Command {
 	rc := &RootCommand{
-		configLoader:   configLoader,
-		validator:      validator,
-		executor:       executor,
-		roleSelector:   roleSelector,
-		roleLoader:     roleLoader,
-		contextLoader:  contextLoader,
-		taskLoader:     taskLoader,
-		taskResolver:   taskResolver,
-		assetResolver:  assetResolver,
-		version:        version,
+		configLoader:  configLoader,
+		validator:     validator,
+		executor:      executor,
+		roleSelector:  roleSelector,
+		roleLoader:    roleLoader,
+		contextLoader: contextLoader,
+		taskLoader:    taskLoader,
+		taskResolver:  taskResolver,
+		assetResolver: assetResolver,
+		history:       history,
+		outputCache:   outputCache,
+		launcher: &launcher{
+			roleSelector:  roleSelector,
+			roleLoader:    roleLoader,
+			contextLoader: contextLoader,
+			taskLoader:    taskLoader,
+			modelCatalog:  modelCatalog,
+		},
+		version: version,
 	}
 
 	cmd := &cobra.Command{
@@ -63,10 +81,13 @@ func NewRootCommand(
 	cmd.PersistentFlags().StringP("agent", "a", "", "Agent to use")
 	cmd.PersistentFlags().StringP("model", "m", "", "Model to use")
 	cmd.PersistentFlags().StringP("role", "r", "", "Role to use")
+	addContextFlags(cmd)
+	addHeadlessFlags(cmd)
+	addFanOutFlags(cmd)
 
 	// Add subcommands
 	cmd.AddCommand(NewInitCommand(assetResolver))
-	cmd.AddCommand(NewConfigCommand(configLoader, validator))
+	cmd.AddCommand(NewConfigCommand(configLoader, validator, roleLoader, modelCatalog))
 	cmd.AddCommand(NewTaskCommand(
 		configLoader,
 		validator,
@@ -76,7 +97,45 @@ func NewRootCommand(
 		contextLoader,
 		taskLoader,
 		taskResolver,
+		modelCatalog,
 	))
+	cmd.AddCommand(NewPromptCommand(
+		configLoader,
+		validator,
+		executor,
+		roleSelector,
+		roleLoader,
+		contextLoader,
+		taskLoader,
+		modelCatalog,
+	))
+	cmd.AddCommand(NewShowCommand(
+		configLoader,
+		validator,
+		executor,
+		roleSelector,
+		roleLoader,
+		contextLoader,
+		taskLoader,
+		taskResolver,
+		modelCatalog,
+	))
+	cmd.AddCommand(NewHistoryCommand(history))
+	cmd.AddCommand(NewReplayCommand(
+		configLoader,
+		validator,
+		executor,
+		history,
+		roleSelector,
+		roleLoader,
+		contextLoader,
+		taskLoader,
+		taskResolver,
+		placeholderResolver,
+		utdProcessor,
+		modelCatalog,
+	))
+	cmd.AddCommand(NewCacheCommand(outputCache))
 	cmd.AddCommand(NewAssetsCommand(assetResolver))
 	cmd.AddCommand(NewCompletionCommand())
 	cmd.AddCommand(NewDoctorCommand(configLoader, validator, version))
@@ -89,26 +148,10 @@ func NewRootCommand(
 
 // run executes the root command
 func (rc *RootCommand) run(cmd *cobra.Command, args []string) error {
-	// Load configuration
-	globalCfg, err := rc.configLoader.LoadGlobal()
-	if err != nil {
-		return fmt.Errorf("failed to load global config: %w", err)
-	}
-
-	// Get current working directory for local config
-	workDir := "."
-	localCfg, err := rc.configLoader.LoadLocal(workDir)
+	// Load and validate configuration
+	cfg, _, _, err := loadMergedConfig(rc.configLoader, rc.validator)
 	if err != nil {
-		// Local config is optional, use empty config
-		localCfg = globalCfg
-	}
-
-	// Merge configs
-	cfg := config.Merge(globalCfg, localCfg)
-
-	// Validate merged config
-	if err := rc.validator.Validate(cfg); err != nil {
-		return fmt.Errorf("config validation failed: %w", err)
+		return err
 	}
 
 	// Get flags
@@ -116,99 +159,38 @@ func (rc *RootCommand) run(cmd *cobra.Command, args []string) error {
 	modelFlag, _ := cmd.Flags().GetString("model")
 	roleFlag, _ := cmd.Flags().GetString("role")
 
-	// Select agent
-	var agentName string
-	if agentFlag != "" {
-		agentName = agentFlag
-	} else if cfg.Settings.DefaultAgent != "" {
-		agentName = cfg.Settings.DefaultAgent
-	} else {
-		return fmt.Errorf("no agent specified and no default agent configured")
-	}
-
-	// Get agent from config
-	agent, ok := cfg.Agents[agentName]
-	if !ok {
-		return fmt.Errorf("agent %q not found in configuration", agentName)
+	agents, err := fanOutAgents(cmd)
+	if err != nil {
+		return err
 	}
-	agent.Name = agentName
-
-	// Select model
-	var modelID string
-	if modelFlag != "" {
-		// Check if it's a model name (needs resolution) or full ID
-		if fullID, ok := agent.Models[modelFlag]; ok {
-			modelID = fullID
-		} else {
-			// Assume it's a full model ID
-			modelID = modelFlag
+	if len(agents) > 0 {
+		if len(args) == 0 {
+			return fmt.Errorf("--agents requires a prompt")
 		}
-	} else if agent.DefaultModel != "" {
-		// Use default model
-		if fullID, ok := agent.Models[agent.DefaultModel]; ok {
-			modelID = fullID
-		} else {
-			return fmt.Errorf("default model %q not found in agent %q models", agent.DefaultModel, agentName)
-		}
-	} else {
-		return fmt.Errorf("no model specified and no default model for agent %q", agentName)
+		agentFlag = agents[0]
 	}
 
-	// Select role
-	selectionCtx := engine.SelectionContext{
+	// Select agent, model and role, then load contexts (interactive mode = all contexts)
+	// Prompt assembled from arguments (empty is valid for interactive sessions)
+	launch, err := rc.launcher.prepare(cfg, launchRequest{
+		AgentFlag:   agentFlag,
+		ModelFlag:   modelFlag,
 		RoleFlag:    roleFlag,
-		TaskRole:    "", // No task for root command
-		DefaultRole: cfg.Settings.DefaultRole,
-	}
-	role, err := rc.roleSelector.Select(selectionCtx, cfg.Roles)
-	if err != nil {
-		return fmt.Errorf("role selection failed: %w", err)
-	}
-
-	// Get shell and timeout settings
-	shell := cfg.Settings.Shell
-	if shell == "" {
-		shell = engine.DetectShell()
-	}
-	timeout := cfg.Settings.CommandTimeout
-	if timeout == 0 {
-		timeout = 30 // Default 30 seconds
-	}
-
-	// Load role
-	loadedRole, err := rc.roleLoader.LoadRole(role, shell, timeout)
+		CommandType: engine.CommandTypeInteractive,
+		UserPrompt:  strings.Join(args, " "),
+		Contexts:    contextSelection(cmd),
+		FanOut:      len(agents) > 0,
+	})
 	if err != nil {
-		return fmt.Errorf("failed to load role: %w", err)
+		return err
 	}
 	// Cleanup temp role file if needed (deferred)
-	defer rc.roleLoader.CleanupRole(loadedRole)
-
-	// Load contexts (interactive mode = all contexts)
-	contexts := rc.contextLoader.LoadContexts(
-		cfg.Contexts,
-		cfg.ContextOrder,
-		engine.CommandTypeInteractive,
-		shell,
-		timeout,
-	)
-
-	// Assemble prompt from arguments (empty is valid for interactive sessions)
-	userPrompt := strings.Join(args, " ")
-
-	// Execute agent (replaces current process, never returns on success)
-	execParams := engine.ExecuteParams{
-		Agent:        agent,
-		Model:        modelID,
-		UserPrompt:   userPrompt,
-		RoleContent:  loadedRole.Content,
-		RoleFilePath: loadedRole.FilePath,
-		Contexts:     contexts,
-		Shell:        shell,
-	}
+	defer rc.launcher.cleanup(launch)
 
-	if err := rc.executor.Execute(execParams); err != nil {
-		return fmt.Errorf("execution failed: %w", err)
+	if len(agents) > 0 {
+		return runFanOut(cmd, rc.executor, rc.launcher.modelCatalog, cfg, launch, agents)
 	}
 
-	return nil
+	// Execute agent (replaces current process unless running headless)
+	return runAgent(cmd, rc.executor, launch)
 }
@@ -0,0 +1,860 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/grantcarthew/start/internal/config"
+	"github.com/grantcarthew/start/internal/domain"
+	"github.com/grantcarthew/start/internal/engine"
+	"github.com/spf13/cobra"
+)
+
+// previewLineLimit is the number of content lines shown without --verbose
+const previewLineLimit = 10
+
+// ShowCommand holds the dependencies for the show command
+type ShowCommand struct {
+	configLoader  *config.Loader
+	validator     *config.Validator
+	executor      *engine.Executor
+	roleSelector  *engine.RoleSelector
+	roleLoader    *engine.RoleLoader
+	contextLoader *engine.ContextLoader
+	taskResolver  *engine.TaskResolver
+	launcher      *launcher
+}
+
+// NewShowCommand creates the show command
+func NewShowCommand(
+	configLoader *config.Loader,
+	validator *config.Validator,
+	executor *engine.Executor,
+	roleSelector *engine.RoleSelector,
+	roleLoader *engine.RoleLoader,
+	contextLoader *engine.ContextLoader,
+	taskLoader *engine.TaskLoader,
+	taskResolver *engine.TaskResolver,
+	modelCatalog *engine.ModelCatalog,
+) *cobra.Command {
+	sc := &ShowCommand{
+		configLoader:  configLoader,
+		validator:     validator,
+		executor:      executor,
+		roleSelector:  roleSelector,
+		roleLoader:    roleLoader,
+		contextLoader: contextLoader,
+		taskResolver:  taskResolver,
+		launcher: &launcher{
+			roleSelector:  roleSelector,
+			roleLoader:    roleLoader,
+			contextLoader: contextLoader,
+			taskLoader:    taskLoader,
+			modelCatalog:  modelCatalog,
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "show [prompt]",
+		Short: "Preview execution or display resolved content",
+		Long: `Preview what start would execute without running the agent, or display
+resolved role, context and agent content after UTD processing and config merging.
+
+Examples:
+  start show                      # Preview 'start'
+  start show --verbose            # Preview with full content
+  start show --json               # Preview as JSON
+  start show task code-review     # Preview 'start task code-review'
+  start show prompt "explain"     # Preview 'start prompt "explain"'
+  start show role                 # Show resolved default role
+  start show context              # Show all resolved contexts
+  start show agent gemini         # Show effective agent configuration`,
+		RunE: sc.runPreview,
+		Args: cobra.ArbitraryArgs,
+	}
+
+	cmd.Flags().Bool("verbose", false, "Show full content (no truncation)")
+	cmd.Flags().Bool("json", false, "Output preview as JSON")
+
+	cmd.AddCommand(sc.newShowTaskCommand())
+	cmd.AddCommand(sc.newShowPromptCommand())
+	cmd.AddCommand(sc.newShowRoleCommand())
+	cmd.AddCommand(sc.newShowContextCommand())
+	cmd.AddCommand(sc.newShowAgentCommand())
+
+	return cmd
+}
+
+// runPreview previews the root command
+func (sc *ShowCommand) runPreview(cmd *cobra.Command, args []string) error {
+	cfg, _, _, err := loadMergedConfig(sc.configLoader, sc.validator)
+	if err != nil {
+		return err
+	}
+
+	return sc.preview(cmd, cfg, launchRequest{
+		CommandType: engine.CommandTypeInteractive,
+		UserPrompt:  strings.Join(args, " "),
+	})
+}
+
+// newShowTaskCommand creates the show task command
+func (sc *ShowCommand) newShowTaskCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "task <name> [instructions]",
+		Short: "Preview task execution",
+		Long:  "Show what 'start task <name>' would execute without running the agent",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			overrides, err := variableOverrides(cmd)
+			if err != nil {
+				return err
+			}
+
+			cfg, globalCfg, localCfg, err := loadMergedConfigWithVars(sc.configLoader, sc.validator, overrides)
+			if err != nil {
+				return err
+			}
+
+			task, err := resolveTask(sc.taskResolver, args[0], cfg, globalCfg, localCfg)
+			if err != nil {
+				return err
+			}
+
+			params, instructions, err := resolveTaskArgs(cmd, sc.launcher.taskLoader, task, args[1:])
+			if err != nil {
+				return err
+			}
+
+			// Step outputs only exist at run time, show the plan instead
+			if len(task.Steps) > 0 {
+				asJSON, _ := cmd.Flags().GetBool("json")
+				return printStepPlan(task, asJSON)
+			}
+
+			return sc.preview(cmd, cfg, launchRequest{
+				CommandType:  engine.CommandTypeTask,
+				Task:         &task,
+				Instructions: instructions,
+				Params:       params,
+			})
+		},
+	}
+
+	cmd.Flags().Bool("verbose", false, "Show full content (no truncation)")
+	cmd.Flags().Bool("json", false, "Output preview as JSON")
+	cmd.Flags().StringArray("var", nil, "Override a [variables] value (key=value, repeatable)")
+	cmd.Flags().StringArray("param", nil, "Set a task parameter (key=value, repeatable)")
+
+	return cmd
+}
+
+// newShowPromptCommand creates the show prompt command
+func (sc *ShowCommand) newShowPromptCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prompt [text]",
+		Short: "Preview prompt execution",
+		Long:  "Show what 'start prompt [text]' would execute without running the agent",
+		Args:  cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, _, _, err := loadMergedConfig(sc.configLoader, sc.validator)
+			if err != nil {
+				return err
+			}
+
+			return sc.preview(cmd, cfg, launchRequest{
+				CommandType: engine.CommandTypePrompt,
+				UserPrompt:  strings.Join(args, " "),
+			})
+		},
+	}
+
+	cmd.Flags().Bool("verbose", false, "Show full content (no truncation)")
+	cmd.Flags().Bool("json", false, "Output preview as JSON")
+
+	return cmd
+}
+
+// previewContext is the JSON form of a loaded context
+type previewContext struct {
+	Name       string   `json:"name"`
+	File       string   `json:"file,omitempty"`
+	Size       int      `json:"size"`
+	Required   bool     `json:"required"`
+	Skipped    bool     `json:"skipped"`
+	SkipReason string   `json:"skip_reason,omitempty"`
+	Cached     bool     `json:"cached"`
+	Warnings   []string `json:"warnings"`
+	Content    string   `json:"content"`
+}
+
+// previewOutput is the JSON form of an execution preview
+type previewOutput struct {
+	Mode       string            `json:"mode"`
+	Agent      string            `json:"agent"`
+	Model      string            `json:"model"`
+	ModelID    string            `json:"model_id"`
+	Role       string            `json:"role"`
+	RoleSource string            `json:"role_source"`
+	RoleFile   string            `json:"role_file,omitempty"`
+	RoleBody   string            `json:"role_content"`
+	RoleCached bool              `json:"role_cached"`
+	Task       string            `json:"task,omitempty"`
+	TaskPrompt string            `json:"task_prompt,omitempty"`
+	TaskCached bool              `json:"task_cached,omitempty"`
+	TaskParams map[string]string `json:"task_params,omitempty"`
+	Contexts   []previewContext  `json:"contexts"`
+	Scope      string            `json:"context_scope"`
+	Prompt     string            `json:"prompt"`
+	Shell      string            `json:"shell"`
+	Command    string            `json:"command"`
+	Delivery   string            `json:"prompt_delivery"`
+	Layout     string            `json:"prompt_layout"`
+	Warnings   []string          `json:"warnings"`
+	Error      string            `json:"error,omitempty"`
+}
+
+// preview runs the selection path and prints what would execute
+func (sc *ShowCommand) preview(cmd *cobra.Command, cfg domain.Config, req launchRequest) error {
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	asJSON, _ := cmd.Flags().GetBool("json")
+	req.AgentFlag, _ = cmd.Flags().GetString("agent")
+	req.ModelFlag, _ = cmd.Flags().GetString("model")
+	req.RoleFlag, _ = cmd.Flags().GetString("role")
+	req.Contexts = contextSelection(cmd)
+
+	launch, err := sc.launcher.prepare(cfg, req)
+	if err != nil {
+		return err
+	}
+	defer sc.launcher.cleanup(launch)
+
+	// Resolve the command exactly as Executor would, without executing
+	prepared, prepErr := sc.executor.Prepare(launch.Params)
+	defer sc.executor.Cleanup(prepared)
+
+	out := buildPreviewOutput(req, launch, prepared, prepErr)
+
+	if asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(out); err != nil {
+			return fmt.Errorf("failed to encode preview: %w", err)
+		}
+		return prepErr
+	}
+
+	printPreview(out, verbose)
+	return prepErr
+}
+
+// buildPreviewOutput collects preview details into a single structure
+func buildPreviewOutput(req launchRequest, launch preparedLaunch, prepared engine.PreparedCommand, prepErr error) previewOutput {
+	out := previewOutput{
+		Mode:       string(req.CommandType),
+		Agent:      launch.Agent.Name,
+		Model:      launch.ModelName,
+		ModelID:    launch.ModelID,
+		Role:       launch.Role.Name,
+		RoleSource: launch.RoleSource,
+		RoleFile:   launch.Role.FilePath,
+		RoleBody:   launch.Role.Content,
+		RoleCached: launch.Role.Cached,
+		Contexts:   []previewContext{},
+		Scope:      contextScope(req),
+		Prompt:     prepared.Prompt,
+		Shell:      prepared.Shell,
+		Command:    prepared.Command,
+		Delivery:   prepared.Delivery,
+		Layout:     describePromptLayout(launch.Params.Layout),
+		Warnings:   append(append([]string{}, launch.Warnings...), launch.Role.Warnings...),
+	}
+
+	if launch.Task != nil {
+		out.Task = launch.Task.Name
+		out.TaskPrompt = launch.Task.Prompt
+		out.TaskCached = launch.Task.Cached
+		out.TaskParams = launch.Task.Params
+		out.Warnings = append(out.Warnings, launch.Task.Warnings...)
+	}
+
+	for _, ctx := range launch.Contexts {
+		size := len(ctx.Content)
+		if ctx.FilePath != "" {
+			if info, err := os.Stat(ctx.FilePath); err == nil {
+				size = int(info.Size())
+			}
+		}
+		warnings := ctx.Warnings
+		if warnings == nil {
+			warnings = []string{}
+		}
+		out.Contexts = append(out.Contexts, previewContext{
+			Name:       ctx.Name,
+			File:       ctx.FilePath,
+			Size:       size,
+			Required:   ctx.Required,
+			Skipped:    ctx.Skipped,
+			SkipReason: ctx.SkipReason,
+			Cached:     ctx.Cached,
+			Warnings:   warnings,
+			Content:    ctx.Content,
+		})
+	}
+
+	out.Warnings = append(out.Warnings, prepared.Warnings...)
+
+	if prepErr != nil {
+		out.Error = prepErr.Error()
+	}
+
+	return out
+}
+
+// contextScope describes which contexts a request loads
+func contextScope(req launchRequest) string {
+	sel := req.Contexts
+	if (req.Task != nil && len(req.Task.Contexts) > 0) || len(sel.Include) > 0 || len(sel.Exclude) > 0 || len(sel.Tags) > 0 || sel.NoDefaults {
+		return "selected"
+	}
+	if req.CommandType != engine.CommandTypeInteractive {
+		return "required only"
+	}
+	return "all"
+}
+
+// printPreview prints an execution preview in human-readable form
+func printPreview(out previewOutput, verbose bool) {
+	subject := "AI Agent"
+	if out.Mode == string(engine.CommandTypePrompt) {
+		subject = "AI Agent with Custom Prompt"
+	}
+	if out.Task != "" {
+		subject = "Task: " + out.Task
+	}
+	fmt.Printf("Starting %s (PREVIEW - NOT EXECUTING)\n", subject)
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Printf("Agent: %s (model: %s)\n", out.Agent, out.ModelID)
+	fmt.Printf("Role: %s (from %s)\n", out.Role, out.RoleSource)
+	if len(out.TaskParams) > 0 {
+		fmt.Printf("Parameters: %s\n", formatParamValues(out.TaskParams))
+	}
+	if out.Shell != "" {
+		fmt.Printf("Shell: %s\n", out.Shell)
+	} else {
+		fmt.Println("Shell: none (args executed directly)")
+	}
+	if out.Delivery != "" && out.Delivery != engine.PromptDeliveryArg {
+		fmt.Printf("Prompt delivery: %s\n", out.Delivery)
+	}
+	if out.Layout != engine.PromptStylePlain {
+		fmt.Printf("Prompt layout: %s\n", out.Layout)
+	}
+	fmt.Println()
+
+	fmt.Printf("Context documents (%s):\n", out.Scope)
+	if len(out.Contexts) == 0 {
+		fmt.Println("  (none)")
+	}
+	for _, ctx := range out.Contexts {
+		mark := "✓"
+		if ctx.Skipped {
+			mark = "✗"
+		}
+		required := "optional"
+		if ctx.Required {
+			required = "required"
+		}
+		source := ctx.File
+		if source == "" {
+			source = "(no file)"
+		}
+		if ctx.SkipReason != "" {
+			fmt.Printf("  - %-15s skipped, when %s (%s)\n", ctx.Name, ctx.SkipReason, required)
+			continue
+		}
+		fmt.Printf("  %s %-15s %s (%s, %s)%s\n", mark, ctx.Name, source, formatSize(ctx.Size), required, cachedLabel(ctx.Cached))
+		for _, w := range ctx.Warnings {
+			fmt.Printf("    ⚠ %s\n", w)
+		}
+	}
+	fmt.Println()
+
+	for _, w := range out.Warnings {
+		fmt.Printf("⚠ %s\n", w)
+	}
+	if len(out.Warnings) > 0 {
+		fmt.Println()
+	}
+
+	printContentBlock("Resolved role content"+cachedLabel(out.RoleCached), out.RoleBody, verbose)
+	if out.Task != "" {
+		printContentBlock("Task prompt"+cachedLabel(out.TaskCached), out.TaskPrompt, verbose)
+	}
+	printContentBlock("Composed prompt", out.Prompt, verbose)
+
+	if out.Error != "" {
+		fmt.Println("✗ Command cannot be built:")
+		fmt.Println(out.Error)
+		fmt.Println()
+		return
+	}
+
+	fmt.Println("Command that would execute:")
+	fmt.Printf("❯ %s\n", truncateLines(out.Command, verbose))
+	fmt.Println()
+	fmt.Println("PREVIEW ONLY: Agent not executed")
+	if !verbose {
+		fmt.Println("Use 'start show --verbose' for full content")
+	}
+}
+
+// printContentBlock prints a titled block of content, truncated unless verbose
+func printContentBlock(title, content string, verbose bool) {
+	if verbose {
+		fmt.Printf("%s (full):\n", title)
+	} else {
+		fmt.Printf("%s (first %d lines):\n", title, previewLineLimit)
+	}
+	fmt.Println("─────────────────────────────────────────────────")
+	if content != "" {
+		fmt.Println(truncateLines(content, verbose))
+	}
+	fmt.Println("─────────────────────────────────────────────────")
+	fmt.Println()
+}
+
+// truncateLines limits content to previewLineLimit lines unless verbose
+func truncateLines(content string, verbose bool) string {
+	lines := strings.Split(content, "\n")
+	if verbose || len(lines) <= previewLineLimit {
+		return content
+	}
+	remaining := len(lines) - previewLineLimit
+	return strings.Join(lines[:previewLineLimit], "\n") +
+		fmt.Sprintf("\n... (%d more lines) - Use --verbose to see full content", remaining)
+}
+
+// describePromptLayout summarises a prompt layout, e.g. "xml, role in prompt, header"
+func describePromptLayout(layout domain.PromptLayout) string {
+	parts := []string{engine.PromptStylePlain}
+	if layout.Style != "" {
+		parts[0] = layout.Style
+	}
+	if layout.RolePlacement == engine.RolePlacementPrompt {
+		parts = append(parts, "role in prompt")
+	}
+	if layout.Header != "" {
+		parts = append(parts, "header")
+	}
+	if layout.Footer != "" {
+		parts = append(parts, "footer")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// cachedLabel marks content whose command output was served from the cache
+func cachedLabel(cached bool) string {
+	if cached {
+		return " [cached]"
+	}
+	return ""
+}
+
+// formatSize formats a byte count for display
+func formatSize(size int) string {
+	if size < 1024 {
+		return fmt.Sprintf("%d bytes", size)
+	}
+	return fmt.Sprintf("%.1f KB", float64(size)/1024)
+}
+
+// loadScopedConfig loads config for a --scope value (global, local, or merged when empty)
+// Returns the config and the local config used to label sources
+func (sc *ShowCommand) loadScopedConfig(scope string) (domain.Config, domain.Config, error) {
+	workDir, err := os.Getwd()
+	if err != nil {
+		return domain.Config{}, domain.Config{}, fmt.Errorf("failed to get working directory: %w", err)
+	}
+
+	switch scope {
+	case "global":
+		globalCfg, err := sc.configLoader.LoadGlobal()
+		if err != nil {
+			return domain.Config{}, domain.Config{}, fmt.Errorf("failed to load global config: %w", err)
+		}
+		globalCfg.Agents = config.ResolveAgents(globalCfg.Agents)
+		return globalCfg, domain.Config{}, nil
+	case "local":
+		localCfg, err := sc.configLoader.LoadLocal(workDir)
+		if err != nil {
+			return domain.Config{}, domain.Config{}, fmt.Errorf("failed to load local config: %w", err)
+		}
+		return localCfg, localCfg, nil
+	case "":
+		globalCfg, err := sc.configLoader.LoadGlobal()
+		if err != nil {
+			return domain.Config{}, domain.Config{}, fmt.Errorf("failed to load global config: %w", err)
+		}
+		localCfg, err := sc.configLoader.LoadLocal(workDir)
+		if err != nil {
+			globalCfg.Agents = config.ResolveAgents(globalCfg.Agents)
+			return globalCfg, domain.Config{}, nil
+		}
+		return config.Merge(globalCfg, localCfg), localCfg, nil
+	default:
+		return domain.Config{}, domain.Config{}, fmt.Errorf("invalid scope %q: must be 'global' or 'local'", scope)
+	}
+}
+
+// scopeLabel returns the heading label for a --scope value
+func scopeLabel(scope string) string {
+	if scope == "" {
+		return "effective"
+	}
+	return scope
+}
+
+// sourceLabel returns "local config" or "global config" for a named item
+func sourceLabel(inLocal bool) string {
+	if inLocal {
+		return "local config"
+	}
+	return "global config"
+}
+
+// newShowRoleCommand creates the show role command
+func (sc *ShowCommand) newShowRoleCommand() *cobra.Command {
+	var scope string
+
+	cmd := &cobra.Command{
+		Use:   "role [name]",
+		Short: "Display resolved role content",
+		Long:  "Show role content after UTD processing and config merging. Shows the default role if no name is given.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, localCfg, err := sc.loadScopedConfig(scope)
+			if err != nil {
+				return err
+			}
+
+			roleFlag := ""
+			if len(args) > 0 {
+				if roleFlag, err = resolveName("role", args[0], roleCandidates(cfg)); err != nil {
+					return err
+				}
+			}
+			role, err := sc.roleSelector.Select(engine.SelectionContext{
+				RoleFlag:    roleFlag,
+				DefaultRole: cfg.Settings.DefaultRole,
+				RoleOrder:   cfg.RoleOrder,
+			}, cfg.Roles)
+			if err != nil {
+				return err
+			}
+
+			shell, timeout := shellAndTimeout(cfg)
+			loaded, err := sc.roleLoader.LoadRole(role, cfg.Roles, shell, timeout, cfg.Variables)
+			if err != nil {
+				return fmt.Errorf("failed to load role: %w", err)
+			}
+			defer sc.roleLoader.CleanupRole(loaded)
+
+			_, inLocal := localCfg.Roles[role.Name]
+
+			fmt.Printf("Role: %s (%s)\n", role.Name, scopeLabel(scope))
+			fmt.Println("═══════════════════════════════════════════════════════════")
+			fmt.Printf("Source: %s\n", sourceLabel(inLocal))
+			fmt.Printf("Type: %s\n", getRoleSourceType(role))
+			if ok, reason := sc.roleLoader.Applies(role, shell, timeout); !ok {
+				fmt.Printf("✗ Does not apply here, when %s\n", reason)
+			}
+			if loaded.Cached {
+				fmt.Println("Command output: cached")
+			}
+			for _, w := range loaded.Warnings {
+				fmt.Printf("⚠ %s\n", w)
+			}
+			fmt.Println()
+			fmt.Println(loaded.Content)
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&scope, "scope", "", "Show from specific scope (global or local)")
+
+	return cmd
+}
+
+// newShowContextCommand creates the show context command
+func (sc *ShowCommand) newShowContextCommand() *cobra.Command {
+	var scope string
+
+	cmd := &cobra.Command{
+		Use:   "context [name]",
+		Short: "Display resolved context content",
+		Long:  "Show context content after UTD processing and config merging. Shows all contexts if no name is given.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, localCfg, err := sc.loadScopedConfig(scope)
+			if err != nil {
+				return err
+			}
+
+			order := cfg.ContextOrder
+			if len(args) > 0 {
+				name, err := resolveName("context", args[0], contextCandidates(cfg))
+				if err != nil {
+					return err
+				}
+				order = []string{name}
+			}
+
+			shell, timeout := shellAndTimeout(cfg)
+			loaded := sc.contextLoader.LoadContexts(cfg.Contexts, order, engine.CommandTypeInteractive, shell, timeout, contextLoadOptions(cfg))
+
+			if len(args) == 0 {
+				fmt.Printf("Contexts (%s - %d total)\n", scopeLabel(scope), len(loaded))
+				fmt.Println("═══════════════════════════════════════════════════════════")
+				fmt.Println()
+			}
+
+			for _, ctx := range loaded {
+				_, inLocal := localCfg.Contexts[ctx.Name]
+				required := "optional"
+				if ctx.Required {
+					required = "required"
+				}
+
+				if len(args) > 0 {
+					fmt.Printf("Context: %s (%s)\n", ctx.Name, scopeLabel(scope))
+					fmt.Println("═══════════════════════════════════════════════════════════")
+					fmt.Printf("Source: %s\n", sourceLabel(inLocal))
+					fmt.Printf("Required: %t\n", ctx.Required)
+					if ctx.FilePath != "" {
+						fmt.Printf("File: %s\n", ctx.FilePath)
+					}
+					if ctx.Cached {
+						fmt.Println("Command output: cached")
+					}
+					fmt.Println()
+				} else {
+					scopeName := "global"
+					if inLocal {
+						scopeName = "local"
+					}
+					fmt.Printf("%s (%s, %s)%s\n", ctx.Name, scopeName, required, cachedLabel(ctx.Cached))
+				}
+
+				if ctx.SkipReason != "" {
+					fmt.Printf("✗ Skipped, when %s\n", ctx.SkipReason)
+				}
+				for _, w := range ctx.Warnings {
+					fmt.Printf("⚠ %s\n", w)
+				}
+				fmt.Println("─────────────────────────────────────────────────")
+				if ctx.Content != "" {
+					fmt.Println(ctx.Content)
+				}
+				fmt.Println("─────────────────────────────────────────────────")
+				fmt.Println()
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&scope, "scope", "", "Show from specific scope (global or local)")
+
+	return cmd
+}
+
+// newShowAgentCommand creates the show agent command
+func (sc *ShowCommand) newShowAgentCommand() *cobra.Command {
+	var scope string
+
+	cmd := &cobra.Command{
+		Use:   "agent [name]",
+		Short: "Display effective agent configuration",
+		Long:  "Show agent configuration after config merging. Shows the default agent if no name is given.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, localCfg, err := sc.loadScopedConfig(scope)
+			if err != nil {
+				return err
+			}
+
+			agentFlag := ""
+			if len(args) > 0 {
+				agentFlag = args[0]
+			}
+			agent, err := selectAgent(cfg, agentFlag, "")
+			if err != nil {
+				return err
+			}
+
+			_, inLocal := localCfg.Agents[agent.Name]
+
+			fmt.Printf("Agent: %s (%s)\n", agent.Name, scopeLabel(scope))
+			fmt.Println("═══════════════════════════════════════════════════════════")
+			fmt.Printf("Source: %s\n", sourceLabel(inLocal))
+			if agent.Description != "" {
+				fmt.Printf("Description: %s\n", agent.Description)
+			}
+			if agent.URL != "" {
+				fmt.Printf("URL: %s\n", agent.URL)
+			}
+			fmt.Println()
+
+			if len(agent.Args) > 0 {
+				fmt.Println("Args template (no shell):")
+				fmt.Printf("  %s\n", formatArgsTemplate(agent.Args))
+			} else {
+				fmt.Println("Command template:")
+				fmt.Printf("  %s\n", agent.Command)
+			}
+			fmt.Println()
+
+			layout := config.MergePromptLayout(cfg.Settings.PromptLayout, agent.PromptLayout)
+			if describePromptLayout(layout) != engine.PromptStylePlain {
+				source := "settings"
+				if agent.PromptLayout != nil {
+					source = "agent override"
+				}
+				fmt.Printf("Prompt layout: %s (%s)\n", describePromptLayout(layout), source)
+				fmt.Println()
+			}
+
+			if agent.DefaultModel != "" {
+				fmt.Printf("Default model: %s (%s)\n", agent.Models[agent.DefaultModel], agent.DefaultModel)
+				fmt.Println()
+			}
+
+			if len(agent.Models) > 0 {
+				names := make([]string, 0, len(agent.Models))
+				width := 0
+				for name := range agent.Models {
+					names = append(names, name)
+					width = max(width, len(name))
+				}
+				sort.Strings(names)
+
+				fmt.Println("Models:")
+				for _, name := range names {
+					fmt.Printf("  %-*s → %s\n", width, name, agent.Models[name])
+				}
+			}
+
+			if agent.ModelsURL != "" {
+				fmt.Println()
+				fmt.Printf("Model docs: %s\n", agent.ModelsURL)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&scope, "scope", "", "Show from specific scope (global or local)")
+
+	return cmd
+}
+
+// formatParamValues formats task parameter values as "name=value" in name order
+func formatParamValues(values map[string]string) string {
+	var names []string
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var pairs []string
+	for _, name := range names {
+		pairs = append(pairs, name+"="+values[name])
+	}
+	return strings.Join(pairs, ", ")
+}
+
+// previewStep is the JSON form of a pipeline step
+type previewStep struct {
+	Name        string `json:"name"`
+	Task        string `json:"task,omitempty"`
+	Prompt      string `json:"prompt,omitempty"`
+	Agent       string `json:"agent,omitempty"`
+	Role        string `json:"role,omitempty"`
+	OnFailure   string `json:"on_failure"`
+	Retries     int    `json:"retries,omitempty"`
+	Interactive bool   `json:"interactive"`
+}
+
+// printStepPlan prints the steps a pipeline task would run
+func printStepPlan(task domain.Task, asJSON bool) error {
+	if asJSON {
+		out := struct {
+			Mode  string        `json:"mode"`
+			Task  string        `json:"task"`
+			Steps []previewStep `json:"steps"`
+		}{Mode: string(engine.CommandTypeTask), Task: task.Name}
+		for _, step := range task.Steps {
+			out.Steps = append(out.Steps, previewStep{
+				Name:        step.Name,
+				Task:        step.Task,
+				Prompt:      step.Prompt,
+				Agent:       step.Agent,
+				Role:        step.Role,
+				OnFailure:   engine.StepOnFailure(step),
+				Retries:     step.Retries,
+				Interactive: step.Interactive,
+			})
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(out); err != nil {
+			return fmt.Errorf("failed to encode preview: %w", err)
+		}
+		return nil
+	}
+
+	fmt.Printf("Starting Task: %s (PREVIEW - NOT EXECUTING)\n", task.Name)
+	fmt.Println("═══════════════════════════════════════════════════════════")
+	fmt.Printf("Steps (%d):\n", len(task.Steps))
+	for i, step := range task.Steps {
+		fmt.Printf("  %d. %s\n", i+1, describeStep(step))
+	}
+	fmt.Println()
+	fmt.Println("Steps run headless, {step:<name>} is replaced with that step's output.")
+	fmt.Println("Use 'start show task <name>' on a step's task to preview its prompt.")
+	fmt.Println()
+	fmt.Println("PREVIEW ONLY: Agent not executed")
+
+	return nil
+}
+
+// describeStep summarises a pipeline step on one line
+func describeStep(step domain.TaskStep) string {
+	source := fmt.Sprintf("prompt (%d chars)", len(step.Prompt))
+	if step.Task != "" {
+		source = "task: " + step.Task
+	}
+
+	var details []string
+	if step.Agent != "" {
+		details = append(details, "agent: "+step.Agent)
+	}
+	if step.Role != "" {
+		details = append(details, "role: "+step.Role)
+	}
+	onFailure := engine.StepOnFailure(step)
+	if onFailure == engine.StepOnFailureRetry && step.Retries > 0 {
+		onFailure = fmt.Sprintf("retry x%d", step.Retries)
+	}
+	details = append(details, "on failure: "+onFailure)
+	if step.Interactive {
+		details = append(details, "interactive")
+	}
+
+	return fmt.Sprintf("%-15s %s (%s)", step.Name, source, strings.Join(details, ", "))
+}
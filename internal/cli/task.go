@@ -1,8 +1,8 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
-	"sort"
 	"strings"
 
 	"github.com/grantcarthew/start/internal/config"
@@ -13,14 +13,15 @@ import (
 
 // TaskCommand holds the dependencies for the task command
 type TaskCommand struct {
-	configLoader   *config.Loader
-	validator      *config.Validator
-	executor       *engine.Executor
-	roleSelector   *engine.RoleSelector
-	roleLoader     *engine.RoleLoader
-	contextLoader  *engine.ContextLoader
-	taskLoader     *engine.TaskLoader
-	taskResolver   *engine.TaskResolver
+	configLoader  *config.Loader
+	validator     *config.Validator
+	executor      *engine.Executor
+	roleSelector  *engine.RoleSelector
+	roleLoader    *engine.RoleLoader
+	contextLoader *engine.ContextLoader
+	taskLoader    *engine.TaskLoader
+	taskResolver  *engine.TaskResolver
+	launcher      *launcher
 }
 
 // NewTaskCommand creates the task command
@@ -33,20 +34,28 @@ func NewTaskCommand(
 	contextLoader *engine.ContextLoader,
 	taskLoader *engine.TaskLoader,
 	taskResolver *engine.TaskResolver,
+	modelCatalog *engine.ModelCatalog,
 ) *cobra.Command {
 	tc := &TaskCommand{
-		configLoader:   configLoader,
-		validator:      validator,
-		executor:       executor,
-		roleSelector:   roleSelector,
-		roleLoader:     roleLoader,
-		contextLoader:  contextLoader,
-		taskLoader:     taskLoader,
-		taskResolver:   taskResolver,
+		configLoader:  configLoader,
+		validator:     validator,
+		executor:      executor,
+		roleSelector:  roleSelector,
+		roleLoader:    roleLoader,
+		contextLoader: contextLoader,
+		taskLoader:    taskLoader,
+		taskResolver:  taskResolver,
+		launcher: &launcher{
+			roleSelector:  roleSelector,
+			roleLoader:    roleLoader,
+			contextLoader: contextLoader,
+			taskLoader:    taskLoader,
+			modelCatalog:  modelCatalog,
+		},
 	}
 
 	cmd := &cobra.Command{
-		Use:   "task [name] [instructions]",
+		Use:   "task [name] [required params...] [instructions]",
 		Short: "Run predefined AI workflow tasks",
 		Long: `Executes predefined AI workflow tasks configured in tasks.toml.
 
@@ -57,36 +66,44 @@ Examples:
   start task                              # List all tasks
   start task code-review                  # Run task
   start task gdr "focus on security"      # Run with instructions
-  start task code-review --agent gemini   # Override agent`,
+  start task code-review --agent gemini   # Override agent
+  start task code-review --output r.md    # Run headless, save the answer
+  start task jira --var project=OPS       # Override a [variables] value
+  start task review --param scope=api     # Set a task parameter
+  start task review --help                # Show a task's parameters
+  start task review --agents claude,gemini # Compare agents, results in start-agents/`,
 		RunE: tc.run,
 		Args: cobra.ArbitraryArgs,
 	}
 
+	addHeadlessFlags(cmd)
+	addFanOutFlags(cmd)
+	cmd.Flags().StringArray("var", nil, "Override a [variables] value (key=value, repeatable)")
+	cmd.Flags().StringArray("param", nil, "Set a task parameter (key=value, repeatable)")
+
+	// start task <name> --help describes the task instead of the command
+	defaultHelp := cmd.HelpFunc()
+	cmd.SetHelpFunc(func(c *cobra.Command, args []string) {
+		if names := c.Flags().Args(); len(names) > 0 && tc.showTaskHelp(names[0]) {
+			return
+		}
+		defaultHelp(c, args)
+	})
+
 	return cmd
 }
 
 // run executes the task command
 func (tc *TaskCommand) run(cmd *cobra.Command, args []string) error {
-	// Load configuration
-	globalCfg, err := tc.configLoader.LoadGlobal()
+	overrides, err := variableOverrides(cmd)
 	if err != nil {
-		return fmt.Errorf("failed to load global config: %w", err)
+		return err
 	}
 
-	// Get current working directory for local config
-	workDir := "."
-	localCfg, err := tc.configLoader.LoadLocal(workDir)
+	// Load and validate configuration
+	cfg, globalCfg, localCfg, err := loadMergedConfigWithVars(tc.configLoader, tc.validator, overrides)
 	if err != nil {
-		// Local config is optional, use empty config
-		localCfg = globalCfg
-	}
-
-	// Merge configs
-	cfg := config.Merge(globalCfg, localCfg)
-
-	// Validate merged config
-	if err := tc.validator.Validate(cfg); err != nil {
-		return fmt.Errorf("config validation failed: %w", err)
+		return err
 	}
 
 	// If no arguments, list tasks
@@ -96,122 +113,67 @@ func (tc *TaskCommand) run(cmd *cobra.Command, args []string) error {
 
 	// Resolve task
 	taskName := args[0]
-	task, err := tc.taskResolver.Resolve(taskName, localCfg.Tasks, globalCfg.Tasks)
-	if err != nil {
-		return tc.taskNotFoundError(taskName, cfg)
+	task, err := resolveTask(tc.taskResolver, taskName, cfg, globalCfg, localCfg)
+	var unknown *engine.UnknownNameError
+	if errors.As(err, &unknown) {
+		return tc.taskNotFoundError(taskName, unknown.Suggestion, cfg)
+	} else if err != nil {
+		return err
 	}
 
-	// Get instructions (remaining args joined)
-	instructions := ""
-	if len(args) > 1 {
-		instructions = strings.Join(args[1:], " ")
+	// Parameters come from --param and leading args, the rest are instructions
+	params, instructions, err := resolveTaskArgs(cmd, tc.taskLoader, task, args[1:])
+	if err != nil {
+		return err
 	}
 
-	// Get flags
-	agentFlag, _ := cmd.Flags().GetString("agent")
-	modelFlag, _ := cmd.Flags().GetString("model")
-	roleFlag, _ := cmd.Flags().GetString("role")
-
-	// Select agent with precedence: --agent flag > task agent > default_agent > first agent
-	var agentName string
-	if agentFlag != "" {
-		agentName = agentFlag
-	} else if task.Agent != "" {
-		agentName = task.Agent
-	} else if cfg.Settings.DefaultAgent != "" {
-		agentName = cfg.Settings.DefaultAgent
-	} else {
-		return fmt.Errorf("no agent specified and no default agent configured")
+	agents, err := fanOutAgents(cmd)
+	if err != nil {
+		return err
 	}
 
-	// Get agent from config
-	agent, ok := cfg.Agents[agentName]
-	if !ok {
-		return fmt.Errorf("agent %q not found in configuration", agentName)
-	}
-	agent.Name = agentName
-
-	// Select model
-	var modelID string
-	if modelFlag != "" {
-		// Check if it's a model name (needs resolution) or full ID
-		if fullID, ok := agent.Models[modelFlag]; ok {
-			modelID = fullID
-		} else {
-			// Assume it's a full model ID
-			modelID = modelFlag
-		}
-	} else if agent.DefaultModel != "" {
-		// Use default model
-		if fullID, ok := agent.Models[agent.DefaultModel]; ok {
-			modelID = fullID
-		} else {
-			return fmt.Errorf("default model %q not found in agent %q models", agent.DefaultModel, agentName)
+	// Pipeline tasks run their steps instead of a single agent
+	if len(task.Steps) > 0 {
+		if len(agents) > 0 {
+			return fmt.Errorf("--agents cannot be used with task %q, it runs steps", task.Name)
 		}
-	} else {
-		return fmt.Errorf("no model specified and no default model for agent %q", agentName)
-	}
-
-	// Select role with precedence: --role flag > task role > default_role > first role
-	selectionCtx := engine.SelectionContext{
-		RoleFlag:    roleFlag,
-		TaskRole:    task.Role,
-		DefaultRole: cfg.Settings.DefaultRole,
-	}
-	role, err := tc.roleSelector.Select(selectionCtx, cfg.Roles)
-	if err != nil {
-		return fmt.Errorf("role selection failed: %w", err)
+		return tc.runSteps(cmd, cfg, globalCfg, localCfg, task, params, instructions)
 	}
 
-	// Get shell and timeout settings
-	shell := cfg.Settings.Shell
-	if shell == "" {
-		shell = engine.DetectShell()
-	}
-	timeout := cfg.Settings.CommandTimeout
-	if timeout == 0 {
-		timeout = 30 // Default 30 seconds
+	// Get flags
+	agentFlag, _ := cmd.Flags().GetString("agent")
+	if len(agents) > 0 {
+		agentFlag = agents[0]
 	}
+	modelFlag, _ := cmd.Flags().GetString("model")
+	roleFlag, _ := cmd.Flags().GetString("role")
 
-	// Load role
-	loadedRole, err := tc.roleLoader.LoadRole(role, shell, timeout)
+	// Select agent (--agent flag > task agent > default_agent), model and
+	// role (--role flag > task role > default_role), then load required
+	// contexts and the task prompt
+	launch, err := tc.launcher.prepare(cfg, launchRequest{
+		AgentFlag:    agentFlag,
+		ModelFlag:    modelFlag,
+		RoleFlag:     roleFlag,
+		CommandType:  engine.CommandTypeTask,
+		Task:         &task,
+		Instructions: instructions,
+		Params:       params,
+		Contexts:     contextSelection(cmd),
+		FanOut:       len(agents) > 0,
+	})
 	if err != nil {
-		return fmt.Errorf("failed to load role: %w", err)
+		return err
 	}
 	// Cleanup temp role file if needed (deferred)
-	defer tc.roleLoader.CleanupRole(loadedRole)
-
-	// Load required contexts only (tasks use CommandTypeTask)
-	contexts := tc.contextLoader.LoadContexts(
-		cfg.Contexts,
-		cfg.ContextOrder,
-		engine.CommandTypeTask,
-		shell,
-		timeout,
-	)
-
-	// Load task with instructions
-	loadedTask, err := tc.taskLoader.LoadTask(task, instructions, shell, timeout)
-	if err != nil {
-		return fmt.Errorf("failed to load task: %w", err)
-	}
-
-	// Execute agent (replaces current process, never returns on success)
-	execParams := engine.ExecuteParams{
-		Agent:        agent,
-		Model:        modelID,
-		UserPrompt:   loadedTask.Prompt,
-		RoleContent:  loadedRole.Content,
-		RoleFilePath: loadedRole.FilePath,
-		Contexts:     contexts,
-		Shell:        shell,
-	}
+	defer tc.launcher.cleanup(launch)
 
-	if err := tc.executor.Execute(execParams); err != nil {
-		return fmt.Errorf("execution failed: %w", err)
+	if len(agents) > 0 {
+		return runFanOut(cmd, tc.executor, tc.launcher.modelCatalog, cfg, launch, agents)
 	}
 
-	return nil
+	// Execute agent (replaces current process unless running headless)
+	return runAgent(cmd, tc.executor, launch)
 }
 
 // listTasks displays all configured tasks
@@ -226,12 +188,8 @@ func (tc *TaskCommand) listTasks(cfg, globalCfg, localCfg domain.Config) error {
 		return nil
 	}
 
-	// Sort task names
-	var names []string
-	for name := range allTasks {
-		names = append(names, name)
-	}
-	sort.Strings(names)
+	// Definition order, explicit order first
+	names := orderedNames(allTasks, cfg.TaskOrder)
 
 	fmt.Println("Available tasks:")
 	for _, name := range names {
@@ -245,6 +203,12 @@ func (tc *TaskCommand) listTasks(cfg, globalCfg, localCfg domain.Config) error {
 			descStr = fmt.Sprintf(" - %s", task.Description)
 		}
 		fmt.Printf("  %s%s%s\n", name, aliasStr, descStr)
+		if len(task.Params) > 0 {
+			fmt.Printf("      params: %s\n", describeParams(task))
+		}
+		if len(task.Steps) > 0 {
+			fmt.Printf("      steps: %s\n", stepNames(task))
+		}
 	}
 	fmt.Println()
 	fmt.Println("Use 'start task <name>' to run a task.")
@@ -252,19 +216,134 @@ func (tc *TaskCommand) listTasks(cfg, globalCfg, localCfg domain.Config) error {
 	return nil
 }
 
+// resolveTaskArgs splits task arguments into parameter values (--param, and
+// positional for required params) and instructions (the remaining args)
+func resolveTaskArgs(cmd *cobra.Command, taskLoader *engine.TaskLoader, task domain.Task, args []string) (map[string]string, string, error) {
+	flags, _ := cmd.Flags().GetStringArray("param")
+	supplied, err := engine.ParseParamFlags(flags)
+	if err != nil {
+		return nil, "", err
+	}
+
+	params, rest, err := taskLoader.ResolveParams(task, supplied, args)
+	if err != nil {
+		return nil, "", err
+	}
+
+	return params, strings.Join(rest, " "), nil
+}
+
+// describeParams lists a task's parameters in usage form
+func describeParams(task domain.Task) string {
+	var usages []string
+	for _, name := range engine.ParamNames(task) {
+		usages = append(usages, engine.DescribeParam(name, task.Params[name]))
+	}
+	return strings.Join(usages, ", ")
+}
+
+// stepNames lists a pipeline task's steps in run order
+func stepNames(task domain.Task) string {
+	var names []string
+	for _, step := range task.Steps {
+		names = append(names, step.Name)
+	}
+	return strings.Join(names, " → ")
+}
+
+// showTaskHelp prints help for a single task, returning false if it cannot be resolved
+func (tc *TaskCommand) showTaskHelp(name string) bool {
+	cfg, globalCfg, localCfg, err := loadMergedConfig(tc.configLoader, tc.validator)
+	if err != nil {
+		return false
+	}
+	task, err := resolveTask(tc.taskResolver, name, cfg, globalCfg, localCfg)
+	if err != nil {
+		return false
+	}
+
+	aliasStr := ""
+	if task.Alias != "" {
+		aliasStr = fmt.Sprintf(" (alias: %s)", task.Alias)
+	}
+	fmt.Printf("Task: %s%s\n", task.Name, aliasStr)
+
+	if task.Description != "" {
+		fmt.Println()
+		fmt.Println("Description:")
+		fmt.Printf("  %s\n", task.Description)
+	}
+
+	fmt.Println()
+	fmt.Println("Configuration:")
+	if task.Role != "" {
+		fmt.Printf("  Role: %s\n", task.Role)
+	} else {
+		fmt.Println("  Role: (default role)")
+	}
+	if task.Agent != "" {
+		fmt.Printf("  Agent: %s\n", task.Agent)
+	}
+	if task.Command != "" {
+		fmt.Printf("  Command: %s\n", task.Command)
+	} else if len(task.Steps) == 0 {
+		fmt.Println("  Command: (none)")
+	}
+
+	if len(task.Steps) > 0 {
+		fmt.Println()
+		fmt.Println("Steps:")
+		for i, step := range task.Steps {
+			fmt.Printf("  %d. %s\n", i+1, describeStep(step))
+		}
+	}
+
+	names := engine.ParamNames(task)
+	if len(names) > 0 {
+		fmt.Println()
+		fmt.Println("Parameters:")
+		for _, paramName := range names {
+			param := task.Params[paramName]
+			fmt.Printf("  %s\n", engine.DescribeParam(paramName, param))
+			if param.Description != "" {
+				fmt.Printf("      %s\n", param.Description)
+			}
+		}
+	}
+
+	fmt.Println()
+	fmt.Println("Usage:")
+	if len(names) > 0 {
+		var positional []string
+		for _, paramName := range names {
+			if task.Params[paramName].Required {
+				positional = append(positional, "<"+paramName+">")
+			}
+		}
+		usage := strings.Join(append(positional, "[instructions]"), " ")
+		fmt.Printf("  start task %s %s\n", task.Name, usage)
+		fmt.Printf("  start task %s --param %s=<value> \"special instructions\"\n", task.Name, names[0])
+	} else {
+		fmt.Printf("  start task %s\n", task.Name)
+		fmt.Printf("  start task %s \"special instructions\"\n", task.Name)
+	}
+	fmt.Println()
+	fmt.Println("Use 'start task --help' for general help.")
+
+	return true
+}
+
 // taskNotFoundError returns a helpful error when task is not found
-func (tc *TaskCommand) taskNotFoundError(taskName string, cfg domain.Config) error {
+func (tc *TaskCommand) taskNotFoundError(taskName, suggestion string, cfg domain.Config) error {
 	msg := fmt.Sprintf("Task %q not found.", taskName)
+	if suggestion != "" {
+		msg += fmt.Sprintf(" Did you mean %q?", suggestion)
+	}
 
 	// Show available tasks if any exist
 	if len(cfg.Tasks) > 0 {
 		msg += "\n\nAvailable tasks:"
-		var names []string
-		for name := range cfg.Tasks {
-			names = append(names, name)
-		}
-		sort.Strings(names)
-		for _, name := range names {
+		for _, name := range orderedNames(cfg.Tasks, cfg.TaskOrder) {
 			task := cfg.Tasks[name]
 			aliasStr := ""
 			if task.Alias != "" {
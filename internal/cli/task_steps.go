@@ -0,0 +1,180 @@
+package cli
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/grantcarthew/start/internal/domain"
+	"github.com/grantcarthew/start/internal/engine"
+	"github.com/spf13/cobra"
+)
+
+// runSteps runs a pipeline task: each step runs headless and its output is
+// available to later steps as {step:name}. An interactive last step replaces
+// the process like a normal task, otherwise the last output is printed
+func (tc *TaskCommand) runSteps(
+	cmd *cobra.Command,
+	cfg, globalCfg, localCfg domain.Config,
+	task domain.Task,
+	params map[string]string,
+	instructions string,
+) error {
+	agentFlag, _ := cmd.Flags().GetString("agent")
+	modelFlag, _ := cmd.Flags().GetString("model")
+	roleFlag, _ := cmd.Flags().GetString("role")
+	outputPath, _ := cmd.Flags().GetString("output")
+	selection := contextSelection(cmd)
+
+	// stepRequest builds the launch request for a step
+	stepRequest := func(step domain.TaskStep, outputs map[string]string) (launchRequest, error) {
+		sub := domain.Task{Name: task.Name, Prompt: step.Prompt, Params: task.Params}
+		subParams := params
+		if step.Task != "" {
+			resolved, err := tc.taskResolver.Resolve(step.Task, localCfg.Tasks, globalCfg.Tasks)
+			if err != nil {
+				return launchRequest{}, fmt.Errorf("step %q: %w", step.Name, err)
+			}
+			if len(resolved.Steps) > 0 {
+				return launchRequest{}, fmt.Errorf("step %q: task %q has steps, pipelines cannot be nested", step.Name, step.Task)
+			}
+			sub = resolved
+
+			// The referenced task gets the pipeline params it declares
+			supplied := make(map[string]string)
+			for name, value := range params {
+				if _, ok := sub.Params[name]; ok && value != "" {
+					supplied[name] = value
+				}
+			}
+			subParams, _, err = tc.taskLoader.ResolveParams(sub, supplied, nil)
+			if err != nil {
+				return launchRequest{}, fmt.Errorf("step %q: %w", step.Name, err)
+			}
+		}
+
+		// Steps inherit the pipeline's agent and role
+		if sub.Agent == "" {
+			sub.Agent = task.Agent
+		}
+		if sub.Role == "" {
+			sub.Role = task.Role
+		}
+
+		req := launchRequest{
+			AgentFlag:    agentFlag,
+			ModelFlag:    modelFlag,
+			RoleFlag:     roleFlag,
+			CommandType:  engine.CommandTypeTask,
+			Task:         &sub,
+			Instructions: instructions,
+			Params:       subParams,
+			StepOutputs:  outputs,
+			Contexts:     selection,
+		}
+		if step.Agent != "" {
+			if step.Agent != agentFlag {
+				req.ModelFlag = ""
+			}
+			req.AgentFlag = step.Agent
+		}
+		if step.Role != "" {
+			req.RoleFlag = step.Role
+		}
+		return req, nil
+	}
+
+	steps := task.Steps
+	var last *domain.TaskStep
+	if n := len(steps); steps[n-1].Interactive {
+		last = &steps[n-1]
+		steps = steps[:n-1]
+	}
+
+	position := make(map[string]int)
+	for i, step := range task.Steps {
+		position[step.Name] = i + 1
+	}
+
+	fmt.Fprintf(os.Stderr, "Running task '%s' (%d steps)\n", task.Name, len(task.Steps))
+
+	run := func(step domain.TaskStep, outputs map[string]string) (string, error) {
+		req, err := stepRequest(step, outputs)
+		if err != nil {
+			return "", err
+		}
+		launch, err := tc.launcher.prepare(cfg, req)
+		if err != nil {
+			return "", err
+		}
+		defer tc.launcher.cleanup(launch)
+
+		fmt.Fprintf(os.Stderr, "❯ Step %d/%d: %s (agent: %s)\n", position[step.Name], len(task.Steps), step.Name, launch.Agent.Name)
+
+		var output bytes.Buffer
+		stepParams := launch.Params
+		stepParams.Headless = true
+		code, err := tc.executor.Capture(stepParams, &output)
+		if err != nil {
+			return "", err
+		}
+		if code != 0 {
+			return "", &ExitCodeError{Code: code}
+		}
+		return strings.TrimRight(output.String(), "\n"), nil
+	}
+
+	report := func(result engine.StepResult) {
+		name := result.Step.Name
+		switch {
+		case result.Err == nil:
+			fmt.Fprintf(os.Stderr, "✓ Step %s (%d bytes)\n", name, len(result.Output))
+		case result.Retrying:
+			fmt.Fprintf(os.Stderr, "⚠ Step %s failed (attempt %d): %v, retrying\n", name, result.Attempt, result.Err)
+		case engine.StepOnFailure(result.Step) == engine.StepOnFailureContinue:
+			fmt.Fprintf(os.Stderr, "⚠ Step %s failed: %v, continuing\n", name, result.Err)
+		default:
+			fmt.Fprintf(os.Stderr, "✗ Step %s failed: %v\n", name, result.Err)
+		}
+	}
+
+	outputs, err := engine.RunSteps(steps, run, report)
+	if err != nil {
+		// The failed step was reported above, an agent's exit code passes through quietly
+		cmd.SilenceUsage = true
+		var exitErr *ExitCodeError
+		if errors.As(err, &exitErr) {
+			cmd.SilenceErrors = true
+		}
+		return err
+	}
+
+	if last != nil {
+		req, err := stepRequest(*last, outputs)
+		if err != nil {
+			return err
+		}
+		launch, err := tc.launcher.prepare(cfg, req)
+		if err != nil {
+			return err
+		}
+		defer tc.launcher.cleanup(launch)
+
+		fmt.Fprintf(os.Stderr, "❯ Step %d/%d: %s (agent: %s)\n", len(task.Steps), len(task.Steps), last.Name, launch.Agent.Name)
+		return runAgent(cmd, tc.executor, launch)
+	}
+
+	// The last step's output is the task's output
+	final := outputs[steps[len(steps)-1].Name]
+	if outputPath != "" {
+		if err := os.WriteFile(outputPath, []byte(final+"\n"), 0644); err != nil {
+			return fmt.Errorf("failed to write output file: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "✓ Task output written to %s\n", outputPath)
+		return nil
+	}
+	fmt.Println(final)
+	return nil
+}
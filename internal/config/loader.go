@@ -100,7 +100,8 @@ func (l *Loader) loadSettings(dir string, config *domain.Config) error {
 	}
 
 	var parsed struct {
-		Settings domain.Settings `toml:"settings"`
+		Settings  domain.Settings   `toml:"settings"`
+		Variables map[string]string `toml:"variables"`
 	}
 
 	if err := toml.Unmarshal(data, &parsed); err != nil {
@@ -108,6 +109,7 @@ func (l *Loader) loadSettings(dir string, config *domain.Config) error {
 	}
 
 	config.Settings = parsed.Settings
+	config.Variables = parsed.Variables
 	return nil
 }
 
@@ -127,11 +129,21 @@ func (l *Loader) loadAgents(dir string, config *domain.Config) error {
 		return fmt.Errorf("failed to parse %s: %w", path, err)
 	}
 
+	// Maps lose definition order, recover it from the document
+	paths, err := keyPaths(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
 	// Set the Name field for each agent (it's the map key)
 	for name, agent := range parsed.Agents {
 		agent.Name = name
+		agent.ConfigPath = path
 		config.Agents[name] = agent
 	}
+	config.AgentOrder = applyOrder(definitionOrder(paths, "agents"), func(name string) int {
+		return config.Agents[name].Order
+	})
 
 	return nil
 }
@@ -152,11 +164,20 @@ func (l *Loader) loadRoles(dir string, config *domain.Config) error {
 		return fmt.Errorf("failed to parse %s: %w", path, err)
 	}
 
+	// Maps lose definition order, recover it from the document
+	paths, err := keyPaths(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
 	// Set the Name field for each role (it's the map key)
 	for name, role := range parsed.Roles {
 		role.Name = name
 		config.Roles[name] = role
 	}
+	config.RoleOrder = applyOrder(definitionOrder(paths, "roles"), func(name string) int {
+		return config.Roles[name].Order
+	})
 
 	return nil
 }
@@ -173,18 +194,24 @@ func (l *Loader) loadContexts(dir string, config *domain.Config) error {
 		Contexts map[string]domain.Context `toml:"contexts"`
 	}
 
-	// Use decoder to preserve order (go-toml v2 preserves map iteration order)
 	if err := toml.Unmarshal(data, &parsed); err != nil {
 		return fmt.Errorf("failed to parse %s: %w", path, err)
 	}
 
-	// Set the Name field for each context and preserve order
-	// Note: go-toml/v2 preserves the order of map keys during iteration
+	// Maps lose definition order, recover it from the document
+	paths, err := keyPaths(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	// Set the Name field for each context (it's the map key)
 	for name, ctx := range parsed.Contexts {
 		ctx.Name = name
 		config.Contexts[name] = ctx
-		config.ContextOrder = append(config.ContextOrder, name)
 	}
+	config.ContextOrder = applyOrder(definitionOrder(paths, "contexts"), func(name string) int {
+		return config.Contexts[name].Order
+	})
 
 	return nil
 }
@@ -205,11 +232,25 @@ func (l *Loader) loadTasks(dir string, config *domain.Config) error {
 		return fmt.Errorf("failed to parse %s: %w", path, err)
 	}
 
+	// Task and param order are lost in maps, recover them from the document
+	paths, err := keyPaths(data)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
 	// Set the Name field for each task (it's the map key)
 	for name, task := range parsed.Tasks {
 		task.Name = name
+		for paramName, param := range task.Params {
+			param.Name = paramName
+			task.Params[paramName] = param
+		}
+		task.ParamOrder = definitionOrder(paths, "tasks", name, "params")
 		config.Tasks[name] = task
 	}
+	config.TaskOrder = applyOrder(definitionOrder(paths, "tasks"), func(name string) int {
+		return config.Tasks[name].Order
+	})
 
 	return nil
 }
@@ -2,6 +2,7 @@ package config_test
 
 import (
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/grantcarthew/start/internal/config"
@@ -69,6 +70,10 @@ default_model = "sonnet"
 		t.Errorf("Expected default_model 'sonnet', got '%s'", claude.DefaultModel)
 	}
 
+	if claude.ConfigPath != home+"/.config/start/agents.toml" {
+		t.Errorf("Expected config path of agents.toml, got '%s'", claude.ConfigPath)
+	}
+
 	if len(claude.Models) != 1 {
 		t.Errorf("Expected 1 model, got %d", len(claude.Models))
 	}
@@ -117,6 +122,109 @@ description = "Project code reviewer"
 	}
 }
 
+func TestLoadTaskParams(t *testing.T) {
+	mockFS := mocks.NewMockFileSystem()
+
+	// Params defined as tables, dotted keys and inline tables keep their order
+	mockFS.Files["/project/.start/tasks.toml"] = `
+[tasks.review]
+prompt = "Review {param:scope}"
+params.zone = { type = "string" }
+
+[tasks.review.params.scope]
+type = "enum"
+values = ["api", "web"]
+required = true
+
+[tasks.review.params.depth]
+type = "int"
+default = 2
+
+[tasks.other]
+prompt = "Other"
+params = { beta = {}, alpha = { type = "bool" } }
+`
+
+	loader := config.NewLoader(mockFS)
+
+	cfg, err := loader.LoadLocal("/project")
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	review := cfg.Tasks["review"]
+	if got := strings.Join(review.ParamOrder, ","); got != "zone,scope,depth" {
+		t.Errorf("Expected param order 'zone,scope,depth', got '%s'", got)
+	}
+	if review.Params["scope"].Name != "scope" || !review.Params["scope"].Required {
+		t.Errorf("Expected required scope param, got %+v", review.Params["scope"])
+	}
+	if review.Params["depth"].Default != int64(2) {
+		t.Errorf("Expected depth default 2, got %v", review.Params["depth"].Default)
+	}
+
+	if got := strings.Join(cfg.Tasks["other"].ParamOrder, ","); got != "beta,alpha" {
+		t.Errorf("Expected param order 'beta,alpha', got '%s'", got)
+	}
+}
+
+func TestLoadDefinitionOrder(t *testing.T) {
+	mockFS := mocks.NewMockFileSystem()
+
+	mockFS.Files["/project/.start/roles.toml"] = `
+[roles.zeta]
+prompt = "Z"
+
+[roles.alpha]
+prompt = "A"
+
+[roles.mid]
+prompt = "M"
+`
+	// An explicit order comes first, lowest first
+	mockFS.Files["/project/.start/contexts.toml"] = `
+[contexts.zeta]
+prompt = "Z"
+
+[contexts.alpha]
+prompt = "A"
+order = 2
+
+[contexts.mid]
+prompt = "M"
+
+[contexts.first]
+prompt = "F"
+order = 1
+`
+	mockFS.Files["/project/.start/tasks.toml"] = `
+tasks.zeta.prompt = "Z"
+
+[tasks.alpha]
+prompt = "A"
+`
+
+	loader := config.NewLoader(mockFS)
+
+	// Map iteration order is random, repeat to catch an order taken from it
+	for range 10 {
+		cfg, err := loader.LoadLocal("/project")
+		if err != nil {
+			t.Fatalf("Expected no error, got: %v", err)
+		}
+
+		if got := strings.Join(cfg.RoleOrder, ","); got != "zeta,alpha,mid" {
+			t.Fatalf("Expected role order 'zeta,alpha,mid', got '%s'", got)
+		}
+		if got := strings.Join(cfg.ContextOrder, ","); got != "first,alpha,zeta,mid" {
+			t.Fatalf("Expected context order 'first,alpha,zeta,mid', got '%s'", got)
+		}
+		if got := strings.Join(cfg.TaskOrder, ","); got != "zeta,alpha" {
+			t.Fatalf("Expected task order 'zeta,alpha', got '%s'", got)
+		}
+	}
+}
+
 func TestLoadMissingFiles(t *testing.T) {
 	mockFS := mocks.NewMockFileSystem()
 
@@ -1,6 +1,10 @@
 package config
 
-import "github.com/grantcarthew/start/internal/domain"
+import (
+	"slices"
+
+	"github.com/grantcarthew/start/internal/domain"
+)
 
 // Merge merges local config into global config
 // Local config takes precedence over global config
@@ -11,17 +15,43 @@ func Merge(global, local domain.Config) domain.Config {
 	)
 
 	result := domain.Config{
-		Settings:     mergeSettings(global.Settings, local.Settings),
-		Agents:       mergeAgents(global.Agents, local.Agents),
-		Roles:        mergeRoles(global.Roles, local.Roles),
-		Contexts:     contexts,
-		ContextOrder: contextOrder,
-		Tasks:        mergeTasks(global.Tasks, local.Tasks),
+		Settings:  mergeSettings(global.Settings, local.Settings),
+		Agents:    ResolveAgents(mergeAgents(global.Agents, local.Agents)),
+		Roles:     mergeRoles(global.Roles, local.Roles),
+		Contexts:  contexts,
+		Tasks:     mergeTasks(global.Tasks, local.Tasks),
+		Variables: MergeVariables(global.Variables, local.Variables),
 	}
 
+	// An explicit order applies across both configs
+	result.AgentOrder = applyOrder(mergeOrder(global.AgentOrder, local.AgentOrder), func(name string) int {
+		return result.Agents[name].Order
+	})
+	result.RoleOrder = applyOrder(mergeOrder(global.RoleOrder, local.RoleOrder), func(name string) int {
+		return result.Roles[name].Order
+	})
+	result.ContextOrder = applyOrder(contextOrder, func(name string) int {
+		return result.Contexts[name].Order
+	})
+	result.TaskOrder = applyOrder(mergeOrder(global.TaskOrder, local.TaskOrder), func(name string) int {
+		return result.Tasks[name].Order
+	})
+
 	return result
 }
 
+// mergeOrder combines definition orders: global names first, then local-only
+// names; a local entry replacing a global one keeps the global position
+func mergeOrder(global, local []string) []string {
+	order := slices.Clone(global)
+	for _, name := range local {
+		if !slices.Contains(order, name) {
+			order = append(order, name)
+		}
+	}
+	return order
+}
+
 // mergeSettings merges settings - local overrides global per-field
 func mergeSettings(global, local domain.Settings) domain.Settings {
 	result := global
@@ -42,6 +72,12 @@ func mergeSettings(global, local domain.Settings) domain.Settings {
 	if local.CommandTimeout != 0 {
 		result.CommandTimeout = local.CommandTimeout
 	}
+	if local.MaxParallelCommands != 0 {
+		result.MaxParallelCommands = local.MaxParallelCommands
+	}
+	if local.CommandDeadline != 0 {
+		result.CommandDeadline = local.CommandDeadline
+	}
 	// AssetDownload is a bool, so we need to check if it was explicitly set
 	// For now, we always take the local value (false is a valid override)
 	if local.AssetDownload != global.AssetDownload || local.AssetDownload {
@@ -53,10 +89,52 @@ func mergeSettings(global, local domain.Settings) domain.Settings {
 	if local.AssetPath != "" {
 		result.AssetPath = local.AssetPath
 	}
+	if local.PromptLayout != nil {
+		layout := MergePromptLayout(global.PromptLayout, local.PromptLayout)
+		result.PromptLayout = &layout
+	}
+
+	return result
+}
+
+// MergePromptLayout overlays the fields set in override onto base
+// Either may be nil
+func MergePromptLayout(base, override *domain.PromptLayout) domain.PromptLayout {
+	var result domain.PromptLayout
+	if base != nil {
+		result = *base
+	}
+	if override == nil {
+		return result
+	}
+
+	if override.Style != "" {
+		result.Style = override.Style
+	}
+	if override.Header != "" {
+		result.Header = override.Header
+	}
+	if override.Footer != "" {
+		result.Footer = override.Footer
+	}
+	if override.RolePlacement != "" {
+		result.RolePlacement = override.RolePlacement
+	}
 
 	return result
 }
 
+// MergeVariables combines variables, later maps override earlier ones
+func MergeVariables(layers ...map[string]string) map[string]string {
+	result := make(map[string]string)
+	for _, layer := range layers {
+		for name, value := range layer {
+			result[name] = value
+		}
+	}
+	return result
+}
+
 // mergeAgents combines agents from both configs
 // Local agent replaces global agent with same name
 func mergeAgents(global, local map[string]domain.Agent) map[string]domain.Agent {
@@ -75,6 +153,102 @@ func mergeAgents(global, local map[string]domain.Agent) map[string]domain.Agent
 	return result
 }
 
+// ResolveAgents applies agent extends, so a variant carries its parent's
+// fields under its own overrides
+// An agent whose parent is missing or part of a cycle is left as written,
+// validation reports it
+func ResolveAgents(agents map[string]domain.Agent) map[string]domain.Agent {
+	result := make(map[string]domain.Agent, len(agents))
+	for name, agent := range agents {
+		result[name] = resolveAgent(name, agent, agents)
+	}
+	return result
+}
+
+// resolveAgent applies the extends chain of one agent, root parent first
+func resolveAgent(name string, agent domain.Agent, agents map[string]domain.Agent) domain.Agent {
+	chain := []domain.Agent{agent}
+	seen := map[string]bool{name: true}
+
+	for current := agent; current.Extends != ""; {
+		parent, ok := agents[current.Extends]
+		if !ok || seen[current.Extends] {
+			return agent
+		}
+		seen[current.Extends] = true
+		chain = append(chain, parent)
+		current = parent
+	}
+
+	result := chain[len(chain)-1]
+	for i := len(chain) - 2; i >= 0; i-- {
+		result = overrideAgent(result, chain[i])
+	}
+	return result
+}
+
+// overrideAgent returns parent with every field the child sets replaced
+// Models are combined, command and args replace each other, and order is not
+// inherited
+func overrideAgent(parent, child domain.Agent) domain.Agent {
+	result := parent
+	result.Name = child.Name
+	result.Order = child.Order
+	result.Extends = child.Extends
+
+	if child.Bin != "" {
+		result.Bin = child.Bin
+	}
+	if child.Command != "" {
+		result.Command = child.Command
+		result.Args = nil
+		result.ConfigPath = child.ConfigPath
+	}
+	if len(child.Args) > 0 {
+		result.Args = child.Args
+		result.Command = ""
+		result.ConfigPath = child.ConfigPath
+	}
+	if child.HeadlessCommand != "" {
+		result.HeadlessCommand = child.HeadlessCommand
+	}
+	if child.PromptDelivery != "" {
+		result.PromptDelivery = child.PromptDelivery
+	}
+	if child.Description != "" {
+		result.Description = child.Description
+	}
+	if child.URL != "" {
+		result.URL = child.URL
+	}
+	if child.ModelsURL != "" {
+		result.ModelsURL = child.ModelsURL
+	}
+	if child.ModelsCommand != "" {
+		result.ModelsCommand = child.ModelsCommand
+	}
+	if child.DefaultModel != "" {
+		result.DefaultModel = child.DefaultModel
+	}
+
+	if len(child.Models) > 0 {
+		result.Models = make(map[string]string, len(parent.Models)+len(child.Models))
+		for alias, id := range parent.Models {
+			result.Models[alias] = id
+		}
+		for alias, id := range child.Models {
+			result.Models[alias] = id
+		}
+	}
+
+	if child.PromptLayout != nil {
+		layout := MergePromptLayout(parent.PromptLayout, child.PromptLayout)
+		result.PromptLayout = &layout
+	}
+
+	return result
+}
+
 // mergeRoles combines roles from both configs
 // Local role replaces global role with same name
 func mergeRoles(global, local map[string]domain.Role) map[string]domain.Role {
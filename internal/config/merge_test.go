@@ -1,6 +1,7 @@
 package config_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/grantcarthew/start/internal/config"
@@ -46,6 +47,72 @@ func TestMergeSettings(t *testing.T) {
 	}
 }
 
+func TestMergePromptLayout(t *testing.T) {
+	global := domain.Config{
+		Settings: domain.Settings{
+			PromptLayout: &domain.PromptLayout{Style: "xml", Header: "Global header"},
+		},
+	}
+
+	local := domain.Config{
+		Settings: domain.Settings{
+			PromptLayout: &domain.PromptLayout{Style: "markdown"},
+		},
+	}
+
+	result := config.Merge(global, local)
+
+	if result.Settings.PromptLayout.Style != "markdown" {
+		t.Errorf("Expected style 'markdown', got '%s'", result.Settings.PromptLayout.Style)
+	}
+	if result.Settings.PromptLayout.Header != "Global header" {
+		t.Errorf("Expected global header preserved, got '%s'", result.Settings.PromptLayout.Header)
+	}
+	if global.Settings.PromptLayout.Style != "xml" {
+		t.Errorf("Expected global layout unchanged, got '%s'", global.Settings.PromptLayout.Style)
+	}
+
+	// Agent override applies per field on top of settings
+	layout := config.MergePromptLayout(result.Settings.PromptLayout, &domain.PromptLayout{RolePlacement: "prompt"})
+	if layout.Style != "markdown" || layout.RolePlacement != "prompt" {
+		t.Errorf("Expected markdown with role in prompt, got %+v", layout)
+	}
+
+	// No layout anywhere
+	layout = config.MergePromptLayout(nil, nil)
+	if layout != (domain.PromptLayout{}) {
+		t.Errorf("Expected empty layout, got %+v", layout)
+	}
+}
+
+func TestMergeVariables(t *testing.T) {
+	global := domain.Config{
+		Variables: map[string]string{"jira_project": "CORE", "team": "platform"},
+	}
+
+	local := domain.Config{
+		Variables: map[string]string{"jira_project": "OPS"},
+	}
+
+	result := config.Merge(global, local)
+
+	if result.Variables["jira_project"] != "OPS" {
+		t.Errorf("Expected local jira_project 'OPS', got '%s'", result.Variables["jira_project"])
+	}
+	if result.Variables["team"] != "platform" {
+		t.Errorf("Expected global team preserved, got '%s'", result.Variables["team"])
+	}
+	if global.Variables["jira_project"] != "CORE" {
+		t.Errorf("Expected global variables unchanged, got '%s'", global.Variables["jira_project"])
+	}
+
+	// --var overrides are the last layer
+	vars := config.MergeVariables(result.Variables, map[string]string{"team": "infra"})
+	if vars["team"] != "infra" || vars["jira_project"] != "OPS" {
+		t.Errorf("Expected override applied on top, got %v", vars)
+	}
+}
+
 func TestMergeAgents(t *testing.T) {
 	global := domain.Config{
 		Agents: map[string]domain.Agent{
@@ -96,6 +163,98 @@ func TestMergeAgents(t *testing.T) {
 	}
 }
 
+func TestMergeAgentsExtends(t *testing.T) {
+	global := domain.Config{
+		Agents: map[string]domain.Agent{
+			"claude": {
+				Name:          "claude",
+				Bin:           "claude",
+				Command:       "{bin} --model {model} {prompt}",
+				URL:           "https://docs.anthropic.com/claude-code",
+				ModelsCommand: "claude models",
+				DefaultModel:  "sonnet",
+				Models:        map[string]string{"sonnet": "claude-sonnet-4"},
+			},
+		},
+	}
+
+	// A local variant extends the global agent
+	local := domain.Config{
+		Agents: map[string]domain.Agent{
+			"claude-yolo": {
+				Name:    "claude-yolo",
+				Extends: "claude",
+				Command: "{bin} --model {model} --dangerously-skip-permissions {prompt}",
+				Models:  map[string]string{"opus": "claude-opus-4"},
+			},
+			"orphan": {Name: "orphan", Extends: "missing"},
+		},
+	}
+
+	result := config.Merge(global, local)
+
+	yolo := result.Agents["claude-yolo"]
+	if yolo.Bin != "claude" || yolo.DefaultModel != "sonnet" || yolo.URL != "https://docs.anthropic.com/claude-code" || yolo.ModelsCommand != "claude models" {
+		t.Errorf("Expected inherited bin, default model, url and models command, got %+v", yolo)
+	}
+	if yolo.Command != "{bin} --model {model} --dangerously-skip-permissions {prompt}" {
+		t.Errorf("Expected overridden command, got %q", yolo.Command)
+	}
+	if len(yolo.Models) != 2 || yolo.Models["sonnet"] != "claude-sonnet-4" || yolo.Models["opus"] != "claude-opus-4" {
+		t.Errorf("Expected combined models, got %v", yolo.Models)
+	}
+	if yolo.Name != "claude-yolo" || yolo.Extends != "claude" {
+		t.Errorf("Expected variant name and extends kept, got %q %q", yolo.Name, yolo.Extends)
+	}
+	if len(result.Agents["claude"].Models) != 1 {
+		t.Error("Expected parent models left unchanged")
+	}
+
+	// Unresolvable variants stay as written
+	if orphan := result.Agents["orphan"]; orphan.Bin != "" || orphan.Extends != "missing" {
+		t.Errorf("Expected orphan left as written, got %+v", orphan)
+	}
+}
+
+func TestMergeDefinitionOrder(t *testing.T) {
+	global := domain.Config{
+		Roles: map[string]domain.Role{
+			"zeta":  {Name: "zeta"},
+			"alpha": {Name: "alpha"},
+		},
+		RoleOrder: []string{"zeta", "alpha"},
+		Tasks: map[string]domain.Task{
+			"review": {Name: "review"},
+			"docs":   {Name: "docs"},
+		},
+		TaskOrder: []string{"review", "docs"},
+	}
+
+	local := domain.Config{
+		Roles: map[string]domain.Role{
+			"alpha": {Name: "alpha"},
+			"local": {Name: "local"},
+		},
+		RoleOrder: []string{"local", "alpha"},
+		Tasks: map[string]domain.Task{
+			"audit": {Name: "audit", Order: 1},
+		},
+		TaskOrder: []string{"audit"},
+	}
+
+	result := config.Merge(global, local)
+
+	// Global first, a local override keeps the global position
+	if got := strings.Join(result.RoleOrder, ","); got != "zeta,alpha,local" {
+		t.Errorf("Expected role order 'zeta,alpha,local', got '%s'", got)
+	}
+
+	// An explicit order applies across both configs
+	if got := strings.Join(result.TaskOrder, ","); got != "audit,review,docs" {
+		t.Errorf("Expected task order 'audit,review,docs', got '%s'", got)
+	}
+}
+
 func TestMergeRoles(t *testing.T) {
 	global := domain.Config{
 		Roles: map[string]domain.Role{
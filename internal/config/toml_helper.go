@@ -103,11 +103,23 @@ func (h *TOMLHelper) WriteSettingsFile(dir string, settings domain.Settings) err
 		return fmt.Errorf("failed to create directory: %w", err)
 	}
 
+	// Keep the [variables] table that shares config.toml
+	var existing struct {
+		Variables map[string]string `toml:"variables"`
+	}
+	if data, err := h.fs.ReadFile(filepath.Join(dir, "config.toml")); err == nil {
+		if err := toml.Unmarshal(data, &existing); err != nil {
+			return fmt.Errorf("failed to parse config file: %w", err)
+		}
+	}
+
 	// Prepare structure for marshaling
 	tomlData := struct {
-		Settings domain.Settings `toml:"settings"`
+		Settings  domain.Settings   `toml:"settings"`
+		Variables map[string]string `toml:"variables,omitempty"`
 	}{
-		Settings: settings,
+		Settings:  settings,
+		Variables: existing.Variables,
 	}
 
 	// Marshal to TOML
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/grantcarthew/start/internal/domain"
@@ -111,6 +112,27 @@ func TestTOMLHelper_ReadWriteSettings(t *testing.T) {
 	}
 }
 
+func TestTOMLHelper_WriteSettingsPreservesVariables(t *testing.T) {
+	fs := mocks.NewMockFileSystem()
+	helper := NewTOMLHelper(fs)
+
+	dir := "/test"
+	fs.Files["/test/config.toml"] = "[settings]\ndefault_agent = \"claude\"\n\n[variables]\njira_project = \"OPS\"\n"
+
+	err := helper.WriteSettingsFile(dir, domain.Settings{DefaultAgent: "gemini"})
+	if err != nil {
+		t.Fatalf("WriteSettingsFile failed: %v", err)
+	}
+
+	written := fs.Files["/test/config.toml"]
+	if !strings.Contains(written, "jira_project = 'OPS'") && !strings.Contains(written, `jira_project = "OPS"`) {
+		t.Errorf("expected [variables] preserved, got:\n%s", written)
+	}
+	if !strings.Contains(written, "gemini") {
+		t.Errorf("expected updated settings, got:\n%s", written)
+	}
+}
+
 func TestTOMLHelper_ReadWriteRoles(t *testing.T) {
 	fs := mocks.NewMockFileSystem()
 	helper := NewTOMLHelper(fs)
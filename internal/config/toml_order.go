@@ -0,0 +1,90 @@
+package config
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/pelletier/go-toml/v2/unstable"
+)
+
+// keyPaths returns the full key path of every table header and key in data,
+// in document order (go-toml decodes tables into maps, which lose it)
+func keyPaths(data []byte) ([][]string, error) {
+	var p unstable.Parser
+	p.Reset(data)
+
+	var paths [][]string
+	var table []string
+	for p.NextExpression() {
+		expr := p.Expression()
+		switch expr.Kind {
+		case unstable.Table, unstable.ArrayTable:
+			table = keyOf(expr.Key())
+			paths = append(paths, table)
+		case unstable.KeyValue:
+			paths = appendKeyValue(paths, table, expr)
+		}
+	}
+
+	return paths, p.Error()
+}
+
+// appendKeyValue appends the path of a key/value, descending into inline tables
+func appendKeyValue(paths [][]string, table []string, kv *unstable.Node) [][]string {
+	path := append(slices.Clone(table), keyOf(kv.Key())...)
+	paths = append(paths, path)
+
+	if value := kv.Value(); value.Kind == unstable.InlineTable {
+		children := value.Children()
+		for children.Next() {
+			if child := children.Node(); child.Kind == unstable.KeyValue {
+				paths = appendKeyValue(paths, path, child)
+			}
+		}
+	}
+
+	return paths
+}
+
+// keyOf joins the parts of a dotted key
+func keyOf(parts unstable.Iterator) []string {
+	var key []string
+	for parts.Next() {
+		key = append(key, string(parts.Node().Data))
+	}
+	return key
+}
+
+// definitionOrder returns the distinct keys directly below prefix, in the order
+// they are first defined
+func definitionOrder(paths [][]string, prefix ...string) []string {
+	var order []string
+	for _, path := range paths {
+		if len(path) <= len(prefix) || !slices.Equal(path[:len(prefix)], prefix) {
+			continue
+		}
+		if name := path[len(prefix)]; !slices.Contains(order, name) {
+			order = append(order, name)
+		}
+	}
+	return order
+}
+
+// applyOrder moves the names with an explicit order (order > 0) to the front,
+// lowest first; the rest keep their place in names
+func applyOrder(names []string, orderOf func(name string) int) []string {
+	result := slices.Clone(names)
+	slices.SortStableFunc(result, func(a, b string) int {
+		oa, ob := orderOf(a), orderOf(b)
+		switch {
+		case oa == 0 && ob == 0:
+			return 0
+		case oa == 0:
+			return 1
+		case ob == 0:
+			return -1
+		}
+		return cmp.Compare(oa, ob)
+	})
+	return result
+}
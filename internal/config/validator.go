@@ -2,10 +2,15 @@ package config
 
 import (
 	"fmt"
+	"net/url"
+	"path"
 	"regexp"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/grantcarthew/start/internal/domain"
+	"github.com/grantcarthew/start/internal/engine"
 )
 
 // ValidationError represents a configuration validation error
@@ -48,12 +53,12 @@ func (v *Validator) Validate(cfg domain.Config) error {
 
 	// Validate agents
 	for name, agent := range cfg.Agents {
-		errors = append(errors, v.validateAgent(name, agent)...)
+		errors = append(errors, v.validateAgent(name, agent, cfg)...)
 	}
 
 	// Validate roles
 	for name, role := range cfg.Roles {
-		errors = append(errors, v.validateRole(name, role)...)
+		errors = append(errors, v.validateRole(name, role, cfg)...)
 	}
 
 	// Validate contexts
@@ -69,6 +74,9 @@ func (v *Validator) Validate(cfg domain.Config) error {
 	// Validate settings references
 	errors = append(errors, v.validateSettings(cfg)...)
 
+	// Validate variable names and {var:name} references
+	errors = append(errors, v.validateVariables(cfg)...)
+
 	if len(errors) > 0 {
 		return errors
 	}
@@ -76,12 +84,15 @@ func (v *Validator) Validate(cfg domain.Config) error {
 	return nil
 }
 
+// namePattern matches agent, role, context, task and step names
+// Lowercase alphanumeric with hyphens, e.g. my-agent
+var namePattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
 // validateAgent validates an agent configuration
-func (v *Validator) validateAgent(name string, agent domain.Agent) ValidationErrors {
+func (v *Validator) validateAgent(name string, agent domain.Agent, cfg domain.Config) ValidationErrors {
 	var errors ValidationErrors
 
 	// Agent name pattern: lowercase alphanumeric with hyphens
-	namePattern := regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
 	if !namePattern.MatchString(name) {
 		errors = append(errors, ValidationError{
 			Field:   fmt.Sprintf("agents.%s", name),
@@ -89,6 +100,15 @@ func (v *Validator) validateAgent(name string, agent domain.Agent) ValidationErr
 		})
 	}
 
+	errors = append(errors, validateOrder(fmt.Sprintf("agents.%s.order", name), agent.Order)...)
+
+	// A variant with a broken extends chain was not resolved, its own fields are incomplete
+	if agent.Extends != "" {
+		if extendsErrors := validateAgentExtends(name, agent, cfg); len(extendsErrors) > 0 {
+			return append(errors, extendsErrors...)
+		}
+	}
+
 	// Bin is required
 	if agent.Bin == "" {
 		errors = append(errors, ValidationError{
@@ -97,30 +117,53 @@ func (v *Validator) validateAgent(name string, agent domain.Agent) ValidationErr
 		})
 	}
 
-	// Command is required
-	if agent.Command == "" {
+	// Exactly one of command (shell template) or args (argv template) is required
+	field := "command"
+	template := agent.Command
+	if len(agent.Args) > 0 {
+		field = "args"
+		template = strings.Join(agent.Args, " ")
+
+		if agent.Command != "" {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("agents.%s", name),
+				Message: "specify either command or args, not both",
+			})
+		}
+
+		// The binary is executed directly, so it must be the first element
+		if !strings.Contains(agent.Args[0], "{bin}") {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("agents.%s.args", name),
+				Message: "first element of args must contain {bin} placeholder",
+			})
+		}
+	} else if agent.Command == "" {
 		errors = append(errors, ValidationError{
 			Field:   fmt.Sprintf("agents.%s.command", name),
 			Message: "command field is required",
 		})
 	}
 
-	// Command must contain {bin} placeholder
-	if !strings.Contains(agent.Command, "{bin}") {
-		errors = append(errors, ValidationError{
-			Field:   fmt.Sprintf("agents.%s.command", name),
-			Message: "command must contain {bin} placeholder",
-		})
+	errors = append(errors, validateAgentTemplate(name, field, template, agent.PromptDelivery)...)
+
+	// Headless command is optional, but held to the same placeholders
+	if agent.HeadlessCommand != "" {
+		errors = append(errors, validateAgentTemplate(name, "headless_command", agent.HeadlessCommand, agent.PromptDelivery)...)
 	}
 
-	// Command must contain {model} placeholder
-	if !strings.Contains(agent.Command, "{model}") {
+	// Prompt delivery must be a known mode
+	switch agent.PromptDelivery {
+	case "", "arg", "file", "stdin":
+	default:
 		errors = append(errors, ValidationError{
-			Field:   fmt.Sprintf("agents.%s.command", name),
-			Message: "command must contain {model} placeholder",
+			Field:   fmt.Sprintf("agents.%s.prompt_delivery", name),
+			Message: fmt.Sprintf("invalid prompt_delivery '%s': must be arg, file or stdin", agent.PromptDelivery),
 		})
 	}
 
+	errors = append(errors, validatePromptLayout(fmt.Sprintf("agents.%s.prompt_layout", name), agent.PromptLayout)...)
+
 	// Models table must exist and have at least one model
 	if len(agent.Models) == 0 {
 		errors = append(errors, ValidationError{
@@ -153,11 +196,10 @@ func (v *Validator) validateAgent(name string, agent domain.Agent) ValidationErr
 }
 
 // validateRole validates a role configuration
-func (v *Validator) validateRole(name string, role domain.Role) ValidationErrors {
+func (v *Validator) validateRole(name string, role domain.Role, cfg domain.Config) ValidationErrors {
 	var errors ValidationErrors
 
 	// Role name pattern
-	namePattern := regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
 	if !namePattern.MatchString(name) {
 		errors = append(errors, ValidationError{
 			Field:   fmt.Sprintf("roles.%s", name),
@@ -165,23 +207,141 @@ func (v *Validator) validateRole(name string, role domain.Role) ValidationErrors
 		})
 	}
 
-	// UTD pattern: at least one of file, command, or prompt must be present
-	if role.File == "" && role.Command == "" && role.Prompt == "" {
+	// UTD pattern: at least one of file, command, or prompt must be present,
+	// unless the role is made of the roles it extends
+	if role.File == "" && role.Command == "" && role.URL == "" && role.Prompt == "" && len(role.Extends) == 0 {
 		errors = append(errors, ValidationError{
 			Field:   fmt.Sprintf("roles.%s", name),
-			Message: "at least one of 'file', 'command', or 'prompt' must be specified (UTD pattern)",
+			Message: "at least one of 'file', 'command', 'url', or 'prompt' must be specified (UTD pattern)",
+		})
+	}
+	errors = append(errors, validateURL(fmt.Sprintf("roles.%s.url", name), role.URL)...)
+
+	errors = append(errors, validateCache(fmt.Sprintf("roles.%s", name), role.Command, role.CacheTTL, role.CacheKeyFiles)...)
+	errors = append(errors, validateFileSource(fmt.Sprintf("roles.%s", name), role.File, role.FileHeader, role.MaxFiles, role.MaxBytes)...)
+	errors = append(errors, validateWhen(fmt.Sprintf("roles.%s.when", name), role.When)...)
+	errors = append(errors, validateOrder(fmt.Sprintf("roles.%s.order", name), role.Order)...)
+
+	// Parent roles must exist and must not lead back to this role
+	for _, parent := range role.Extends {
+		if _, ok := cfg.Roles[parent]; !ok {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("roles.%s.extends", name),
+				Message: fmt.Sprintf("role '%s' not found in configuration", parent),
+			})
+		}
+	}
+	if cycle := inheritanceCycle(name, func(n string) []string { return cfg.Roles[n].Extends }); cycle != nil {
+		errors = append(errors, ValidationError{
+			Field:   fmt.Sprintf("roles.%s.extends", name),
+			Message: fmt.Sprintf("inheritance cycle: %s", strings.Join(cycle, " → ")),
+		})
+	}
+
+	return errors
+}
+
+// validateOrder validates an explicit order, 0 (unset) keeps definition order
+func validateOrder(field string, order int) ValidationErrors {
+	if order < 0 {
+		return ValidationErrors{{
+			Field:   field,
+			Message: "order must be a positive number",
+		}}
+	}
+	return nil
+}
+
+// validateAgentTemplate checks that a command template has the placeholders
+// the agent needs: {bin}, {model}, and {prompt_file} for file delivery
+func validateAgentTemplate(name, field, template, delivery string) ValidationErrors {
+	var errors ValidationErrors
+
+	// Template must contain {bin} placeholder
+	if !strings.Contains(template, "{bin}") {
+		errors = append(errors, ValidationError{
+			Field:   fmt.Sprintf("agents.%s.%s", name, field),
+			Message: fmt.Sprintf("%s must contain {bin} placeholder", field),
+		})
+	}
+
+	// Template must contain {model} placeholder
+	if !strings.Contains(template, "{model}") {
+		errors = append(errors, ValidationError{
+			Field:   fmt.Sprintf("agents.%s.%s", name, field),
+			Message: fmt.Sprintf("%s must contain {model} placeholder", field),
+		})
+	}
+
+	// File delivery needs {prompt_file}
+	if delivery == "file" && !strings.Contains(template, "{prompt_file}") {
+		errors = append(errors, ValidationError{
+			Field:   fmt.Sprintf("agents.%s.%s", name, field),
+			Message: fmt.Sprintf("prompt_delivery \"file\" requires {prompt_file} placeholder in %s", field),
 		})
 	}
 
 	return errors
 }
 
+// validateAgentExtends validates an agent's extends reference
+func validateAgentExtends(name string, agent domain.Agent, cfg domain.Config) ValidationErrors {
+	var errors ValidationErrors
+	field := fmt.Sprintf("agents.%s.extends", name)
+
+	if _, ok := cfg.Agents[agent.Extends]; !ok {
+		errors = append(errors, ValidationError{
+			Field:   field,
+			Message: fmt.Sprintf("agent '%s' not found in configuration", agent.Extends),
+		})
+	}
+
+	parent := func(n string) []string {
+		if ref := cfg.Agents[n].Extends; ref != "" {
+			return []string{ref}
+		}
+		return nil
+	}
+	if cycle := inheritanceCycle(name, parent); cycle != nil {
+		errors = append(errors, ValidationError{
+			Field:   field,
+			Message: fmt.Sprintf("inheritance cycle: %s", strings.Join(cycle, " → ")),
+		})
+	}
+
+	return errors
+}
+
+// inheritanceCycle returns the extends path from name back to itself, or nil
+// when name is not part of a cycle
+func inheritanceCycle(name string, parents func(string) []string) []string {
+	visited := make(map[string]bool)
+
+	var walk func(current string, path []string) []string
+	walk = func(current string, path []string) []string {
+		for _, parent := range parents(current) {
+			if parent == name {
+				return append(path, parent)
+			}
+			if visited[parent] {
+				continue
+			}
+			visited[parent] = true
+			if cycle := walk(parent, append(path, parent)); cycle != nil {
+				return cycle
+			}
+		}
+		return nil
+	}
+
+	return walk(name, []string{name})
+}
+
 // validateContext validates a context configuration
 func (v *Validator) validateContext(name string, ctx domain.Context) ValidationErrors {
 	var errors ValidationErrors
 
 	// Context name pattern
-	namePattern := regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
 	if !namePattern.MatchString(name) {
 		errors = append(errors, ValidationError{
 			Field:   fmt.Sprintf("contexts.%s", name),
@@ -190,12 +350,27 @@ func (v *Validator) validateContext(name string, ctx domain.Context) ValidationE
 	}
 
 	// UTD pattern: at least one of file, command, or prompt must be present
-	if ctx.File == "" && ctx.Command == "" && ctx.Prompt == "" {
+	if ctx.File == "" && ctx.Command == "" && ctx.URL == "" && ctx.Prompt == "" {
 		errors = append(errors, ValidationError{
 			Field:   fmt.Sprintf("contexts.%s", name),
-			Message: "at least one of 'file', 'command', or 'prompt' must be specified (UTD pattern)",
+			Message: "at least one of 'file', 'command', 'url', or 'prompt' must be specified (UTD pattern)",
 		})
 	}
+	errors = append(errors, validateURL(fmt.Sprintf("contexts.%s.url", name), ctx.URL)...)
+
+	errors = append(errors, validateCache(fmt.Sprintf("contexts.%s", name), ctx.Command, ctx.CacheTTL, ctx.CacheKeyFiles)...)
+	errors = append(errors, validateFileSource(fmt.Sprintf("contexts.%s", name), ctx.File, ctx.FileHeader, ctx.MaxFiles, ctx.MaxBytes)...)
+	errors = append(errors, validateWhen(fmt.Sprintf("contexts.%s.when", name), ctx.When)...)
+	errors = append(errors, validateOrder(fmt.Sprintf("contexts.%s.order", name), ctx.Order)...)
+
+	for _, tag := range ctx.Tags {
+		if !namePattern.MatchString(tag) {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("contexts.%s.tags", name),
+				Message: fmt.Sprintf("tag %q must be lowercase alphanumeric with hyphens", tag),
+			})
+		}
+	}
 
 	return errors
 }
@@ -205,7 +380,6 @@ func (v *Validator) validateTask(name string, task domain.Task, cfg domain.Confi
 	var errors ValidationErrors
 
 	// Task name pattern
-	namePattern := regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
 	if !namePattern.MatchString(name) {
 		errors = append(errors, ValidationError{
 			Field:   fmt.Sprintf("tasks.%s", name),
@@ -221,13 +395,38 @@ func (v *Validator) validateTask(name string, task domain.Task, cfg domain.Confi
 		})
 	}
 
-	// UTD pattern: at least one of file, command, or prompt must be present
-	if task.File == "" && task.Command == "" && task.Prompt == "" {
+	// Pipeline tasks run steps instead of a prompt of their own
+	if len(task.Steps) > 0 {
+		if task.File != "" || task.Command != "" || task.URL != "" || task.Prompt != "" {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("tasks.%s", name),
+				Message: "steps cannot be combined with 'file', 'command', 'url' or 'prompt'",
+			})
+		}
+		errors = append(errors, validateSteps(name, task.Steps, cfg)...)
+	} else if task.File == "" && task.Command == "" && task.URL == "" && task.Prompt == "" && task.Extends == "" {
+		// UTD pattern: at least one of file, command, url, or prompt must be present
 		errors = append(errors, ValidationError{
 			Field:   fmt.Sprintf("tasks.%s", name),
-			Message: "at least one of 'file', 'command', or 'prompt' must be specified (UTD pattern)",
+			Message: "at least one of 'file', 'command', 'url', or 'prompt' must be specified (UTD pattern)",
 		})
 	}
+	errors = append(errors, validateURL(fmt.Sprintf("tasks.%s.url", name), task.URL)...)
+
+	errors = append(errors, validateCache(fmt.Sprintf("tasks.%s", name), task.Command, task.CacheTTL, task.CacheKeyFiles)...)
+	errors = append(errors, validateFileSource(fmt.Sprintf("tasks.%s", name), task.File, task.FileHeader, task.MaxFiles, task.MaxBytes)...)
+	errors = append(errors, validateWhen(fmt.Sprintf("tasks.%s.when", name), task.When)...)
+	errors = append(errors, validateOrder(fmt.Sprintf("tasks.%s.order", name), task.Order)...)
+
+	// Contexts pulled in by the task must exist
+	for _, ctxName := range task.Contexts {
+		if _, ok := cfg.Contexts[ctxName]; !ok {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("tasks.%s.contexts", name),
+				Message: fmt.Sprintf("context '%s' not found in configuration", ctxName),
+			})
+		}
+	}
 
 	// If agent is specified, it must exist
 	if task.Agent != "" {
@@ -249,6 +448,389 @@ func (v *Validator) validateTask(name string, task domain.Task, cfg domain.Confi
 		}
 	}
 
+	errors = append(errors, validateTaskExtends(name, task, cfg)...)
+	errors = append(errors, validateParams(name, task)...)
+
+	return errors
+}
+
+// validateTaskExtends validates a task's extends reference
+// A reference to the task's own name, or scoped with global:, may only be
+// present in global config, which the merged config no longer shows
+func validateTaskExtends(name string, task domain.Task, cfg domain.Config) ValidationErrors {
+	if task.Extends == "" {
+		return nil
+	}
+
+	var errors ValidationErrors
+	field := fmt.Sprintf("tasks.%s.extends", name)
+
+	parent, scoped := strings.CutPrefix(task.Extends, "global:")
+	if !namePattern.MatchString(parent) {
+		return append(errors, ValidationError{
+			Field:   field,
+			Message: "extends must be a task name, optionally prefixed with 'global:'",
+		})
+	}
+	if _, ok := cfg.Tasks[parent]; !ok && parent != name {
+		errors = append(errors, ValidationError{
+			Field:   field,
+			Message: fmt.Sprintf("task '%s' not found in configuration", parent),
+		})
+	}
+
+	// Plain references resolve within the merged config, so cycles show there
+	if !scoped && parent != name {
+		plainParent := func(n string) []string {
+			ref := cfg.Tasks[n].Extends
+			if ref == "" || ref == n || strings.HasPrefix(ref, "global:") {
+				return nil
+			}
+			return []string{ref}
+		}
+		if cycle := inheritanceCycle(name, plainParent); cycle != nil {
+			errors = append(errors, ValidationError{
+				Field:   field,
+				Message: fmt.Sprintf("inheritance cycle: %s", strings.Join(cycle, " → ")),
+			})
+		}
+	}
+
+	return errors
+}
+
+// stepRefPattern matches {step:name} references in step prompts
+var stepRefPattern = regexp.MustCompile(`\{step:([A-Za-z0-9_-]+)\}`)
+
+// effectiveTask returns a task with its extends chain applied, so inherited
+// steps show. A broken chain is reported by validateTaskExtends, so the task
+// is returned as written
+func effectiveTask(name string, task domain.Task, cfg domain.Config) domain.Task {
+	resolved, err := engine.NewTaskResolver().Resolve(name, nil, cfg.Tasks)
+	if err != nil {
+		return task
+	}
+	return resolved
+}
+
+// validateSteps validates the [[tasks.<name>.steps]] of a pipeline task
+func validateSteps(name string, steps []domain.TaskStep, cfg domain.Config) ValidationErrors {
+	var errors ValidationErrors
+
+	seen := make(map[string]bool)
+
+	for i, step := range steps {
+		field := fmt.Sprintf("tasks.%s.steps[%d]", name, i)
+
+		if !namePattern.MatchString(step.Name) {
+			errors = append(errors, ValidationError{
+				Field:   field + ".name",
+				Message: "step name is required and must be lowercase alphanumeric with hyphens",
+			})
+		} else if seen[step.Name] {
+			errors = append(errors, ValidationError{
+				Field:   field + ".name",
+				Message: fmt.Sprintf("duplicate step name '%s'", step.Name),
+			})
+		}
+
+		if (step.Task == "") == (step.Prompt == "") {
+			errors = append(errors, ValidationError{
+				Field:   field,
+				Message: "specify exactly one of 'task' or 'prompt'",
+			})
+		}
+
+		if step.Task != "" {
+			if ref, ok := cfg.Tasks[step.Task]; !ok {
+				errors = append(errors, ValidationError{
+					Field:   field + ".task",
+					Message: fmt.Sprintf("task '%s' not found in configuration", step.Task),
+				})
+			} else if len(effectiveTask(step.Task, ref, cfg).Steps) > 0 {
+				errors = append(errors, ValidationError{
+					Field:   field + ".task",
+					Message: fmt.Sprintf("task '%s' has steps, pipelines cannot be nested", step.Task),
+				})
+			}
+		}
+
+		if step.Agent != "" {
+			if _, ok := cfg.Agents[step.Agent]; !ok {
+				errors = append(errors, ValidationError{
+					Field:   field + ".agent",
+					Message: fmt.Sprintf("agent '%s' not found in configuration", step.Agent),
+				})
+			}
+		}
+		if step.Role != "" {
+			if _, ok := cfg.Roles[step.Role]; !ok {
+				errors = append(errors, ValidationError{
+					Field:   field + ".role",
+					Message: fmt.Sprintf("role '%s' not found in configuration", step.Role),
+				})
+			}
+		}
+
+		switch step.OnFailure {
+		case "", "stop", "continue", "retry":
+		default:
+			errors = append(errors, ValidationError{
+				Field:   field + ".on_failure",
+				Message: fmt.Sprintf("invalid on_failure '%s': must be stop, continue or retry", step.OnFailure),
+			})
+		}
+		if step.Retries < 0 || (step.Retries > 0 && step.OnFailure != "retry") {
+			errors = append(errors, ValidationError{
+				Field:   field + ".retries",
+				Message: "retries must be a positive number and requires on_failure = 'retry'",
+			})
+		}
+
+		if step.Interactive && i != len(steps)-1 {
+			errors = append(errors, ValidationError{
+				Field:   field + ".interactive",
+				Message: "only the last step can be interactive",
+			})
+		}
+
+		// Steps can only use the output of steps that ran before them
+		for _, match := range stepRefPattern.FindAllStringSubmatch(step.Prompt, -1) {
+			if !seen[match[1]] {
+				errors = append(errors, ValidationError{
+					Field:   field + ".prompt",
+					Message: fmt.Sprintf("step '%s' is not an earlier step", match[1]),
+				})
+			}
+		}
+
+		seen[step.Name] = true
+	}
+
+	return errors
+}
+
+// paramRefPattern matches {param:name} references in task templates
+var paramRefPattern = regexp.MustCompile(`\{param:([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// validateParams validates [tasks.<name>.params] and {param:name} references
+func validateParams(name string, task domain.Task) ValidationErrors {
+	var errors ValidationErrors
+
+	for paramName, param := range task.Params {
+		field := fmt.Sprintf("tasks.%s.params.%s", name, paramName)
+
+		if !variableNamePattern.MatchString(paramName) {
+			errors = append(errors, ValidationError{
+				Field:   field,
+				Message: "param name must be letters, digits and underscores, not starting with a digit",
+			})
+		}
+
+		switch param.Type {
+		case "", "string", "int", "bool", "path":
+		case "enum":
+			if len(param.Values) == 0 {
+				errors = append(errors, ValidationError{
+					Field:   field + ".values",
+					Message: "enum param requires 'values'",
+				})
+			}
+		default:
+			errors = append(errors, ValidationError{
+				Field:   field + ".type",
+				Message: fmt.Sprintf("invalid type '%s': must be string, int, bool, enum or path", param.Type),
+			})
+		}
+
+		if len(param.Values) > 0 && param.Type != "enum" {
+			errors = append(errors, ValidationError{
+				Field:   field + ".values",
+				Message: "values is only allowed for enum params",
+			})
+		}
+
+		if param.Default != nil {
+			if param.Required {
+				errors = append(errors, ValidationError{
+					Field:   field,
+					Message: "a required param cannot have a default",
+				})
+			}
+			if msg := checkParamDefault(param); msg != "" {
+				errors = append(errors, ValidationError{
+					Field:   field + ".default",
+					Message: msg,
+				})
+			}
+		}
+	}
+
+	templates := []struct{ field, value string }{
+		{"file", task.File},
+		{"command", task.Command},
+		{"prompt", task.Prompt},
+	}
+	for i, step := range task.Steps {
+		templates = append(templates, struct{ field, value string }{fmt.Sprintf("steps[%d].prompt", i), step.Prompt})
+	}
+
+	// Params may be inherited, undeclared references warn when the task runs
+	if task.Extends != "" {
+		return errors
+	}
+
+	for _, template := range templates {
+		for _, match := range paramRefPattern.FindAllStringSubmatch(template.value, -1) {
+			if _, ok := task.Params[match[1]]; !ok {
+				errors = append(errors, ValidationError{
+					Field:   fmt.Sprintf("tasks.%s.%s", name, template.field),
+					Message: fmt.Sprintf("undefined param '%s'", match[1]),
+				})
+			}
+		}
+	}
+
+	return errors
+}
+
+// checkParamDefault returns a message when a param default does not match its type
+func checkParamDefault(param domain.TaskParam) string {
+	switch value := param.Default.(type) {
+	case int, int64:
+		if param.Type != "int" {
+			return fmt.Sprintf("integer default for %s param", paramTypeName(param))
+		}
+	case bool:
+		if param.Type != "bool" {
+			return fmt.Sprintf("boolean default for %s param", paramTypeName(param))
+		}
+	case string:
+		switch param.Type {
+		case "int", "bool":
+			return fmt.Sprintf("default must be a TOML %s, not a string", param.Type)
+		case "enum":
+			if !slices.Contains(param.Values, value) {
+				return fmt.Sprintf("default '%s' is not one of the enum values", value)
+			}
+		}
+	default:
+		return "default must be a string, integer or boolean"
+	}
+	return ""
+}
+
+// paramTypeName returns the param type, defaulting to string
+func paramTypeName(param domain.TaskParam) string {
+	if param.Type == "" {
+		return "string"
+	}
+	return param.Type
+}
+
+// validateCache validates cache_ttl and cache_key_files of a UTD section
+func validateCache(field, command, cacheTTL string, keyFiles []string) ValidationErrors {
+	var errors ValidationErrors
+
+	if cacheTTL != "" {
+		if ttl, err := time.ParseDuration(cacheTTL); err != nil || ttl <= 0 {
+			errors = append(errors, ValidationError{
+				Field:   field + ".cache_ttl",
+				Message: fmt.Sprintf("invalid duration %q (e.g., \"30s\", \"10m\", \"1h\")", cacheTTL),
+			})
+		}
+		if command == "" {
+			errors = append(errors, ValidationError{
+				Field:   field + ".cache_ttl",
+				Message: "cache_ttl requires 'command'",
+			})
+		}
+	}
+
+	if len(keyFiles) > 0 && cacheTTL == "" {
+		errors = append(errors, ValidationError{
+			Field:   field + ".cache_key_files",
+			Message: "cache_key_files requires 'cache_ttl'",
+		})
+	}
+
+	return errors
+}
+
+// validateURL checks that a url field is an absolute http or https URL
+// References to {var:name} are expanded at load time and not checked here
+func validateURL(field, rawURL string) ValidationErrors {
+	if rawURL == "" || strings.Contains(rawURL, "{var:") || strings.Contains(rawURL, "{param:") {
+		return nil
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ValidationErrors{{
+			Field:   field,
+			Message: fmt.Sprintf("invalid url %q (must be an http or https URL)", rawURL),
+		}}
+	}
+	return nil
+}
+
+// validateFileSource validates the glob and directory options of a UTD file field
+func validateFileSource(field, file, header string, maxFiles, maxBytes int) ValidationErrors {
+	var errors ValidationErrors
+
+	for _, limit := range []struct {
+		key   string
+		value int
+	}{{"max_files", maxFiles}, {"max_bytes", maxBytes}} {
+		if limit.value < 0 {
+			errors = append(errors, ValidationError{
+				Field:   field + "." + limit.key,
+				Message: fmt.Sprintf("%s must be positive", limit.key),
+			})
+		}
+	}
+
+	if file == "" && (header != "" || maxFiles != 0 || maxBytes != 0) {
+		errors = append(errors, ValidationError{
+			Field:   field,
+			Message: "file_header, max_files and max_bytes require 'file'",
+		})
+	}
+
+	return errors
+}
+
+// validateWhen validates the predicates of a when table
+func validateWhen(field string, when *domain.When) ValidationErrors {
+	if when == nil {
+		return nil
+	}
+
+	var errors ValidationErrors
+
+	if *when == (domain.When{}) {
+		errors = append(errors, ValidationError{
+			Field:   field,
+			Message: "at least one predicate required (file_exists, dir_exists, env_set, git_repo, command_succeeds, path_glob)",
+		})
+	}
+
+	if when.EnvSet != "" && !variableNamePattern.MatchString(when.EnvSet) {
+		errors = append(errors, ValidationError{
+			Field:   field + ".env_set",
+			Message: fmt.Sprintf("invalid environment variable name %q", when.EnvSet),
+		})
+	}
+
+	if when.PathGlob != "" {
+		if _, err := path.Match(when.PathGlob, ""); err != nil {
+			errors = append(errors, ValidationError{
+				Field:   field + ".path_glob",
+				Message: fmt.Sprintf("invalid pattern %q: %v", when.PathGlob, err),
+			})
+		}
+	}
+
 	return errors
 }
 
@@ -292,5 +874,117 @@ func (v *Validator) validateSettings(cfg domain.Config) ValidationErrors {
 		}
 	}
 
+	// Concurrency and deadline must not be negative (0 means default)
+	if cfg.Settings.MaxParallelCommands < 0 {
+		errors = append(errors, ValidationError{
+			Field:   "settings.max_parallel_commands",
+			Message: "max_parallel_commands must be a positive number",
+		})
+	}
+	if cfg.Settings.CommandDeadline < 0 {
+		errors = append(errors, ValidationError{
+			Field:   "settings.command_deadline",
+			Message: "command_deadline must be a positive number of seconds",
+		})
+	}
+
+	errors = append(errors, validatePromptLayout("settings.prompt_layout", cfg.Settings.PromptLayout)...)
+
+	return errors
+}
+
+// variableNamePattern matches valid [variables] keys
+var variableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// variableRefPattern matches {var:name} references in templates
+var variableRefPattern = regexp.MustCompile(`\{var:([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// validateVariables validates variable names and reports references to undefined variables
+func (v *Validator) validateVariables(cfg domain.Config) ValidationErrors {
+	var errors ValidationErrors
+
+	for name := range cfg.Variables {
+		if !variableNamePattern.MatchString(name) {
+			errors = append(errors, ValidationError{
+				Field:   fmt.Sprintf("variables.%s", name),
+				Message: "variable name must be letters, digits and underscores, not starting with a digit",
+			})
+		}
+	}
+
+	check := func(field, template string) {
+		for _, match := range variableRefPattern.FindAllStringSubmatch(template, -1) {
+			if _, ok := cfg.Variables[match[1]]; !ok {
+				errors = append(errors, ValidationError{
+					Field:   field,
+					Message: fmt.Sprintf("undefined variable '%s'", match[1]),
+				})
+			}
+		}
+	}
+
+	for name, agent := range cfg.Agents {
+		check(fmt.Sprintf("agents.%s.command", name), agent.Command)
+		check(fmt.Sprintf("agents.%s.headless_command", name), agent.HeadlessCommand)
+		check(fmt.Sprintf("agents.%s.args", name), strings.Join(agent.Args, " "))
+		if agent.PromptLayout != nil {
+			check(fmt.Sprintf("agents.%s.prompt_layout.header", name), agent.PromptLayout.Header)
+			check(fmt.Sprintf("agents.%s.prompt_layout.footer", name), agent.PromptLayout.Footer)
+		}
+	}
+	for name, role := range cfg.Roles {
+		check(fmt.Sprintf("roles.%s.file", name), role.File)
+		check(fmt.Sprintf("roles.%s.command", name), role.Command)
+		check(fmt.Sprintf("roles.%s.url", name), role.URL)
+		check(fmt.Sprintf("roles.%s.prompt", name), role.Prompt)
+	}
+	for name, ctx := range cfg.Contexts {
+		check(fmt.Sprintf("contexts.%s.file", name), ctx.File)
+		check(fmt.Sprintf("contexts.%s.command", name), ctx.Command)
+		check(fmt.Sprintf("contexts.%s.url", name), ctx.URL)
+		check(fmt.Sprintf("contexts.%s.prompt", name), ctx.Prompt)
+	}
+	for name, task := range cfg.Tasks {
+		check(fmt.Sprintf("tasks.%s.file", name), task.File)
+		check(fmt.Sprintf("tasks.%s.command", name), task.Command)
+		check(fmt.Sprintf("tasks.%s.url", name), task.URL)
+		check(fmt.Sprintf("tasks.%s.prompt", name), task.Prompt)
+		for i, step := range task.Steps {
+			check(fmt.Sprintf("tasks.%s.steps[%d].prompt", name, i), step.Prompt)
+		}
+	}
+	if layout := cfg.Settings.PromptLayout; layout != nil {
+		check("settings.prompt_layout.header", layout.Header)
+		check("settings.prompt_layout.footer", layout.Footer)
+	}
+
+	return errors
+}
+
+// validatePromptLayout validates a [settings.prompt_layout] or agent override table
+func validatePromptLayout(field string, layout *domain.PromptLayout) ValidationErrors {
+	var errors ValidationErrors
+	if layout == nil {
+		return errors
+	}
+
+	switch layout.Style {
+	case "", "plain", "xml", "markdown":
+	default:
+		errors = append(errors, ValidationError{
+			Field:   field + ".style",
+			Message: fmt.Sprintf("invalid style '%s': must be plain, xml or markdown", layout.Style),
+		})
+	}
+
+	switch layout.RolePlacement {
+	case "", "system", "prompt":
+	default:
+		errors = append(errors, ValidationError{
+			Field:   field + ".role_placement",
+			Message: fmt.Sprintf("invalid role_placement '%s': must be system or prompt", layout.RolePlacement),
+		})
+	}
+
 	return errors
 }
@@ -111,6 +111,121 @@ func TestValidateAgent(t *testing.T) {
 	}
 }
 
+func TestValidateAgentExtends(t *testing.T) {
+	validator := config.NewValidator()
+
+	global := domain.Config{
+		Agents: map[string]domain.Agent{
+			"claude": {Name: "claude", Bin: "claude", Command: "{bin} --model {model}", Models: map[string]string{"sonnet": "claude-sonnet-4"}},
+		},
+	}
+	local := domain.Config{
+		Agents: map[string]domain.Agent{
+			"claude-headless": {Name: "claude-headless", Extends: "claude", Command: "{bin} --model {model} --print"},
+		},
+	}
+	if err := validator.Validate(config.Merge(global, local)); err != nil {
+		t.Errorf("Expected no error for resolved variant, got: %v", err)
+	}
+
+	local.Agents["claude-headless"] = domain.Agent{Name: "claude-headless", Extends: "missing"}
+	err := validator.Validate(config.Merge(global, local))
+	if err == nil || !strings.Contains(err.Error(), "agents.claude-headless.extends") {
+		t.Errorf("Expected error about unknown parent, got: %v", err)
+	}
+	if strings.Contains(err.Error(), "agents.claude-headless.bin") {
+		t.Errorf("Expected unresolved fields not reported, got: %v", err)
+	}
+
+	local.Agents["a"] = domain.Agent{Name: "a", Extends: "b"}
+	local.Agents["b"] = domain.Agent{Name: "b", Extends: "a"}
+	err = validator.Validate(config.Merge(global, local))
+	if err == nil || !strings.Contains(err.Error(), "inheritance cycle: a → b → a") {
+		t.Errorf("Expected error about inheritance cycle, got: %v", err)
+	}
+}
+
+func TestValidateAgentArgs(t *testing.T) {
+	validator := config.NewValidator()
+
+	// Valid argv-based agent
+	validConfig := domain.Config{
+		Agents: map[string]domain.Agent{
+			"claude": {
+				Name: "claude",
+				Bin:  "claude",
+				Args: []string{"{bin}", "--model", "{model}", "{prompt}"},
+				Models: map[string]string{
+					"sonnet": "claude-3-7-sonnet-20250219",
+				},
+			},
+		},
+	}
+
+	err := validator.Validate(validConfig)
+	if err != nil {
+		t.Errorf("Expected no error for valid args agent, got: %v", err)
+	}
+
+	// Both command and args
+	invalidConfig := domain.Config{
+		Agents: map[string]domain.Agent{
+			"test": {
+				Name:    "test",
+				Bin:     "test",
+				Command: "{bin} --model {model} '{prompt}'",
+				Args:    []string{"{bin}", "--model", "{model}"},
+				Models: map[string]string{
+					"default": "test-model",
+				},
+			},
+		},
+	}
+
+	err = validator.Validate(invalidConfig)
+	if err == nil || !strings.Contains(err.Error(), "not both") {
+		t.Errorf("Expected error about command and args together, got: %v", err)
+	}
+
+	// {bin} not first
+	invalidConfig = domain.Config{
+		Agents: map[string]domain.Agent{
+			"test": {
+				Name: "test",
+				Bin:  "test",
+				Args: []string{"--model", "{model}", "{bin}"},
+				Models: map[string]string{
+					"default": "test-model",
+				},
+			},
+		},
+	}
+
+	err = validator.Validate(invalidConfig)
+	if err == nil || !strings.Contains(err.Error(), "first element of args") {
+		t.Errorf("Expected error about first args element, got: %v", err)
+	}
+
+	// Missing {model}
+	invalidConfig = domain.Config{
+		Agents: map[string]domain.Agent{
+			"test": {
+				Name: "test",
+				Bin:  "test",
+				Args: []string{"{bin}", "{prompt}"},
+				Models: map[string]string{
+					"default": "test-model",
+				},
+			},
+		},
+	}
+
+	err = validator.Validate(invalidConfig)
+	if err == nil || !strings.Contains(err.Error(), "args must contain {model}") {
+		t.Errorf("Expected error about {model} in args, got: %v", err)
+	}
+}
+
 func TestValidateRole(t *testing.T) {
 	validator := config.NewValidator()
 
@@ -177,6 +292,34 @@ func TestValidateRole(t *testing.T) {
 	}
 }
 
+func TestValidateRoleExtends(t *testing.T) {
+	validator := config.NewValidator()
+
+	// A role may be made only of its parents
+	cfg := domain.Config{
+		Roles: map[string]domain.Role{
+			"base":     {Name: "base", Prompt: "Base"},
+			"reviewer": {Name: "reviewer", Extends: []string{"base"}},
+		},
+	}
+	if err := validator.Validate(cfg); err != nil {
+		t.Errorf("Expected no error for extends-only role, got: %v", err)
+	}
+
+	cfg.Roles["reviewer"] = domain.Role{Name: "reviewer", Extends: []string{"missing"}}
+	err := validator.Validate(cfg)
+	if err == nil || !strings.Contains(err.Error(), "roles.reviewer.extends") {
+		t.Errorf("Expected error about unknown parent, got: %v", err)
+	}
+
+	cfg.Roles["base"] = domain.Role{Name: "base", Prompt: "Base", Extends: []string{"reviewer"}}
+	cfg.Roles["reviewer"] = domain.Role{Name: "reviewer", Extends: []string{"base"}}
+	err = validator.Validate(cfg)
+	if err == nil || !strings.Contains(err.Error(), "inheritance cycle") {
+		t.Errorf("Expected error about inheritance cycle, got: %v", err)
+	}
+}
+
 func TestValidateTask(t *testing.T) {
 	validator := config.NewValidator()
 
@@ -250,6 +393,40 @@ func TestValidateTask(t *testing.T) {
 	}
 }
 
+func TestValidateTaskExtends(t *testing.T) {
+	validator := config.NewValidator()
+
+	// Only the overridden field, params come from the parent
+	cfg := domain.Config{
+		Tasks: map[string]domain.Task{
+			"code-review":     {Name: "code-review", Prompt: "Review {param:focus}", Params: map[string]domain.TaskParam{"focus": {}}},
+			"security-review": {Name: "security-review", Extends: "global:code-review", Prompt: "Audit {param:focus}"},
+		},
+	}
+	if err := validator.Validate(cfg); err != nil {
+		t.Errorf("Expected no error for valid extends, got: %v", err)
+	}
+
+	cfg.Tasks["security-review"] = domain.Task{Name: "security-review", Extends: "missing"}
+	err := validator.Validate(cfg)
+	if err == nil || !strings.Contains(err.Error(), "tasks.security-review.extends") {
+		t.Errorf("Expected error about unknown parent, got: %v", err)
+	}
+
+	cfg.Tasks["security-review"] = domain.Task{Name: "security-review", Extends: "local:code-review"}
+	err = validator.Validate(cfg)
+	if err == nil || !strings.Contains(err.Error(), "optionally prefixed with 'global:'") {
+		t.Errorf("Expected error about reference format, got: %v", err)
+	}
+
+	cfg.Tasks["code-review"] = domain.Task{Name: "code-review", Extends: "security-review"}
+	cfg.Tasks["security-review"] = domain.Task{Name: "security-review", Extends: "code-review"}
+	err = validator.Validate(cfg)
+	if err == nil || !strings.Contains(err.Error(), "inheritance cycle") {
+		t.Errorf("Expected error about inheritance cycle, got: %v", err)
+	}
+}
+
 func TestValidateSettings(t *testing.T) {
 	validator := config.NewValidator()
 
@@ -486,3 +663,395 @@ func indexOfSubstr(s, substr string) int {
 	}
 	return -1
 }
+
+func TestValidateAgentPromptDelivery(t *testing.T) {
+	validator := config.NewValidator()
+
+	agent := domain.Agent{
+		Name:           "test",
+		Bin:            "test",
+		Command:        "{bin} --model {model} --file {prompt_file}",
+		PromptDelivery: "file",
+		Models: map[string]string{
+			"default": "test-model",
+		},
+	}
+
+	cfg := domain.Config{Agents: map[string]domain.Agent{"test": agent}}
+	if err := validator.Validate(cfg); err != nil {
+		t.Errorf("Expected no error for file delivery with {prompt_file}, got: %v", err)
+	}
+
+	// File delivery without {prompt_file}
+	agent.Command = "{bin} --model {model}"
+	cfg = domain.Config{Agents: map[string]domain.Agent{"test": agent}}
+	err := validator.Validate(cfg)
+	if err == nil || !strings.Contains(err.Error(), "requires {prompt_file}") {
+		t.Errorf("Expected error about missing {prompt_file}, got: %v", err)
+	}
+
+	// Stdin delivery needs no prompt placeholder
+	agent.PromptDelivery = "stdin"
+	cfg = domain.Config{Agents: map[string]domain.Agent{"test": agent}}
+	if err := validator.Validate(cfg); err != nil {
+		t.Errorf("Expected no error for stdin delivery, got: %v", err)
+	}
+
+	// Unknown delivery mode
+	agent.PromptDelivery = "pipe"
+	cfg = domain.Config{Agents: map[string]domain.Agent{"test": agent}}
+	err = validator.Validate(cfg)
+	if err == nil || !strings.Contains(err.Error(), "invalid prompt_delivery") {
+		t.Errorf("Expected error about invalid prompt_delivery, got: %v", err)
+	}
+}
+
+func TestValidateAgentHeadlessCommand(t *testing.T) {
+	validator := config.NewValidator()
+
+	agent := domain.Agent{
+		Name:            "test",
+		Bin:             "test",
+		Command:         "{bin} --model {model} --file {prompt_file}",
+		HeadlessCommand: "{bin} --print --model {model} --file {prompt_file}",
+		PromptDelivery:  "file",
+		Models: map[string]string{
+			"default": "test-model",
+		},
+	}
+
+	cfg := domain.Config{Agents: map[string]domain.Agent{"test": agent}}
+	if err := validator.Validate(cfg); err != nil {
+		t.Errorf("Expected no error for complete headless_command, got: %v", err)
+	}
+
+	tests := []struct {
+		headless string
+		want     string
+	}{
+		{"--print --model {model} --file {prompt_file}", "headless_command must contain {bin} placeholder"},
+		{"{bin} --print --file {prompt_file}", "headless_command must contain {model} placeholder"},
+		{"{bin} --print --model {model}", "requires {prompt_file} placeholder in headless_command"},
+	}
+
+	for _, tt := range tests {
+		agent.HeadlessCommand = tt.headless
+		cfg = domain.Config{Agents: map[string]domain.Agent{"test": agent}}
+		err := validator.Validate(cfg)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%q: expected error containing %q, got: %v", tt.headless, tt.want, err)
+		}
+	}
+}
+
+func TestValidateCacheSettings(t *testing.T) {
+	validator := config.NewValidator()
+
+	ctx := domain.Context{
+		Name:          "deps",
+		Command:       "go list -m all",
+		CacheTTL:      "10m",
+		CacheKeyFiles: []string{"go.sum"},
+	}
+
+	cfg := domain.Config{Contexts: map[string]domain.Context{"deps": ctx}}
+	if err := validator.Validate(cfg); err != nil {
+		t.Errorf("Expected no error for valid cache settings, got: %v", err)
+	}
+
+	// Invalid duration
+	ctx.CacheTTL = "ten minutes"
+	cfg = domain.Config{Contexts: map[string]domain.Context{"deps": ctx}}
+	err := validator.Validate(cfg)
+	if err == nil || !strings.Contains(err.Error(), "contexts.deps.cache_ttl") {
+		t.Errorf("Expected error about invalid cache_ttl, got: %v", err)
+	}
+
+	// Key files without a TTL
+	ctx.CacheTTL = ""
+	cfg = domain.Config{Contexts: map[string]domain.Context{"deps": ctx}}
+	err = validator.Validate(cfg)
+	if err == nil || !strings.Contains(err.Error(), "cache_key_files requires 'cache_ttl'") {
+		t.Errorf("Expected error about cache_key_files without cache_ttl, got: %v", err)
+	}
+
+	// TTL without a command
+	role := domain.Role{Name: "dev", File: "ROLE.md", CacheTTL: "1h"}
+	cfg = domain.Config{Roles: map[string]domain.Role{"dev": role}}
+	err = validator.Validate(cfg)
+	if err == nil || !strings.Contains(err.Error(), "cache_ttl requires 'command'") {
+		t.Errorf("Expected error about cache_ttl without command, got: %v", err)
+	}
+}
+
+func TestValidateFileSource(t *testing.T) {
+	validator := config.NewValidator()
+
+	ctx := domain.Context{Name: "adrs", File: "docs/adr/*.md", FileHeader: "## {path}", MaxFiles: 20, MaxBytes: 65536}
+	cfg := domain.Config{Contexts: map[string]domain.Context{"adrs": ctx}}
+	if err := validator.Validate(cfg); err != nil {
+		t.Errorf("Expected no error for valid file source, got: %v", err)
+	}
+
+	// Negative limit
+	ctx.MaxFiles = -1
+	cfg = domain.Config{Contexts: map[string]domain.Context{"adrs": ctx}}
+	err := validator.Validate(cfg)
+	if err == nil || !strings.Contains(err.Error(), "contexts.adrs.max_files") {
+		t.Errorf("Expected error about max_files, got: %v", err)
+	}
+
+	// Limits without a file
+	role := domain.Role{Name: "dev", Prompt: "You are a dev", MaxBytes: 100}
+	cfg = domain.Config{Roles: map[string]domain.Role{"dev": role}}
+	err = validator.Validate(cfg)
+	if err == nil || !strings.Contains(err.Error(), "require 'file'") {
+		t.Errorf("Expected error about limits without file, got: %v", err)
+	}
+}
+
+func TestValidateURL(t *testing.T) {
+	validator := config.NewValidator()
+
+	// A url alone satisfies the UTD pattern
+	ctx := domain.Context{Name: "standards", URL: "https://example.com/standards.md"}
+	cfg := domain.Config{Contexts: map[string]domain.Context{"standards": ctx}}
+	if err := validator.Validate(cfg); err != nil {
+		t.Errorf("Expected no error for url context, got: %v", err)
+	}
+
+	ctx.URL = "ftp://example.com/standards.md"
+	cfg = domain.Config{Contexts: map[string]domain.Context{"standards": ctx}}
+	err := validator.Validate(cfg)
+	if err == nil || !strings.Contains(err.Error(), "contexts.standards.url") {
+		t.Errorf("Expected error about url scheme, got: %v", err)
+	}
+}
+
+func TestValidateWhen(t *testing.T) {
+	validator := config.NewValidator()
+
+	ctx := domain.Context{Name: "agents", File: "AGENTS.md", When: &domain.When{FileExists: "AGENTS.md", PathGlob: "~/work/**"}}
+	cfg := domain.Config{Contexts: map[string]domain.Context{"agents": ctx}}
+	if err := validator.Validate(cfg); err != nil {
+		t.Errorf("Expected no error for valid when, got: %v", err)
+	}
+
+	// Empty when table
+	ctx.When = &domain.When{}
+	cfg = domain.Config{Contexts: map[string]domain.Context{"agents": ctx}}
+	err := validator.Validate(cfg)
+	if err == nil || !strings.Contains(err.Error(), "contexts.agents.when") {
+		t.Errorf("Expected error about empty when, got: %v", err)
+	}
+
+	// Malformed glob
+	ctx.When = &domain.When{PathGlob: "[work"}
+	cfg = domain.Config{Contexts: map[string]domain.Context{"agents": ctx}}
+	err = validator.Validate(cfg)
+	if err == nil || !strings.Contains(err.Error(), "path_glob") {
+		t.Errorf("Expected error about path_glob, got: %v", err)
+	}
+}
+
+func TestValidateContextSelection(t *testing.T) {
+	validator := config.NewValidator()
+
+	cfg := domain.Config{
+		Contexts: map[string]domain.Context{
+			"owasp": {Name: "owasp", File: "OWASP.md", Tags: []string{"security"}},
+		},
+		Tasks: map[string]domain.Task{
+			"audit": {Name: "audit", Prompt: "Audit", Contexts: []string{"owasp"}},
+		},
+	}
+	if err := validator.Validate(cfg); err != nil {
+		t.Errorf("Expected no error for valid selection, got: %v", err)
+	}
+
+	cfg.Tasks["audit"] = domain.Task{Name: "audit", Prompt: "Audit", Contexts: []string{"missing"}}
+	err := validator.Validate(cfg)
+	if err == nil || !strings.Contains(err.Error(), "tasks.audit.contexts") {
+		t.Errorf("Expected error about unknown task context, got: %v", err)
+	}
+
+	cfg.Tasks = nil
+	cfg.Contexts["owasp"] = domain.Context{Name: "owasp", File: "OWASP.md", Tags: []string{"Security"}}
+	err = validator.Validate(cfg)
+	if err == nil || !strings.Contains(err.Error(), "contexts.owasp.tags") {
+		t.Errorf("Expected error about tag name, got: %v", err)
+	}
+}
+
+func TestValidatePromptLayout(t *testing.T) {
+	validator := config.NewValidator()
+
+	cfg := domain.Config{
+		Settings: domain.Settings{
+			PromptLayout: &domain.PromptLayout{Style: "xml", RolePlacement: "prompt"},
+		},
+	}
+	if err := validator.Validate(cfg); err != nil {
+		t.Errorf("Expected no error for valid prompt layout, got: %v", err)
+	}
+
+	cfg.Settings.PromptLayout = &domain.PromptLayout{Style: "html"}
+	err := validator.Validate(cfg)
+	if err == nil || !strings.Contains(err.Error(), "settings.prompt_layout.style") {
+		t.Errorf("Expected error about invalid style, got: %v", err)
+	}
+
+	// Agent override is validated too
+	cfg = domain.Config{
+		Agents: map[string]domain.Agent{
+			"test": {
+				Name:         "test",
+				Bin:          "test",
+				Command:      "{bin} --model {model} '{prompt}'",
+				Models:       map[string]string{"default": "test-model"},
+				PromptLayout: &domain.PromptLayout{RolePlacement: "user"},
+			},
+		},
+	}
+	err = validator.Validate(cfg)
+	if err == nil || !strings.Contains(err.Error(), "agents.test.prompt_layout.role_placement") {
+		t.Errorf("Expected error about invalid role_placement, got: %v", err)
+	}
+}
+
+func TestValidateVariables(t *testing.T) {
+	validator := config.NewValidator()
+
+	cfg := domain.Config{
+		Variables: map[string]string{"jira_project": "OPS"},
+		Tasks: map[string]domain.Task{
+			"jira": {Name: "jira", Prompt: "Triage {var:jira_project} issues"},
+		},
+	}
+	if err := validator.Validate(cfg); err != nil {
+		t.Errorf("Expected no error for defined variable, got: %v", err)
+	}
+
+	cfg.Tasks["jira"] = domain.Task{Name: "jira", Prompt: "Triage {var:jira_projekt} issues"}
+	err := validator.Validate(cfg)
+	if err == nil || !strings.Contains(err.Error(), "tasks.jira.prompt: undefined variable 'jira_projekt'") {
+		t.Errorf("Expected error about undefined variable, got: %v", err)
+	}
+
+	// Invalid variable names are reported
+	cfg = domain.Config{
+		Variables: map[string]string{"jira-project": "OPS"},
+	}
+	err = validator.Validate(cfg)
+	if err == nil || !strings.Contains(err.Error(), "variables.jira-project") {
+		t.Errorf("Expected error about invalid variable name, got: %v", err)
+	}
+}
+
+func TestValidateTaskParams(t *testing.T) {
+	validator := config.NewValidator()
+
+	valid := domain.Task{
+		Name:   "review",
+		Prompt: "Review {param:scope} at depth {param:depth}",
+		Params: map[string]domain.TaskParam{
+			"scope": {Type: "enum", Values: []string{"api", "web"}, Default: "api"},
+			"depth": {Type: "int", Default: int64(2)},
+		},
+	}
+	cfg := domain.Config{Tasks: map[string]domain.Task{"review": valid}}
+	if err := validator.Validate(cfg); err != nil {
+		t.Errorf("Expected no error for valid params, got: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		params map[string]domain.TaskParam
+		prompt string
+		expect string
+	}{
+		{"invalid type", map[string]domain.TaskParam{"scope": {Type: "list"}}, "{param:scope}", "tasks.review.params.scope.type"},
+		{"enum without values", map[string]domain.TaskParam{"scope": {Type: "enum"}}, "{param:scope}", "enum param requires 'values'"},
+		{"default not in enum", map[string]domain.TaskParam{"scope": {Type: "enum", Values: []string{"api"}, Default: "web"}}, "{param:scope}", "not one of the enum values"},
+		{"string default for int", map[string]domain.TaskParam{"scope": {Type: "int", Default: "2"}}, "{param:scope}", "default must be a TOML int"},
+		{"required with default", map[string]domain.TaskParam{"scope": {Required: true, Default: "api"}}, "{param:scope}", "required param cannot have a default"},
+		{"undefined reference", map[string]domain.TaskParam{"scope": {}}, "{param:scop}", "tasks.review.prompt: undefined param 'scop'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := domain.Config{Tasks: map[string]domain.Task{
+				"review": {Name: "review", Prompt: tt.prompt, Params: tt.params},
+			}}
+			err := validator.Validate(cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.expect) {
+				t.Errorf("Expected error containing %q, got: %v", tt.expect, err)
+			}
+		})
+	}
+}
+
+func TestValidateTaskSteps(t *testing.T) {
+	validator := config.NewValidator()
+
+	base := func(steps ...domain.TaskStep) domain.Config {
+		return domain.Config{
+			Tasks: map[string]domain.Task{
+				"summarise": {Name: "summarise", Prompt: "Summarise"},
+				"release":   {Name: "release", Steps: steps},
+			},
+		}
+	}
+
+	cfg := base(
+		domain.TaskStep{Name: "summary", Task: "summarise"},
+		domain.TaskStep{Name: "notes", Prompt: "Notes for {step:summary}", OnFailure: "retry", Retries: 2},
+		domain.TaskStep{Name: "review", Prompt: "Review {step:notes}", Interactive: true},
+	)
+	if err := validator.Validate(cfg); err != nil {
+		t.Errorf("Expected no error for valid steps, got: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		steps  []domain.TaskStep
+		expect string
+	}{
+		{"task and prompt", []domain.TaskStep{{Name: "a", Task: "summarise", Prompt: "x"}}, "exactly one of 'task' or 'prompt'"},
+		{"unknown task", []domain.TaskStep{{Name: "a", Task: "missing"}}, "task 'missing' not found"},
+		{"nested pipeline", []domain.TaskStep{{Name: "a", Task: "release"}}, "pipelines cannot be nested"},
+		{"duplicate name", []domain.TaskStep{{Name: "a", Prompt: "x"}, {Name: "a", Prompt: "y"}}, "duplicate step name 'a'"},
+		{"later step reference", []domain.TaskStep{{Name: "a", Prompt: "{step:b}"}, {Name: "b", Prompt: "y"}}, "step 'b' is not an earlier step"},
+		{"interactive not last", []domain.TaskStep{{Name: "a", Prompt: "x", Interactive: true}, {Name: "b", Prompt: "y"}}, "only the last step can be interactive"},
+		{"invalid on_failure", []domain.TaskStep{{Name: "a", Prompt: "x", OnFailure: "ignore"}}, "invalid on_failure 'ignore'"},
+		{"retries without retry", []domain.TaskStep{{Name: "a", Prompt: "x", Retries: 1}}, "requires on_failure = 'retry'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.Validate(base(tt.steps...))
+			if err == nil || !strings.Contains(err.Error(), tt.expect) {
+				t.Errorf("Expected error containing %q, got: %v", tt.expect, err)
+			}
+		})
+	}
+
+	// A task that inherits steps through extends is a pipeline too
+	cfg = base(domain.TaskStep{Name: "a", Task: "summarise"})
+	cfg.Tasks["nightly"] = domain.Task{Name: "nightly", Extends: "release", Description: "Nightly release"}
+	cfg.Tasks["wrapper"] = domain.Task{Name: "wrapper", Steps: []domain.TaskStep{{Name: "a", Task: "nightly"}}}
+	err := validator.Validate(cfg)
+	if err == nil || !strings.Contains(err.Error(), "task 'nightly' has steps, pipelines cannot be nested") {
+		t.Errorf("Expected nested pipeline error for inherited steps, got: %v", err)
+	}
+
+	// Steps replace the task's own prompt
+	cfg = base(domain.TaskStep{Name: "a", Prompt: "x"})
+	release := cfg.Tasks["release"]
+	release.Prompt = "own prompt"
+	cfg.Tasks["release"] = release
+	err = validator.Validate(cfg)
+	if err == nil || !strings.Contains(err.Error(), "steps cannot be combined") {
+		t.Errorf("Expected error about steps with prompt, got: %v", err)
+	}
+}
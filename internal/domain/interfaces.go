@@ -2,7 +2,9 @@ package domain
 
 import (
 	"context"
+	"io"
 	"os"
+	"time"
 )
 
 // FileSystem abstracts all file operations
@@ -10,7 +12,10 @@ type FileSystem interface {
 	ReadFile(path string) ([]byte, error)
 	WriteFile(path string, data []byte, perm os.FileMode) error
 	Exists(path string) bool
+	IsDir(path string) bool
 	Glob(pattern string) ([]string, error)
+	// WalkFiles returns all regular files under root, recursively, skipping .git
+	WalkFiles(root string) ([]string, error)
 	MkdirAll(path string, perm os.FileMode) error
 	TempFile(pattern string) (name string, err error)
 	Remove(path string) error
@@ -22,6 +27,25 @@ type Runner interface {
 	// After successful exec, this function never returns
 	// Only returns on error (before exec)
 	Exec(shell, command string) error
+
+	// ExecArgs replaces the current process with args[0], passing args
+	// directly without a shell
+	// Only returns on error (before exec)
+	ExecArgs(args []string) error
+
+	// RedirectStdin makes the file at path the standard input of the
+	// process that a following Exec or ExecArgs call starts
+	RedirectStdin(path string) error
+}
+
+// ProcessRunner abstracts running an agent as a child process (headless mode)
+type ProcessRunner interface {
+	// Run runs the command through the shell, copying its stdout to stdout
+	// Returns the exit code, or an error if the process could not be started
+	Run(shell, command string, stdin io.Reader, stdout io.Writer) (int, error)
+
+	// RunArgs runs args[0] directly without a shell
+	RunArgs(args []string, stdin io.Reader, stdout io.Writer) (int, error)
 }
 
 // CommandRunner abstracts command execution with output capture
@@ -31,6 +55,44 @@ type CommandRunner interface {
 	Run(shell, command string, timeoutSeconds int) (string, error)
 }
 
+// OutputCache abstracts the command output cache (cache_ttl)
+type OutputCache interface {
+	// Get returns the output stored for key if it is younger than ttl
+	Get(key string, ttl time.Duration) (output string, storedAt time.Time, ok bool)
+
+	// Set stores output for key
+	Set(key, output string) error
+
+	// Clear removes all cached output
+	Clear() error
+}
+
+// ModelCache abstracts the cache of model IDs discovered through an agent's
+// models_command or models_url
+type ModelCache interface {
+	// Get returns the model IDs stored for an agent
+	Get(agent string) (ids []string, storedAt time.Time, ok bool)
+
+	// Set stores the model IDs for an agent
+	Set(agent string, ids []string) error
+}
+
+// URLFetcher abstracts fetching the url field of a UTD section
+type URLFetcher interface {
+	// Fetch returns the body at url, revalidating any cached copy
+	// When the server cannot be reached and a cached copy exists, the copy is
+	// returned with Stale set instead of an error
+	Fetch(ctx context.Context, url string, timeout time.Duration) (FetchedURL, error)
+}
+
+// FetchedURL is the result of a URLFetcher fetch
+type FetchedURL struct {
+	Body      string
+	FetchedAt time.Time // When the body was last confirmed by the server
+	Stale     bool      // Server unreachable, Body is the cached copy
+	StaleErr  error     // Why the server could not be reached (Stale only)
+}
+
 // GitHubClient abstracts GitHub HTTP operations
 type GitHubClient interface {
 	FetchIndex(ctx context.Context, repo, branch string) ([]byte, error)
@@ -44,3 +106,12 @@ type Cache interface {
 	List(assetType string) ([]CachedAsset, error)
 	Delete(assetType, name string) error
 }
+
+// HistoryStore abstracts the invocation history log
+type HistoryStore interface {
+	// Append adds an entry to the end of the log
+	Append(entry HistoryEntry) error
+
+	// List returns all entries, oldest first
+	List() ([]HistoryEntry, error)
+}
@@ -6,71 +6,168 @@ import "time"
 type Config struct {
 	Settings     Settings
 	Agents       map[string]Agent
+	AgentOrder   []string // Agent names in definition order, explicit order first
 	Roles        map[string]Role
+	RoleOrder    []string // Role names in definition order, explicit order first
 	Contexts     map[string]Context
-	ContextOrder []string // Preserves TOML definition order
+	ContextOrder []string // Context names in definition order, explicit order first
 	Tasks        map[string]Task
+	TaskOrder    []string          // Task names in definition order, explicit order first
+	Variables    map[string]string // [variables] from config.toml, used as {var:name}
 }
 
 // Settings from config.toml [settings]
 type Settings struct {
-	DefaultAgent   string `toml:"default_agent"`
-	DefaultRole    string `toml:"default_role"`
-	LogLevel       string `toml:"log_level"`
-	Shell          string `toml:"shell"`
-	CommandTimeout int    `toml:"command_timeout"`
-	AssetDownload  bool   `toml:"asset_download"`
-	AssetRepo      string `toml:"asset_repo"`
-	AssetPath      string `toml:"asset_path"`
+	DefaultAgent        string `toml:"default_agent"`
+	DefaultRole         string `toml:"default_role"`
+	LogLevel            string `toml:"log_level"`
+	Shell               string `toml:"shell"`
+	CommandTimeout      int    `toml:"command_timeout"`
+	MaxParallelCommands int    `toml:"max_parallel_commands"` // Context commands run concurrently
+	CommandDeadline     int    `toml:"command_deadline"`      // Seconds for all context commands in one run
+	AssetDownload       bool   `toml:"asset_download"`
+	AssetRepo           string `toml:"asset_repo"`
+	AssetPath           string `toml:"asset_path"`
+
+	PromptLayout *PromptLayout `toml:"prompt_layout,omitempty"`
+}
+
+// PromptLayout from [settings.prompt_layout] or [agents.<name>.prompt_layout]
+// Controls how role, contexts and user prompt are assembled into the final prompt
+type PromptLayout struct {
+	Style         string `toml:"style,omitempty"`          // "plain" (default), "xml" or "markdown"
+	Header        string `toml:"header,omitempty"`         // Text placed before everything else
+	Footer        string `toml:"footer,omitempty"`         // Text placed after the user prompt
+	RolePlacement string `toml:"role_placement,omitempty"` // "system" (default, via {role}) or "prompt"
 }
 
 // Agent from agents.toml [agents.<name>]
 type Agent struct {
-	Name         string
-	Bin          string            `toml:"bin"`
-	Command      string            `toml:"command"`
-	Description  string            `toml:"description"`
-	URL          string            `toml:"url"`
-	ModelsURL    string            `toml:"models_url"`
-	DefaultModel string            `toml:"default_model"`
-	Models       map[string]string `toml:"models"`
+	Name            string
+	Bin             string            `toml:"bin"`
+	Command         string            `toml:"command"`
+	HeadlessCommand string            `toml:"headless_command,omitempty"` // Command template for --capture/--output runs
+	Args            []string          `toml:"args,omitempty"`             // Argv template, alternative to command (no shell)
+	PromptDelivery  string            `toml:"prompt_delivery,omitempty"`  // "arg" (default), "file" or "stdin"
+	Description     string            `toml:"description"`
+	Order           int               `toml:"order,omitempty"` // Position among agents, ahead of those without
+	URL             string            `toml:"url"`
+	ModelsURL       string            `toml:"models_url"`
+	ModelsCommand   string            `toml:"models_command,omitempty"` // Command listing model IDs, one per line
+	DefaultModel    string            `toml:"default_model"`
+	Models          map[string]string `toml:"models"`
+	PromptLayout    *PromptLayout     `toml:"prompt_layout,omitempty"` // Overrides [settings.prompt_layout] per field
+
+	Extends string `toml:"extends,omitempty"` // Parent agent whose fields this agent overrides, resolved after merging
+
+	ConfigPath string `toml:"-"` // agents.toml the command template comes from, for errors
 }
 
 // Role from roles.toml [roles.<name>] (UTD pattern)
 type Role struct {
 	Name           string
-	Description    string `toml:"description"`
-	File           string `toml:"file"`
-	Command        string `toml:"command"`
-	Prompt         string `toml:"prompt"`
-	Shell          string `toml:"shell"`
-	CommandTimeout int    `toml:"command_timeout"`
+	Description    string   `toml:"description"`
+	Order          int      `toml:"order,omitempty"` // Position among roles, ahead of those without
+	File           string   `toml:"file"`
+	Command        string   `toml:"command"`
+	URL            string   `toml:"url,omitempty"` // Remote content for {url_contents}
+	Prompt         string   `toml:"prompt"`
+	Shell          string   `toml:"shell"`
+	CommandTimeout int      `toml:"command_timeout"`
+	CacheTTL       string   `toml:"cache_ttl,omitempty"`       // Reuse command output for this long (e.g., "10m")
+	CacheKeyFiles  []string `toml:"cache_key_files,omitempty"` // Files whose content invalidates cached output
+	FileHeader     string   `toml:"file_header,omitempty"`     // Header before each file of a glob or directory ({path})
+	MaxFiles       int      `toml:"max_files,omitempty"`       // Most files read from a glob or directory
+	MaxBytes       int      `toml:"max_bytes,omitempty"`       // Most bytes read from a glob or directory
+	When           *When    `toml:"when,omitempty"`            // Conditions for the role to apply
+
+	Extends          []string `toml:"extends,omitempty"`           // Parent roles whose content comes first, in order
+	ExtendsSeparator string   `toml:"extends_separator,omitempty"` // Between parent and role content (default blank line)
 }
 
 // Context from contexts.toml [contexts.<name>] (UTD pattern)
 type Context struct {
 	Name           string
-	Description    string `toml:"description"`
-	File           string `toml:"file"`
-	Command        string `toml:"command"`
-	Prompt         string `toml:"prompt"`
-	Required       bool   `toml:"required"`
-	Shell          string `toml:"shell"`
-	CommandTimeout int    `toml:"command_timeout"`
+	Description    string   `toml:"description"`
+	File           string   `toml:"file"`
+	Command        string   `toml:"command"`
+	URL            string   `toml:"url,omitempty"`
+	Prompt         string   `toml:"prompt"`
+	Required       bool     `toml:"required"`
+	Order          int      `toml:"order,omitempty"`
+	Tags           []string `toml:"tags,omitempty"`
+	Shell          string   `toml:"shell"`
+	CommandTimeout int      `toml:"command_timeout"`
+	CacheTTL       string   `toml:"cache_ttl,omitempty"`
+	CacheKeyFiles  []string `toml:"cache_key_files,omitempty"`
+	FileHeader     string   `toml:"file_header,omitempty"`
+	MaxFiles       int      `toml:"max_files,omitempty"`
+	MaxBytes       int      `toml:"max_bytes,omitempty"`
+	When           *When    `toml:"when,omitempty"`
 }
 
 // Task from tasks.toml [tasks.<name>] (UTD pattern)
 type Task struct {
 	Name           string
-	Alias          string `toml:"alias"`
-	Description    string `toml:"description"`
-	Role           string `toml:"role"`
-	Agent          string `toml:"agent"`
-	File           string `toml:"file"`
-	Command        string `toml:"command"`
-	Prompt         string `toml:"prompt"`
-	Shell          string `toml:"shell"`
-	CommandTimeout int    `toml:"command_timeout"`
+	Alias          string   `toml:"alias"`
+	Description    string   `toml:"description"`
+	Order          int      `toml:"order,omitempty"`
+	Role           string   `toml:"role"`
+	Agent          string   `toml:"agent"`
+	File           string   `toml:"file"`
+	Command        string   `toml:"command"`
+	URL            string   `toml:"url,omitempty"`
+	Prompt         string   `toml:"prompt"`
+	Shell          string   `toml:"shell"`
+	CommandTimeout int      `toml:"command_timeout"`
+	CacheTTL       string   `toml:"cache_ttl,omitempty"`
+	CacheKeyFiles  []string `toml:"cache_key_files,omitempty"`
+	FileHeader     string   `toml:"file_header,omitempty"`
+	MaxFiles       int      `toml:"max_files,omitempty"`
+	MaxBytes       int      `toml:"max_bytes,omitempty"`
+	When           *When    `toml:"when,omitempty"`
+
+	Extends string `toml:"extends,omitempty"` // Parent task, "name" or "global:name", whose fields this task overrides
+
+	Contexts []string `toml:"contexts,omitempty"` // Optional contexts loaded with the task, by name
+
+	Params     map[string]TaskParam `toml:"params,omitempty"` // Named parameters, {param:name}
+	ParamOrder []string             `toml:"-"`                // Param names in definition order (positional args)
+
+	Steps []TaskStep `toml:"steps,omitempty"` // Pipeline of headless agent runs (replaces file/command/prompt)
+}
+
+// When from [<section>.when], predicates on the working directory that must
+// all hold for a role, context or task to apply
+type When struct {
+	FileExists      string `toml:"file_exists,omitempty"`      // Relative to the working directory
+	DirExists       string `toml:"dir_exists,omitempty"`       // Relative to the working directory
+	EnvSet          string `toml:"env_set,omitempty"`          // Environment variable name
+	GitRepo         *bool  `toml:"git_repo,omitempty"`         // Inside (true) or outside (false) a git repository
+	CommandSucceeds string `toml:"command_succeeds,omitempty"` // Shell command that must exit 0
+	PathGlob        string `toml:"path_glob,omitempty"`        // Pattern matched against the working directory
+}
+
+// TaskStep from tasks.toml [[tasks.<name>.steps]]
+type TaskStep struct {
+	Name        string `toml:"name"`
+	Task        string `toml:"task,omitempty"`   // Task whose prompt this step runs
+	Prompt      string `toml:"prompt,omitempty"` // Inline prompt, alternative to task
+	Agent       string `toml:"agent,omitempty"`
+	Role        string `toml:"role,omitempty"`
+	OnFailure   string `toml:"on_failure,omitempty"`  // stop (default), continue or retry
+	Retries     int    `toml:"retries,omitempty"`     // Extra attempts for on_failure = "retry" (default 1)
+	Interactive bool   `toml:"interactive,omitempty"` // Last step only: launch the agent interactively
+}
+
+// TaskParam from tasks.toml [tasks.<name>.params.<param>]
+type TaskParam struct {
+	Name        string
+	Type        string   `toml:"type,omitempty"` // string (default), int, bool, enum or path
+	Default     any      `toml:"default,omitempty"`
+	Required    bool     `toml:"required,omitempty"`
+	Description string   `toml:"description,omitempty"`
+	Values      []string `toml:"values,omitempty"` // Allowed values for enum
 }
 
 // AssetMeta from .meta.toml files
@@ -94,3 +191,43 @@ type CachedAsset struct {
 	Name     string
 	Meta     AssetMeta
 }
+
+// HistoryEntry is one recorded agent invocation in the history log (JSONL)
+type HistoryEntry struct {
+	ID           string               `json:"id"`
+	Timestamp    time.Time            `json:"timestamp"`
+	Cwd          string               `json:"cwd"`
+	Mode         string               `json:"mode"` // interactive, prompt or task
+	Agent        string               `json:"agent"`
+	Model        string               `json:"model"` // Model alias as given
+	ModelID      string               `json:"model_id"`
+	Role         string               `json:"role"`
+	Contexts     []HistoryContext     `json:"contexts"`
+	Task         string               `json:"task,omitempty"`
+	Instructions string               `json:"instructions,omitempty"`
+	Params       map[string]string    `json:"params,omitempty"` // Task parameter values
+	UserPrompt   string               `json:"user_prompt,omitempty"`
+	Shell        string               `json:"shell,omitempty"`
+	Command      string               `json:"command"`
+	Args         []string             `json:"args,omitempty"`
+	Delivery     string               `json:"prompt_delivery,omitempty"`
+	PromptFile   string               `json:"prompt_file,omitempty"`
+	Prompt       string               `json:"prompt,omitempty"` // Kept only when the command references PromptFile
+	Headless     bool                 `json:"headless,omitempty"`
+	Output       string               `json:"output,omitempty"` // Headless output file (--output)
+	ContextFlags *HistoryContextFlags `json:"context_flags,omitempty"`
+}
+
+// HistoryContextFlags records the per-run context selection of an invocation
+type HistoryContextFlags struct {
+	Include    []string `json:"context,omitempty"`     // --context
+	Exclude    []string `json:"no_context,omitempty"`  // --no-context
+	Tags       []string `json:"context_tag,omitempty"` // --context-tag
+	NoDefaults bool     `json:"no_contexts,omitempty"` // --no-contexts
+}
+
+// HistoryContext records a context document used by an invocation
+type HistoryContext struct {
+	Name string `json:"name"`
+	Hash string `json:"hash"` // sha256 of the resolved content
+}
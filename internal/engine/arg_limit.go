@@ -0,0 +1,92 @@
+package engine
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+)
+
+// Prompt delivery modes for the agent prompt_delivery setting
+const (
+	PromptDeliveryArg   = "arg"   // Prompt substituted into {prompt} (default)
+	PromptDeliveryFile  = "file"  // Prompt written to {prompt_file}
+	PromptDeliveryStdin = "stdin" // Prompt written to the agent's standard input
+)
+
+// ArgLimitError reports an agent invocation that exceeds the system argument limit
+type ArgLimitError struct {
+	Agent      string
+	Size       int  // Size in bytes of the offending argument or argument list
+	Limit      int  // System limit in bytes
+	SingleArg  bool // True when one argument exceeds the per-argument limit
+	PromptSize int
+}
+
+func (e *ArgLimitError) Error() string {
+	what := "agent command line"
+	if e.SingleArg {
+		what = "a single agent argument"
+	}
+
+	msg := fmt.Sprintf("%s is too large to execute (%s, system limit %s)\n\n", what, formatBytes(e.Size), formatBytes(e.Limit))
+	if e.Agent != "" {
+		msg += fmt.Sprintf("Agent: %s\n", e.Agent)
+	}
+	msg += fmt.Sprintf("Composed prompt: %s\n\n", formatBytes(e.PromptSize))
+	msg += "The prompt is passed as a command-line argument and the operating system\n"
+	msg += "would reject it (E2BIG). Pass the prompt another way by setting\n"
+	msg += "prompt_delivery in the agent configuration:\n"
+	msg += "  prompt_delivery = \"file\"   (use {prompt_file} in the command)\n"
+	msg += "  prompt_delivery = \"stdin\"  (prompt written to the agent's standard input)\n"
+	msg += "Or reduce the size of the context documents."
+
+	return msg
+}
+
+// argLimits returns the per-argument and total argument size limits for this OS
+// A per-argument limit of 0 means there is none
+func argLimits() (int, int) {
+	switch runtime.GOOS {
+	case "linux":
+		// MAX_ARG_STRLEN (32 pages) and the default ARG_MAX (8MB stack / 4)
+		return 131072, 2097152
+	case "darwin":
+		return 0, 1048576
+	default:
+		// POSIX minimum ARG_MAX is 4096, but every supported system allows more
+		return 0, 262144
+	}
+}
+
+// CheckArgSize verifies that args plus the environment fit within the system
+// argument limits, returning an *ArgLimitError when they do not
+func CheckArgSize(args []string) error {
+	perArg, total := argLimits()
+
+	size := 0
+	for _, arg := range args {
+		argSize := len(arg) + 1 // NUL terminator
+		if perArg > 0 && argSize > perArg {
+			return &ArgLimitError{Size: argSize, Limit: perArg, SingleArg: true}
+		}
+		size += argSize + 8 // Pointer in argv
+	}
+
+	for _, env := range os.Environ() {
+		size += len(env) + 1 + 8
+	}
+
+	if size > total {
+		return &ArgLimitError{Size: size, Limit: total}
+	}
+
+	return nil
+}
+
+// formatBytes formats a byte count for error output
+func formatBytes(n int) string {
+	if n < 1024 {
+		return fmt.Sprintf("%d bytes", n)
+	}
+	return fmt.Sprintf("%.1f KB", float64(n)/1024)
+}
@@ -0,0 +1,121 @@
+package engine
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// builtinKeys are the names of the built-in placeholders available in every template
+const builtinKeys = `env:[A-Za-z_][A-Za-z0-9_]*|date:[^{}]+|cwd|project_root|git_branch|git_commit|user|hostname`
+
+var (
+	builtinPattern    = regexp.MustCompile(`\{(` + builtinKeys + `)\}`)
+	builtinKeyPattern = regexp.MustCompile(`^(` + builtinKeys + `)$`)
+)
+
+// gitTimeout is the timeout in seconds for git lookups
+const gitTimeout = 5
+
+// gitCommands maps git placeholders to the command providing their value
+var gitCommands = map[string]string{
+	"git_branch":   "git rev-parse --abbrev-ref HEAD",
+	"git_commit":   "git rev-parse --short HEAD",
+	"project_root": "git rev-parse --show-toplevel",
+}
+
+// gitResult is a memoized git lookup
+type gitResult struct {
+	value string
+	err   error
+}
+
+// IsBuiltinPlaceholder reports whether placeholder (with braces) is a built-in
+func IsBuiltinPlaceholder(placeholder string) bool {
+	return builtinPattern.FindString(placeholder) == placeholder
+}
+
+// Builtins returns values for the built-in placeholders used in template
+// Placeholders whose value is unavailable resolve to "" and produce a warning
+func (r *PlaceholderResolver) Builtins(template string) (map[string]string, []string) {
+	values := make(map[string]string)
+	var warnings []string
+
+	for _, match := range builtinPattern.FindAllStringSubmatch(template, -1) {
+		key := match[1]
+		if _, done := values[key]; done {
+			continue
+		}
+
+		value, err := r.builtin(key)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("Placeholder {%s} unavailable: %v", key, err))
+		}
+		values[key] = value
+	}
+
+	return values, warnings
+}
+
+// ResolveBuiltins replaces only the built-in placeholders in template
+func (r *PlaceholderResolver) ResolveBuiltins(template string) (string, []string) {
+	values, warnings := r.Builtins(template)
+	return r.ResolveArg(template, values), warnings
+}
+
+// builtin returns the value of one built-in placeholder
+func (r *PlaceholderResolver) builtin(key string) (string, error) {
+	switch {
+	case strings.HasPrefix(key, "env:"):
+		name := strings.TrimPrefix(key, "env:")
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		}
+		return value, nil
+	case strings.HasPrefix(key, "date:"):
+		return time.Now().Format(strings.TrimPrefix(key, "date:")), nil
+	case key == "cwd":
+		if r.workDir != "" {
+			return r.workDir, nil
+		}
+		return os.Getwd()
+	case key == "user":
+		if u, err := user.Current(); err == nil {
+			return u.Username, nil
+		}
+		if name := os.Getenv("USER"); name != "" {
+			return name, nil
+		}
+		return "", fmt.Errorf("cannot determine current user")
+	case key == "hostname":
+		return os.Hostname()
+	default:
+		return r.git(key)
+	}
+}
+
+// git runs the git command for a placeholder, once per resolver
+func (r *PlaceholderResolver) git(key string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if cached, ok := r.gitCache[key]; ok {
+		return cached.value, cached.err
+	}
+
+	var result gitResult
+	if r.commandRunner == nil {
+		result.err = fmt.Errorf("git is not available")
+	} else if output, err := r.commandRunner.Run("sh", gitCommands[key], gitTimeout); err != nil {
+		result.err = fmt.Errorf("not a git repository")
+	} else {
+		result.value = strings.TrimSpace(output)
+	}
+
+	r.gitCache[key] = result
+	return result.value, result.err
+}
@@ -0,0 +1,98 @@
+package engine_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grantcarthew/start/internal/domain"
+	"github.com/grantcarthew/start/internal/engine"
+	"github.com/grantcarthew/start/test/assert"
+	"github.com/grantcarthew/start/test/mocks"
+)
+
+func TestPlaceholderResolver_Builtins_Env(t *testing.T) {
+	t.Setenv("START_TEST_TOKEN", "abc123")
+	resolver := engine.NewPlaceholderResolver(nil, "/work")
+
+	result, warnings := resolver.ResolveBuiltins("token={env:START_TEST_TOKEN} dir={cwd}")
+
+	assert.Equal(t, "token=abc123 dir=/work", result)
+	assert.Equal(t, 0, len(warnings))
+}
+
+func TestPlaceholderResolver_Builtins_MissingEnvWarns(t *testing.T) {
+	resolver := engine.NewPlaceholderResolver(nil, "/work")
+
+	result, warnings := resolver.ResolveBuiltins("value=[{env:START_TEST_UNSET_VAR}]")
+
+	assert.Equal(t, "value=[]", result)
+	assert.Equal(t, 1, len(warnings))
+	assert.Contains(t, warnings[0], "{env:START_TEST_UNSET_VAR}")
+	assert.Contains(t, warnings[0], "not set")
+}
+
+func TestPlaceholderResolver_Builtins_DateLayout(t *testing.T) {
+	resolver := engine.NewPlaceholderResolver(nil, "/work")
+
+	result, _ := resolver.ResolveBuiltins("Today is {date:2006-01-02}")
+
+	assert.Equal(t, "Today is "+time.Now().Format("2006-01-02"), result)
+}
+
+func TestPlaceholderResolver_Builtins_Git(t *testing.T) {
+	runner := mocks.NewMockCommandRunner()
+	runner.Outputs["git rev-parse --abbrev-ref HEAD"] = "main\n"
+	runner.Outputs["git rev-parse --short HEAD"] = "1a2b3c4\n"
+	runner.Outputs["git rev-parse --show-toplevel"] = "/work/project\n"
+	resolver := engine.NewPlaceholderResolver(runner, "/work/project/sub")
+
+	result, warnings := resolver.ResolveBuiltins("{git_branch}@{git_commit} in {project_root}")
+
+	assert.Equal(t, "main@1a2b3c4 in /work/project", result)
+	assert.Equal(t, 0, len(warnings))
+}
+
+func TestPlaceholderResolver_Builtins_GitUnavailable(t *testing.T) {
+	runner := mocks.NewMockCommandRunner()
+	runner.SetOutput("fatal: not a git repository", errors.New("exit status 128"))
+	resolver := engine.NewPlaceholderResolver(runner, "/tmp")
+
+	result, warnings := resolver.ResolveBuiltins("branch: {git_branch}")
+
+	assert.Equal(t, "branch: ", result)
+	assert.Equal(t, 1, len(warnings))
+	assert.Contains(t, warnings[0], "{git_branch} unavailable")
+}
+
+func TestPlaceholderResolver_Builtins_UnknownLeftAlone(t *testing.T) {
+	resolver := engine.NewPlaceholderResolver(nil, "/work")
+
+	result, warnings := resolver.ResolveBuiltins("{instructions} and {env:} stay")
+
+	assert.Equal(t, "{instructions} and {env:} stay", result)
+	assert.Equal(t, 0, len(warnings))
+}
+
+func TestExecutor_Prepare_BuiltinPlaceholders(t *testing.T) {
+	t.Setenv("START_TEST_PROFILE", "it's mine")
+	resolver := engine.NewPlaceholderResolver(nil, "/work")
+	executor := engine.NewExecutor(&mocks.MockRunner{}, &mocks.MockProcessRunner{}, resolver, mocks.NewMockFileSystem(), nil)
+
+	prepared, err := executor.Prepare(engine.ExecuteParams{
+		Agent: domain.Agent{
+			Name:    "smith",
+			Bin:     "smith",
+			Command: "{bin} --profile '{env:START_TEST_PROFILE}' --dir '{cwd}' --key '{env:START_TEST_UNSET_KEY}' '{prompt}'",
+		},
+		Model:      "test-model",
+		UserPrompt: "hi",
+		Shell:      "bash",
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, `smith --profile 'it'\''s mine' --dir '/work' --key '' 'hi'`, prepared.Command)
+	assert.Equal(t, 1, len(prepared.Warnings))
+	assert.True(t, strings.Contains(prepared.Warnings[0], "START_TEST_UNSET_KEY"), "warning should name the variable")
+}
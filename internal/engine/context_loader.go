@@ -1,6 +1,11 @@
 package engine
 
 import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
 	"github.com/grantcarthew/start/internal/domain"
 )
 
@@ -27,13 +32,59 @@ const (
 
 // LoadedContext represents a processed context
 type LoadedContext struct {
-	Name     string
-	Content  string
-	FilePath string // For display purposes
-	Warnings []string
+	Name       string
+	Content    string
+	FilePath   string   // For display purposes
+	Files      []string // Files read for a glob or directory
+	Required   bool
+	Skipped    bool   // True if UTD processing or a when predicate skipped this context
+	SkipReason string // Unmet when predicate, empty when UTD processing skipped
+	Cached     bool   // True if command output came from the cache
+	Warnings   []string
+}
+
+// DefaultMaxParallelCommands is the worker pool size when none is configured
+const DefaultMaxParallelCommands = 4
+
+// LoadOptions controls which contexts are loaded and how context commands are run
+type LoadOptions struct {
+	MaxParallel int               // Maximum concurrent command contexts (0 = DefaultMaxParallelCommands)
+	Deadline    time.Duration     // Deadline covering all commands in the run (0 = none)
+	Variables   map[string]string // Values for {var:name}
+	Selection   ContextSelection  // Per-run changes to the default contexts
+}
+
+// ContextSelection adds and drops contexts for a single run
+type ContextSelection struct {
+	Include    []string // Contexts added by name (--context, task contexts)
+	Exclude    []string // Contexts dropped by name (--no-context), wins over the others
+	Tags       []string // Contexts added when they carry any of these tags (--context-tag)
+	NoDefaults bool     // Leave out the contexts the command type loads by default (--no-contexts)
+}
+
+// selects reports whether a context is loaded, given the command type defaults
+func (s ContextSelection) selects(name string, ctx domain.Context, commandType CommandType) bool {
+	if slices.Contains(s.Exclude, name) {
+		return false
+	}
+	if slices.Contains(s.Include, name) {
+		return true
+	}
+	for _, tag := range ctx.Tags {
+		if slices.Contains(s.Tags, tag) {
+			return true
+		}
+	}
+	if s.NoDefaults {
+		return false
+	}
+	// Interactive runs load every context, prompt and task runs required ones only
+	return commandType == CommandTypeInteractive || ctx.Required
 }
 
-// LoadContexts loads and processes contexts based on command type
+// LoadContexts loads and processes contexts based on command type and the
+// per-run selection in opts
+// Command-based contexts run concurrently in a bounded worker pool
 // Returns loaded contexts in definition order
 func (l *ContextLoader) LoadContexts(
 	contexts map[string]domain.Context,
@@ -41,50 +92,107 @@ func (l *ContextLoader) LoadContexts(
 	commandType CommandType,
 	defaultShell string,
 	defaultTimeout int,
+	opts LoadOptions,
 ) []LoadedContext {
-	var result []LoadedContext
-
-	// Process contexts in order
+	// Select contexts in definition order
+	var selected []domain.Context
 	for _, name := range contextOrder {
 		ctx, ok := contexts[name]
 		if !ok {
 			continue
 		}
 
-		// Filter by required field based on command type
-		if commandType != CommandTypeInteractive && !ctx.Required {
-			// Skip optional contexts for prompt and task commands
+		if !opts.Selection.selects(name, ctx, commandType) {
 			continue
 		}
 
-		// Process through UTD
-		utdInput := UTDInput{
-			File:           ctx.File,
-			Command:        ctx.Command,
-			Prompt:         ctx.Prompt,
-			Shell:          ctx.Shell,
-			CommandTimeout: ctx.CommandTimeout,
-		}
+		ctx.Name = name
+		selected = append(selected, ctx)
+	}
 
-		utdResult := l.utdProcessor.Process(utdInput, defaultShell, defaultTimeout)
+	runCtx := context.Background()
+	if opts.Deadline > 0 {
+		var cancel context.CancelFunc
+		runCtx, cancel = context.WithTimeout(runCtx, opts.Deadline)
+		defer cancel()
+	}
 
-		// Skip if UTD processing failed
-		if utdResult.Skipped {
-			result = append(result, LoadedContext{
-				Name:     name,
-				Warnings: utdResult.Warnings,
-			})
+	maxParallel := opts.MaxParallel
+	if maxParallel <= 0 {
+		maxParallel = DefaultMaxParallelCommands
+	}
+
+	// Each worker writes its own slot, so results keep definition order
+	result := make([]LoadedContext, len(selected))
+	pool := make(chan struct{}, maxParallel)
+	var wg sync.WaitGroup
+
+	for i, ctx := range selected {
+		// Contexts that only read files need no worker, commands and url fetches do
+		if ctx.Command == "" && ctx.URL == "" && (ctx.When == nil || ctx.When.CommandSucceeds == "") {
+			result[i] = l.loadContext(runCtx, ctx, defaultShell, defaultTimeout, opts.Variables)
 			continue
 		}
 
-		// Add successfully loaded context
-		result = append(result, LoadedContext{
-			Name:     name,
-			Content:  utdResult.Content,
-			FilePath: utdResult.FilePath,
-			Warnings: utdResult.Warnings,
-		})
+		wg.Add(1)
+		go func(i int, ctx domain.Context) {
+			defer wg.Done()
+			pool <- struct{}{}
+			defer func() { <-pool }()
+			result[i] = l.loadContext(runCtx, ctx, defaultShell, defaultTimeout, opts.Variables)
+		}(i, ctx)
 	}
 
+	wg.Wait()
+
 	return result
 }
+
+// loadContext processes a single context through UTD
+func (l *ContextLoader) loadContext(runCtx context.Context, ctx domain.Context, defaultShell string, defaultTimeout int, variables map[string]string) LoadedContext {
+	if ok, reason := l.utdProcessor.CheckWhen(ctx.When, defaultShell, defaultTimeout); !ok {
+		return LoadedContext{
+			Name:       ctx.Name,
+			Required:   ctx.Required,
+			Skipped:    true,
+			SkipReason: reason,
+		}
+	}
+
+	utdInput := UTDInput{
+		File:           ctx.File,
+		Command:        ctx.Command,
+		URL:            ctx.URL,
+		Prompt:         ctx.Prompt,
+		Shell:          ctx.Shell,
+		CommandTimeout: ctx.CommandTimeout,
+		CacheTTL:       ctx.CacheTTL,
+		CacheKeyFiles:  ctx.CacheKeyFiles,
+		FileHeader:     ctx.FileHeader,
+		MaxFiles:       ctx.MaxFiles,
+		MaxBytes:       ctx.MaxBytes,
+		Variables:      variables,
+	}
+
+	utdResult := l.utdProcessor.ProcessContext(runCtx, utdInput, defaultShell, defaultTimeout)
+
+	// Skip if UTD processing failed
+	if utdResult.Skipped {
+		return LoadedContext{
+			Name:     ctx.Name,
+			Required: ctx.Required,
+			Skipped:  true,
+			Warnings: utdResult.Warnings,
+		}
+	}
+
+	return LoadedContext{
+		Name:     ctx.Name,
+		Content:  utdResult.Content,
+		FilePath: utdResult.FilePath,
+		Files:    utdResult.Files,
+		Required: ctx.Required,
+		Cached:   utdResult.Cached,
+		Warnings: utdResult.Warnings,
+	}
+}
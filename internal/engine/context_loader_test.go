@@ -1,7 +1,12 @@
 package engine
 
 import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/grantcarthew/start/internal/domain"
 )
@@ -13,7 +18,7 @@ func TestContextLoader_LoadContexts_Interactive(t *testing.T) {
 	fs.files["/ctx3.md"] = "Context 3"
 
 	cmdRunner := &mockCommandRunner{}
-	utdProcessor := NewUTDProcessor(fs, cmdRunner, "/workdir")
+	utdProcessor := NewUTDProcessor(fs, cmdRunner, "/workdir", nil, nil)
 	loader := NewContextLoader(utdProcessor)
 
 	contexts := map[string]domain.Context{
@@ -25,7 +30,7 @@ func TestContextLoader_LoadContexts_Interactive(t *testing.T) {
 	contextOrder := []string{"ctx1", "ctx2", "ctx3"}
 
 	// Interactive mode - should load all contexts
-	results := loader.LoadContexts(contexts, contextOrder, CommandTypeInteractive, "bash", 30)
+	results := loader.LoadContexts(contexts, contextOrder, CommandTypeInteractive, "bash", 30, LoadOptions{})
 
 	if len(results) != 3 {
 		t.Errorf("Expected 3 contexts, got %d", len(results))
@@ -54,7 +59,7 @@ func TestContextLoader_LoadContexts_Prompt(t *testing.T) {
 	fs.files["/ctx2.md"] = "Optional context"
 
 	cmdRunner := &mockCommandRunner{}
-	utdProcessor := NewUTDProcessor(fs, cmdRunner, "/workdir")
+	utdProcessor := NewUTDProcessor(fs, cmdRunner, "/workdir", nil, nil)
 	loader := NewContextLoader(utdProcessor)
 
 	contexts := map[string]domain.Context{
@@ -65,7 +70,7 @@ func TestContextLoader_LoadContexts_Prompt(t *testing.T) {
 	contextOrder := []string{"ctx1", "ctx2"}
 
 	// Prompt mode - should load only required contexts
-	results := loader.LoadContexts(contexts, contextOrder, CommandTypePrompt, "bash", 30)
+	results := loader.LoadContexts(contexts, contextOrder, CommandTypePrompt, "bash", 30, LoadOptions{})
 
 	if len(results) != 1 {
 		t.Errorf("Expected 1 context in prompt mode, got %d", len(results))
@@ -87,7 +92,7 @@ func TestContextLoader_LoadContexts_Task(t *testing.T) {
 	fs.files["/ctx3.md"] = "Another required"
 
 	cmdRunner := &mockCommandRunner{}
-	utdProcessor := NewUTDProcessor(fs, cmdRunner, "/workdir")
+	utdProcessor := NewUTDProcessor(fs, cmdRunner, "/workdir", nil, nil)
 	loader := NewContextLoader(utdProcessor)
 
 	contexts := map[string]domain.Context{
@@ -99,7 +104,7 @@ func TestContextLoader_LoadContexts_Task(t *testing.T) {
 	contextOrder := []string{"ctx1", "ctx2", "ctx3"}
 
 	// Task mode - should load only required contexts
-	results := loader.LoadContexts(contexts, contextOrder, CommandTypeTask, "bash", 30)
+	results := loader.LoadContexts(contexts, contextOrder, CommandTypeTask, "bash", 30, LoadOptions{})
 
 	if len(results) != 2 {
 		t.Errorf("Expected 2 contexts in task mode, got %d", len(results))
@@ -117,13 +122,13 @@ func TestContextLoader_LoadContexts_Task(t *testing.T) {
 func TestContextLoader_LoadContexts_EmptyList(t *testing.T) {
 	fs := newMockFileSystem()
 	cmdRunner := &mockCommandRunner{}
-	utdProcessor := NewUTDProcessor(fs, cmdRunner, "/workdir")
+	utdProcessor := NewUTDProcessor(fs, cmdRunner, "/workdir", nil, nil)
 	loader := NewContextLoader(utdProcessor)
 
 	contexts := map[string]domain.Context{}
 	contextOrder := []string{}
 
-	results := loader.LoadContexts(contexts, contextOrder, CommandTypeInteractive, "bash", 30)
+	results := loader.LoadContexts(contexts, contextOrder, CommandTypeInteractive, "bash", 30, LoadOptions{})
 
 	if len(results) != 0 {
 		t.Errorf("Expected 0 contexts, got %d", len(results))
@@ -135,7 +140,7 @@ func TestContextLoader_LoadContexts_FileNotFound(t *testing.T) {
 	// Don't add file to fs
 
 	cmdRunner := &mockCommandRunner{}
-	utdProcessor := NewUTDProcessor(fs, cmdRunner, "/workdir")
+	utdProcessor := NewUTDProcessor(fs, cmdRunner, "/workdir", nil, nil)
 	loader := NewContextLoader(utdProcessor)
 
 	contexts := map[string]domain.Context{
@@ -145,7 +150,7 @@ func TestContextLoader_LoadContexts_FileNotFound(t *testing.T) {
 	contextOrder := []string{"ctx1"}
 
 	// Should still return result with warnings
-	results := loader.LoadContexts(contexts, contextOrder, CommandTypeInteractive, "bash", 30)
+	results := loader.LoadContexts(contexts, contextOrder, CommandTypeInteractive, "bash", 30, LoadOptions{})
 
 	if len(results) != 1 {
 		t.Errorf("Expected 1 context result, got %d", len(results))
@@ -169,7 +174,7 @@ func TestContextLoader_LoadContexts_FileNotFound(t *testing.T) {
 func TestContextLoader_LoadContexts_WithPrompt(t *testing.T) {
 	fs := newMockFileSystem()
 	cmdRunner := &mockCommandRunner{}
-	utdProcessor := NewUTDProcessor(fs, cmdRunner, "/workdir")
+	utdProcessor := NewUTDProcessor(fs, cmdRunner, "/workdir", nil, nil)
 	loader := NewContextLoader(utdProcessor)
 
 	contexts := map[string]domain.Context{
@@ -178,7 +183,7 @@ func TestContextLoader_LoadContexts_WithPrompt(t *testing.T) {
 
 	contextOrder := []string{"ctx1"}
 
-	results := loader.LoadContexts(contexts, contextOrder, CommandTypeInteractive, "bash", 30)
+	results := loader.LoadContexts(contexts, contextOrder, CommandTypeInteractive, "bash", 30, LoadOptions{})
 
 	if len(results) != 1 {
 		t.Errorf("Expected 1 context, got %d", len(results))
@@ -192,7 +197,7 @@ func TestContextLoader_LoadContexts_WithPrompt(t *testing.T) {
 func TestContextLoader_LoadContexts_WithCommand(t *testing.T) {
 	fs := newMockFileSystem()
 	cmdRunner := &mockCommandRunner{output: "Command output"}
-	utdProcessor := NewUTDProcessor(fs, cmdRunner, "/workdir")
+	utdProcessor := NewUTDProcessor(fs, cmdRunner, "/workdir", nil, nil)
 	loader := NewContextLoader(utdProcessor)
 
 	contexts := map[string]domain.Context{
@@ -201,7 +206,7 @@ func TestContextLoader_LoadContexts_WithCommand(t *testing.T) {
 
 	contextOrder := []string{"ctx1"}
 
-	results := loader.LoadContexts(contexts, contextOrder, CommandTypeInteractive, "bash", 30)
+	results := loader.LoadContexts(contexts, contextOrder, CommandTypeInteractive, "bash", 30, LoadOptions{})
 
 	if len(results) != 1 {
 		t.Errorf("Expected 1 context, got %d", len(results))
@@ -219,7 +224,7 @@ func TestContextLoader_LoadContexts_OrderPreserved(t *testing.T) {
 	fs.files["/c.md"] = "C"
 
 	cmdRunner := &mockCommandRunner{}
-	utdProcessor := NewUTDProcessor(fs, cmdRunner, "/workdir")
+	utdProcessor := NewUTDProcessor(fs, cmdRunner, "/workdir", nil, nil)
 	loader := NewContextLoader(utdProcessor)
 
 	contexts := map[string]domain.Context{
@@ -231,7 +236,7 @@ func TestContextLoader_LoadContexts_OrderPreserved(t *testing.T) {
 	// Test different orders
 	contextOrder := []string{"ctx-c", "ctx-a", "ctx-b"}
 
-	results := loader.LoadContexts(contexts, contextOrder, CommandTypeInteractive, "bash", 30)
+	results := loader.LoadContexts(contexts, contextOrder, CommandTypeInteractive, "bash", 30, LoadOptions{})
 
 	if len(results) != 3 {
 		t.Fatalf("Expected 3 contexts, got %d", len(results))
@@ -254,7 +259,7 @@ func TestContextLoader_LoadContexts_MissingFromMap(t *testing.T) {
 	fs.files["/ctx1.md"] = "Context 1"
 
 	cmdRunner := &mockCommandRunner{}
-	utdProcessor := NewUTDProcessor(fs, cmdRunner, "/workdir")
+	utdProcessor := NewUTDProcessor(fs, cmdRunner, "/workdir", nil, nil)
 	loader := NewContextLoader(utdProcessor)
 
 	contexts := map[string]domain.Context{
@@ -264,7 +269,7 @@ func TestContextLoader_LoadContexts_MissingFromMap(t *testing.T) {
 	// Context order includes a context not in the map
 	contextOrder := []string{"ctx1", "ctx-nonexistent", "ctx2"}
 
-	results := loader.LoadContexts(contexts, contextOrder, CommandTypeInteractive, "bash", 30)
+	results := loader.LoadContexts(contexts, contextOrder, CommandTypeInteractive, "bash", 30, LoadOptions{})
 
 	// Should only load ctx1 (others not in map are skipped)
 	if len(results) != 1 {
@@ -281,7 +286,7 @@ func TestContextLoader_LoadContexts_UTDWithFilePath(t *testing.T) {
 	fs.files["/context.md"] = "File content"
 
 	cmdRunner := &mockCommandRunner{}
-	utdProcessor := NewUTDProcessor(fs, cmdRunner, "/workdir")
+	utdProcessor := NewUTDProcessor(fs, cmdRunner, "/workdir", nil, nil)
 	loader := NewContextLoader(utdProcessor)
 
 	contexts := map[string]domain.Context{
@@ -290,7 +295,7 @@ func TestContextLoader_LoadContexts_UTDWithFilePath(t *testing.T) {
 
 	contextOrder := []string{"ctx1"}
 
-	results := loader.LoadContexts(contexts, contextOrder, CommandTypeInteractive, "bash", 30)
+	results := loader.LoadContexts(contexts, contextOrder, CommandTypeInteractive, "bash", 30, LoadOptions{})
 
 	if len(results) != 1 {
 		t.Fatalf("Expected 1 context, got %d", len(results))
@@ -301,3 +306,221 @@ func TestContextLoader_LoadContexts_UTDWithFilePath(t *testing.T) {
 		t.Errorf("Expected file path %q, got %q", "/context.md", results[0].FilePath)
 	}
 }
+
+// slowCommandRunner sleeps for each command and tracks peak concurrency
+type slowCommandRunner struct {
+	delay   time.Duration
+	mu      sync.Mutex
+	running int
+	peak    int
+}
+
+func (m *slowCommandRunner) Run(shell string, command string, timeout int) (string, error) {
+	m.mu.Lock()
+	m.running++
+	if m.running > m.peak {
+		m.peak = m.running
+	}
+	m.mu.Unlock()
+
+	defer func() {
+		m.mu.Lock()
+		m.running--
+		m.mu.Unlock()
+	}()
+
+	if time.Duration(timeout)*time.Second < m.delay {
+		time.Sleep(time.Duration(timeout) * time.Second)
+		return "", fmt.Errorf("command timeout after %d seconds", timeout)
+	}
+	time.Sleep(m.delay)
+	return "output of " + command, nil
+}
+
+func TestContextLoader_LoadContexts_ParallelCommandsKeepOrder(t *testing.T) {
+	fs := newMockFileSystem()
+	fs.files["/static.md"] = "Static context"
+
+	cmdRunner := &slowCommandRunner{delay: 50 * time.Millisecond}
+	utdProcessor := NewUTDProcessor(fs, cmdRunner, "/workdir", nil, nil)
+	loader := NewContextLoader(utdProcessor)
+
+	contexts := map[string]domain.Context{
+		"git-log": {Command: "git log"},
+		"static":  {File: "/static.md"},
+		"tests":   {Command: "go test"},
+		"tree":    {Command: "tree"},
+		"status":  {Command: "git status"},
+		"diff":    {Command: "git diff"},
+	}
+	contextOrder := []string{"git-log", "static", "tests", "tree", "status", "diff"}
+
+	start := time.Now()
+	results := loader.LoadContexts(contexts, contextOrder, CommandTypeInteractive, "bash", 30, LoadOptions{MaxParallel: 2})
+	elapsed := time.Since(start)
+
+	if len(results) != 6 {
+		t.Fatalf("Expected 6 contexts, got %d", len(results))
+	}
+	for i, name := range contextOrder {
+		if results[i].Name != name {
+			t.Errorf("Expected context %d to be %s, got %s", i, name, results[i].Name)
+		}
+	}
+	if results[0].Content != "output of git log" {
+		t.Errorf("Expected command output, got %q", results[0].Content)
+	}
+	if results[1].Content != "Static context" {
+		t.Errorf("Expected file content, got %q", results[1].Content)
+	}
+
+	if cmdRunner.peak > 2 {
+		t.Errorf("Expected at most 2 concurrent commands, got %d", cmdRunner.peak)
+	}
+	// 5 commands, 2 at a time: 3 rounds instead of 5 sequential
+	if elapsed >= 250*time.Millisecond {
+		t.Errorf("Expected commands to run concurrently, took %v", elapsed)
+	}
+}
+
+// slowURLFetcher waits for each fetch unless ctx ends first
+type slowURLFetcher struct {
+	delay time.Duration
+}
+
+func (m *slowURLFetcher) Fetch(ctx context.Context, url string, timeout time.Duration) (domain.FetchedURL, error) {
+	select {
+	case <-time.After(m.delay):
+		return domain.FetchedURL{Body: "body of " + url, FetchedAt: time.Now()}, nil
+	case <-ctx.Done():
+		return domain.FetchedURL{}, fmt.Errorf("failed to fetch %s: %w", url, ctx.Err())
+	}
+}
+
+func TestContextLoader_LoadContexts_ParallelURLs(t *testing.T) {
+	fs := newMockFileSystem()
+	contexts := map[string]domain.Context{
+		"api":    {URL: "https://example.com/api.md", Prompt: "{url_contents}"},
+		"guide":  {URL: "https://example.com/guide.md", Prompt: "{url_contents}"},
+		"readme": {URL: "https://example.com/readme.md", Prompt: "{url_contents}"},
+	}
+	contextOrder := []string{"api", "guide", "readme"}
+
+	fetcher := &slowURLFetcher{delay: 100 * time.Millisecond}
+	loader := NewContextLoader(NewUTDProcessor(fs, &slowCommandRunner{}, "/workdir", nil, fetcher))
+
+	start := time.Now()
+	results := loader.LoadContexts(contexts, contextOrder, CommandTypeInteractive, "bash", 30, LoadOptions{MaxParallel: 3})
+	elapsed := time.Since(start)
+
+	for i, name := range contextOrder {
+		if results[i].Name != name || results[i].Content != "body of https://example.com/"+name+".md" {
+			t.Errorf("Expected context %d to be %s with its url body, got %+v", i, name, results[i])
+		}
+	}
+	// 3 fetches at once instead of 300ms one after another
+	if elapsed >= 250*time.Millisecond {
+		t.Errorf("Expected urls to be fetched concurrently, took %v", elapsed)
+	}
+
+	// The per-run deadline stops fetches too
+	fetcher.delay = 3 * time.Second
+	start = time.Now()
+	results = loader.LoadContexts(contexts, contextOrder, CommandTypeInteractive, "bash", 30, LoadOptions{
+		MaxParallel: 3,
+		Deadline:    100 * time.Millisecond,
+	})
+	if elapsed := time.Since(start); elapsed >= time.Second {
+		t.Errorf("Expected deadline to stop fetches after 100ms, took %v", elapsed)
+	}
+	for _, r := range results {
+		if !r.Skipped || len(r.Warnings) == 0 || !strings.Contains(r.Warnings[0], "context deadline exceeded") {
+			t.Errorf("Expected %s skipped with a deadline warning, got %+v", r.Name, r)
+		}
+	}
+}
+
+func TestContextLoader_LoadContexts_Deadline(t *testing.T) {
+	fs := newMockFileSystem()
+
+	cmdRunner := &slowCommandRunner{delay: 3 * time.Second}
+	utdProcessor := NewUTDProcessor(fs, cmdRunner, "/workdir", nil, nil)
+	loader := NewContextLoader(utdProcessor)
+
+	contexts := map[string]domain.Context{
+		"slow":    {Command: "sleep 3"},
+		"waiting": {Command: "sleep 3"},
+	}
+	contextOrder := []string{"slow", "waiting"}
+
+	// One worker: the other command waits for a slot until the deadline has passed
+	start := time.Now()
+	results := loader.LoadContexts(contexts, contextOrder, CommandTypeInteractive, "bash", 30, LoadOptions{
+		MaxParallel: 1,
+		Deadline:    time.Second,
+	})
+	elapsed := time.Since(start)
+
+	if elapsed >= 2*time.Second {
+		t.Errorf("Expected deadline to stop commands after 1s, took %v", elapsed)
+	}
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 contexts, got %d", len(results))
+	}
+	// Workers may start in either order: one command times out, the other never runs
+	var warnings []string
+	for _, r := range results {
+		warnings = append(warnings, r.Warnings...)
+	}
+	joined := strings.Join(warnings, "\n")
+	if !strings.Contains(joined, "Command failed: command timeout after 1 seconds") {
+		t.Errorf("Expected a command clamped to the deadline, got %v", warnings)
+	}
+	if !strings.Contains(joined, "Command skipped: command deadline exceeded") {
+		t.Errorf("Expected a command skipped after the deadline, got %v", warnings)
+	}
+}
+
+func TestContextLoader_LoadContexts_Selection(t *testing.T) {
+	fs := newMockFileSystem()
+	fs.files["/env.md"] = "Environment"
+	fs.files["/project.md"] = "Project"
+	fs.files["/owasp.md"] = "OWASP"
+	fs.files["/threats.md"] = "Threat model"
+
+	loader := NewContextLoader(NewUTDProcessor(fs, &mockCommandRunner{}, "/workdir", nil, nil))
+
+	contexts := map[string]domain.Context{
+		"env":     {File: "/env.md", Required: true},
+		"project": {File: "/project.md"},
+		"owasp":   {File: "/owasp.md", Tags: []string{"security"}},
+		"threats": {File: "/threats.md", Tags: []string{"security", "design"}},
+	}
+	order := []string{"env", "project", "owasp", "threats"}
+
+	tests := []struct {
+		name        string
+		commandType CommandType
+		selection   ContextSelection
+		want        string
+	}{
+		{"interactive defaults", CommandTypeInteractive, ContextSelection{}, "env,project,owasp,threats"},
+		{"task defaults", CommandTypeTask, ContextSelection{}, "env"},
+		{"include by name", CommandTypeTask, ContextSelection{Include: []string{"project"}}, "env,project"},
+		{"include by tag", CommandTypePrompt, ContextSelection{Tags: []string{"security"}}, "env,owasp,threats"},
+		{"exclude wins", CommandTypeTask, ContextSelection{Tags: []string{"security"}, Exclude: []string{"env", "threats"}}, "owasp"},
+		{"no defaults", CommandTypeInteractive, ContextSelection{NoDefaults: true}, ""},
+		{"no defaults with tag", CommandTypeInteractive, ContextSelection{NoDefaults: true, Tags: []string{"design"}}, "threats"},
+	}
+
+	for _, tt := range tests {
+		results := loader.LoadContexts(contexts, order, tt.commandType, "bash", 30, LoadOptions{Selection: tt.selection})
+		var names []string
+		for _, r := range results {
+			names = append(names, r.Name)
+		}
+		if got := strings.Join(names, ","); got != tt.want {
+			t.Errorf("%s: loaded %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -1,73 +1,435 @@
 package engine
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/grantcarthew/start/internal/domain"
 )
 
 // Executor executes agent commands with resolved placeholders
 type Executor struct {
-	runner   domain.Runner
-	resolver *PlaceholderResolver
+	runner        domain.Runner
+	processRunner domain.ProcessRunner
+	resolver      *PlaceholderResolver
+	fs            domain.FileSystem
+	history       domain.HistoryStore
 }
 
 // NewExecutor creates a new executor
-func NewExecutor(runner domain.Runner, resolver *PlaceholderResolver) *Executor {
+// history may be nil to disable invocation recording
+func NewExecutor(
+	runner domain.Runner,
+	processRunner domain.ProcessRunner,
+	resolver *PlaceholderResolver,
+	fs domain.FileSystem,
+	history domain.HistoryStore,
+) *Executor {
 	return &Executor{
-		runner:   runner,
-		resolver: resolver,
+		runner:        runner,
+		processRunner: processRunner,
+		resolver:      resolver,
+		fs:            fs,
+		history:       history,
 	}
 }
 
 // ExecuteParams holds parameters for execution
 type ExecuteParams struct {
-	Agent         domain.Agent
-	Model         string
-	UserPrompt    string
-	RoleContent   string
-	RoleFilePath  string
-	Contexts      []LoadedContext
-	Shell         string
+	Agent        domain.Agent
+	Model        string
+	UserPrompt   string
+	RoleContent  string
+	RoleFilePath string
+	Contexts     []LoadedContext
+	Shell        string
+	Headless     bool                // Use the agent's headless_command when defined
+	Layout       domain.PromptLayout // How role, contexts and user prompt are assembled
+	Variables    map[string]string   // Values for {var:name} in the command template
+
+	// Selection details recorded in the history log
+	Mode         CommandType
+	ModelName    string
+	RoleName     string
+	TaskName     string
+	Instructions string
+	TaskParams   map[string]string
+	Selection    ContextSelection // Context selection flags, without task contexts
+	Output       string           // Headless output file
 }
 
-// Execute runs an agent command with the given parameters
-// This function replaces the current process and never returns on success
-func (e *Executor) Execute(params ExecuteParams) error {
+// PreparedCommand is the fully resolved agent invocation
+type PreparedCommand struct {
+	Shell      string   // Shell that runs the command (empty for argv invocation)
+	Command    string   // Command with all placeholders resolved (display form for argv)
+	Args       []string // Resolved argv when the agent uses args instead of command
+	Prompt     string   // Final composed prompt (contexts + user prompt)
+	Delivery   string   // Prompt delivery mode: arg, file or stdin
+	PromptFile string   // Temp file holding the prompt ({prompt_file} and stdin delivery)
+	Warnings   []string // Unavailable built-in placeholders
+}
+
+// Prepare composes the final prompt and resolves the agent command template
+// without executing anything. On error the composed prompt is still returned.
+// Callers must call Cleanup on the result when it is not executed.
+func (e *Executor) Prepare(params ExecuteParams) (PreparedCommand, error) {
 	// Build final prompt by combining contexts, role, and user prompt
-	finalPrompt := e.buildFinalPrompt(params.Contexts, params.UserPrompt)
+	finalPrompt, warnings := e.buildFinalPrompt(params)
+
+	// Headless runs use the one-shot command template when the agent has one
+	agent := params.Agent
+	if params.Headless && agent.HeadlessCommand != "" {
+		agent.Command = agent.HeadlessCommand
+		agent.Args = nil
+	}
+
+	delivery := agent.PromptDelivery
+	if delivery == "" {
+		delivery = PromptDeliveryArg
+	}
+	result := PreparedCommand{
+		Shell:    params.Shell,
+		Prompt:   finalPrompt,
+		Delivery: delivery,
+		Warnings: warnings,
+	}
 
 	// Prepare placeholder values
 	values := map[string]string{
-		"bin":       params.Agent.Bin,
+		"bin":       agent.Bin,
 		"model":     params.Model,
 		"prompt":    finalPrompt,
 		"role":      params.RoleContent,
 		"role_file": params.RoleFilePath,
 	}
 
-	// Resolve placeholders in command template
-	command := e.resolver.Resolve(params.Agent.Command, values)
+	template := agent.Command
+	if len(agent.Args) > 0 {
+		template = strings.Join(agent.Args, " ")
+	}
+
+	// Built-in placeholders ({env:VAR}, {git_branch}, ...) used by the template
+	builtins, warnings := e.resolver.Builtins(template)
+	for key, value := range builtins {
+		values[key] = value
+	}
+	result.Warnings = append(result.Warnings, warnings...)
+
+	// User variables are escaped like any other value
+	for key, value := range variableValues(params.Variables) {
+		values[key] = value
+	}
+
+	switch delivery {
+	case PromptDeliveryArg:
+	case PromptDeliveryFile, PromptDeliveryStdin:
+		// The prompt does not travel on the command line
+		values["prompt"] = ""
+	default:
+		return result, fmt.Errorf("invalid prompt_delivery %q for agent %q: must be arg, file or stdin", delivery, agent.Name)
+	}
+
+	// Write the prompt to a temp file for {prompt_file} and stdin delivery
+	if delivery != PromptDeliveryArg || strings.Contains(template, "{prompt_file}") {
+		path, err := e.writePromptFile(finalPrompt)
+		if err != nil {
+			return result, err
+		}
+		result.PromptFile = path
+		values["prompt_file"] = path
+	}
+
+	// Argv invocation: resolve each element separately, no shell and no quoting
+	if len(agent.Args) > 0 {
+		args := make([]string, len(agent.Args))
+		for i, arg := range agent.Args {
+			args[i] = e.resolver.ResolveArg(arg, values)
+		}
+		result.Shell = ""
+		result.Command = FormatArgs(args)
+		result.Args = args
+		return result, nil
+	}
+
+	// Resolve placeholders in command template (escaped for the shell's quoting)
+	command, err := e.resolver.ResolveCommand(agent.Command, values, params.Shell)
+	if err != nil {
+		var quoteErr *QuoteError
+		if errors.As(err, &quoteErr) {
+			quoteErr.Agent = agent.Name
+			quoteErr.Config = agent.ConfigPath
+		}
+		return result, err
+	}
+	result.Command = command
+
+	return result, nil
+}
 
+// Execute runs an agent command with the given parameters
+// This function replaces the current process and never returns on success
+func (e *Executor) Execute(params ExecuteParams) error {
+	prepared, err := e.Prepare(params)
+	if err != nil {
+		e.Cleanup(prepared)
+		return err
+	}
+
+	e.warn(prepared)
+	if err := e.ready(prepared, params.Agent.Name); err != nil {
+		return err
+	}
+	e.record(NewHistoryEntry(params, prepared))
+
+	return e.exec(prepared)
+}
+
+// Replay re-runs the exact command recorded in a history entry
+// This function replaces the current process and never returns on success
+func (e *Executor) Replay(entry domain.HistoryEntry) error {
+	prepared, err := e.prepareReplay(entry)
+	if err != nil {
+		return err
+	}
+
+	if err := e.ready(prepared, entry.Agent); err != nil {
+		return err
+	}
+	e.recordReplay(entry)
+
+	return e.exec(prepared)
+}
+
+// ReplayCapture re-runs the command recorded in a headless history entry as
+// a child process and copies its stdout to stdout
+// Returns the agent's exit code; an error means the agent could not be run
+func (e *Executor) ReplayCapture(entry domain.HistoryEntry, stdout io.Writer) (int, error) {
+	prepared, err := e.prepareReplay(entry)
+	defer e.Cleanup(prepared)
+	if err != nil {
+		return -1, err
+	}
+
+	if err := e.checkArgSize(prepared, entry.Agent); err != nil {
+		return -1, err
+	}
+	stdin, err := e.promptStdin(prepared)
+	if err != nil {
+		return -1, err
+	}
+	e.recordReplay(entry)
+
+	return e.run(prepared, stdin, stdout)
+}
+
+// prepareReplay rebuilds the recorded command and recreates the prompt file
+// it refers to
+func (e *Executor) prepareReplay(entry domain.HistoryEntry) (PreparedCommand, error) {
+	prepared := PreparedFromHistory(entry)
+	if prepared.PromptFile != "" {
+		if err := e.fs.WriteFile(prepared.PromptFile, []byte(prepared.Prompt), 0600); err != nil {
+			return prepared, fmt.Errorf("failed to recreate prompt file: %w", err)
+		}
+	}
+	return prepared, nil
+}
+
+// recordReplay records a replayed entry as a new invocation
+func (e *Executor) recordReplay(entry domain.HistoryEntry) {
+	entry.Timestamp = time.Now()
+	entry.ID = newHistoryID(entry.Timestamp)
+	e.record(entry)
+}
+
+// ready runs the checks that can fail before exec and redirects stdin
+// Only a command that passes them is recorded in history
+func (e *Executor) ready(prepared PreparedCommand, agentName string) error {
+	// Check the command line against the system limit before exec (E2BIG)
+	if err := e.checkArgSize(prepared, agentName); err != nil {
+		e.Cleanup(prepared)
+		return err
+	}
+
+	if prepared.Delivery == PromptDeliveryStdin {
+		if err := e.runner.RedirectStdin(prepared.PromptFile); err != nil {
+			e.Cleanup(prepared)
+			return err
+		}
+		// The redirected descriptor keeps the prompt readable, so the file is
+		// removed now unless the command also refers to it by {prompt_file}
+		if !strings.Contains(prepared.Command, prepared.PromptFile) {
+			e.Cleanup(prepared)
+		}
+	}
+
+	return nil
+}
+
+// exec runs a prepared command by replacing the current process
+func (e *Executor) exec(prepared PreparedCommand) error {
 	// Replace process with agent (never returns on success)
-	return e.runner.Exec(params.Shell, command)
+	var err error
+	if len(prepared.Args) > 0 {
+		err = e.runner.ExecArgs(prepared.Args)
+	} else {
+		err = e.runner.Exec(prepared.Shell, prepared.Command)
+	}
+	e.Cleanup(prepared)
+	return err
 }
 
-// buildFinalPrompt combines contexts and user prompt
-func (e *Executor) buildFinalPrompt(contexts []LoadedContext, userPrompt string) string {
-	var parts []string
+// record appends an entry to the history log
+// Recording is best effort and never blocks a launch
+func (e *Executor) record(entry domain.HistoryEntry) {
+	if e.history == nil {
+		return
+	}
+	if err := e.history.Append(entry); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠ Failed to record history: %v\n", err)
+	}
+}
+
+// warn prints placeholder warnings for a prepared command to stderr
+func (e *Executor) warn(prepared PreparedCommand) {
+	for _, w := range prepared.Warnings {
+		fmt.Fprintf(os.Stderr, "⚠ %s\n", w)
+	}
+}
+
+// Capture runs the agent as a child process and copies its stdout to stdout
+// Returns the agent's exit code; an error means the agent could not be run
+func (e *Executor) Capture(params ExecuteParams, stdout io.Writer) (int, error) {
+	prepared, err := e.Prepare(params)
+	defer e.Cleanup(prepared)
+	if err != nil {
+		return -1, err
+	}
+
+	if err := e.checkArgSize(prepared, params.Agent.Name); err != nil {
+		return -1, err
+	}
+
+	stdin, err := e.promptStdin(prepared)
+	if err != nil {
+		return -1, err
+	}
+
+	e.warn(prepared)
+	e.record(NewHistoryEntry(params, prepared))
+
+	return e.run(prepared, stdin, stdout)
+}
+
+// promptStdin returns the stdin of a headless agent: the prompt for stdin
+// delivery, nothing otherwise
+func (e *Executor) promptStdin(prepared PreparedCommand) (io.Reader, error) {
+	if prepared.Delivery != PromptDeliveryStdin {
+		return nil, nil
+	}
+	data, err := e.fs.ReadFile(prepared.PromptFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read prompt file: %w", err)
+	}
+	return bytes.NewReader(data), nil
+}
+
+// run runs a prepared command as a child process
+func (e *Executor) run(prepared PreparedCommand, stdin io.Reader, stdout io.Writer) (int, error) {
+	if len(prepared.Args) > 0 {
+		return e.processRunner.RunArgs(prepared.Args, stdin, stdout)
+	}
+	return e.processRunner.Run(prepared.Shell, prepared.Command, stdin, stdout)
+}
+
+// checkArgSize checks the prepared command against the system argument limit
+func (e *Executor) checkArgSize(prepared PreparedCommand, agentName string) error {
+	argv := prepared.Args
+	if len(argv) == 0 {
+		argv = []string{prepared.Shell, "-c", prepared.Command}
+	}
+	if err := CheckArgSize(argv); err != nil {
+		var limitErr *ArgLimitError
+		if errors.As(err, &limitErr) {
+			limitErr.Agent = agentName
+			limitErr.PromptSize = len(prepared.Prompt)
+		}
+		return err
+	}
+	return nil
+}
+
+// Cleanup removes the prompt temp file created by Prepare
+func (e *Executor) Cleanup(prepared PreparedCommand) error {
+	if prepared.PromptFile != "" {
+		return e.fs.Remove(prepared.PromptFile)
+	}
+	return nil
+}
+
+// writePromptFile writes the composed prompt to a temp file and returns its path
+func (e *Executor) writePromptFile(prompt string) (string, error) {
+	path, err := e.fs.TempFile("start-prompt-*.md")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file for prompt: %w", err)
+	}
+
+	if err := e.fs.WriteFile(path, []byte(prompt), 0600); err != nil {
+		e.fs.Remove(path) // Clean up on error
+		return "", fmt.Errorf("failed to write temp file for prompt: %w", err)
+	}
 
-	// Add context documents
-	for _, ctx := range contexts {
+	return path, nil
+}
+
+// buildFinalPrompt assembles role, contexts and user prompt according to the layout
+// Returns warnings for undefined variables and unavailable built-ins in header and footer
+func (e *Executor) buildFinalPrompt(params ExecuteParams) (string, []string) {
+	layout := params.Layout
+	var warnings []string
+
+	var sections []promptSection
+	if layout.RolePlacement == RolePlacementPrompt && params.RoleContent != "" {
+		sections = append(sections, promptSection{kind: "role", name: params.RoleName, content: params.RoleContent})
+	}
+	for _, ctx := range params.Contexts {
 		if ctx.Content != "" {
-			parts = append(parts, ctx.Content)
+			sections = append(sections, promptSection{kind: "context", name: ctx.Name, content: ctx.Content})
+		}
+	}
+
+	var parts []string
+	if layout.Header != "" {
+		header, w := ExpandVariables(layout.Header, params.Variables)
+		warnings = append(warnings, w...)
+		header, w = e.resolver.ResolveBuiltins(header)
+		parts = append(parts, header)
+		warnings = append(warnings, w...)
+	}
+	for _, section := range sections {
+		parts = append(parts, formatSection(layout.Style, section))
+	}
+
+	// The user prompt is only delimited when it follows other sections
+	if params.UserPrompt != "" {
+		if len(sections) > 0 {
+			parts = append(parts, formatSection(layout.Style, promptSection{kind: "prompt", content: params.UserPrompt}))
+		} else {
+			parts = append(parts, params.UserPrompt)
 		}
 	}
 
-	// Add user prompt
-	if userPrompt != "" {
-		parts = append(parts, userPrompt)
+	if layout.Footer != "" {
+		footer, w := ExpandVariables(layout.Footer, params.Variables)
+		warnings = append(warnings, w...)
+		footer, w = e.resolver.ResolveBuiltins(footer)
+		parts = append(parts, footer)
+		warnings = append(warnings, w...)
 	}
 
-	return strings.Join(parts, "\n\n")
+	return strings.Join(parts, "\n\n"), warnings
 }
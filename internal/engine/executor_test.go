@@ -13,8 +13,8 @@ import (
 func TestExecutor_Execute_Success(t *testing.T) {
 	mockRunner := &mocks.MockRunner{}
 
-	resolver := engine.NewPlaceholderResolver()
-	executor := engine.NewExecutor(mockRunner, resolver)
+	resolver := engine.NewPlaceholderResolver(nil, "")
+	executor := engine.NewExecutor(mockRunner, &mocks.MockProcessRunner{}, resolver, mocks.NewMockFileSystem(), nil)
 
 	agent := domain.Agent{
 		Name:    "smith",
@@ -42,8 +42,8 @@ func TestExecutor_Execute_Success(t *testing.T) {
 
 func TestExecutor_Execute_PlaceholderResolution(t *testing.T) {
 	mockRunner := &mocks.MockRunner{}
-	resolver := engine.NewPlaceholderResolver()
-	executor := engine.NewExecutor(mockRunner, resolver)
+	resolver := engine.NewPlaceholderResolver(nil, "")
+	executor := engine.NewExecutor(mockRunner, &mocks.MockProcessRunner{}, resolver, mocks.NewMockFileSystem(), nil)
 
 	agent := domain.Agent{
 		Name:    "test-agent",
@@ -72,8 +72,8 @@ func TestExecutor_Execute_Error(t *testing.T) {
 		ErrorMessage: "command failed",
 	}
 
-	resolver := engine.NewPlaceholderResolver()
-	executor := engine.NewExecutor(mockRunner, resolver)
+	resolver := engine.NewPlaceholderResolver(nil, "")
+	executor := engine.NewExecutor(mockRunner, &mocks.MockProcessRunner{}, resolver, mocks.NewMockFileSystem(), nil)
 
 	agent := domain.Agent{
 		Name:    "smith",
@@ -97,8 +97,8 @@ func TestExecutor_Execute_Error(t *testing.T) {
 
 func TestExecutor_Execute_DatePlaceholder(t *testing.T) {
 	mockRunner := &mocks.MockRunner{}
-	resolver := engine.NewPlaceholderResolver()
-	executor := engine.NewExecutor(mockRunner, resolver)
+	resolver := engine.NewPlaceholderResolver(nil, "")
+	executor := engine.NewExecutor(mockRunner, &mocks.MockProcessRunner{}, resolver, mocks.NewMockFileSystem(), nil)
 
 	agent := domain.Agent{
 		Name:    "smith",
@@ -124,8 +124,8 @@ func TestExecutor_Execute_DatePlaceholder(t *testing.T) {
 
 func TestExecutor_Execute_CustomShell(t *testing.T) {
 	mockRunner := &mocks.MockRunner{}
-	resolver := engine.NewPlaceholderResolver()
-	executor := engine.NewExecutor(mockRunner, resolver)
+	resolver := engine.NewPlaceholderResolver(nil, "")
+	executor := engine.NewExecutor(mockRunner, &mocks.MockProcessRunner{}, resolver, mocks.NewMockFileSystem(), nil)
 
 	agent := domain.Agent{
 		Name:    "smith",
@@ -147,3 +147,278 @@ func TestExecutor_Execute_CustomShell(t *testing.T) {
 	assert.Equal(t, 1, len(mockRunner.CalledWith))
 	assert.Equal(t, "sh", mockRunner.CalledWith[0].Shell)
 }
+
+func TestExecutor_Execute_EscapesSingleQuotes(t *testing.T) {
+	mockRunner := &mocks.MockRunner{}
+	resolver := engine.NewPlaceholderResolver(nil, "")
+	executor := engine.NewExecutor(mockRunner, &mocks.MockProcessRunner{}, resolver, mocks.NewMockFileSystem(), nil)
+
+	agent := domain.Agent{
+		Name:    "claude",
+		Bin:     "claude",
+		Command: "{bin} --model {model} --append-system-prompt '{role}' '{prompt}'",
+	}
+
+	params := engine.ExecuteParams{
+		Agent:       agent,
+		Model:       "sonnet",
+		UserPrompt:  "what's new?",
+		RoleContent: "You're a Go expert",
+		Contexts:    []engine.LoadedContext{},
+		Shell:       "bash",
+	}
+
+	err := executor.Execute(params)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(mockRunner.CalledWith))
+	assert.Equal(t, `claude --model sonnet --append-system-prompt 'You'\''re a Go expert' 'what'\''s new?'`, mockRunner.CalledWith[0].Command)
+}
+
+func TestExecutor_Execute_UnsafeUnquotedPlaceholder(t *testing.T) {
+	mockRunner := &mocks.MockRunner{}
+	resolver := engine.NewPlaceholderResolver(nil, "")
+	executor := engine.NewExecutor(mockRunner, &mocks.MockProcessRunner{}, resolver, mocks.NewMockFileSystem(), nil)
+
+	agent := domain.Agent{
+		Name:    "broken",
+		Bin:     "smith",
+		Command: "{bin} {prompt}",
+	}
+
+	params := engine.ExecuteParams{
+		Agent:      agent,
+		Model:      "test-model",
+		UserPrompt: "hello world",
+		Contexts:   []engine.LoadedContext{},
+		Shell:      "bash",
+	}
+
+	err := executor.Execute(params)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Agent: broken")
+	assert.Contains(t, err.Error(), "Position: characters 6-14")
+	assert.Equal(t, 0, len(mockRunner.CalledWith))
+}
+
+func TestExecutor_Prepare_DoesNotExecute(t *testing.T) {
+	mockRunner := &mocks.MockRunner{}
+	resolver := engine.NewPlaceholderResolver(nil, "")
+	executor := engine.NewExecutor(mockRunner, &mocks.MockProcessRunner{}, resolver, mocks.NewMockFileSystem(), nil)
+
+	agent := domain.Agent{
+		Name:    "smith",
+		Bin:     "smith",
+		Command: "{bin} --model {model} '{prompt}'",
+	}
+
+	params := engine.ExecuteParams{
+		Agent:      agent,
+		Model:      "test-model",
+		UserPrompt: "hello",
+		Contexts: []engine.LoadedContext{
+			{Name: "ctx", Content: "context content"},
+		},
+		Shell: "bash",
+	}
+
+	prepared, err := executor.Prepare(params)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(mockRunner.CalledWith))
+	assert.Equal(t, "bash", prepared.Shell)
+	assert.Equal(t, "context content\n\nhello", prepared.Prompt)
+	assert.Equal(t, "smith --model test-model 'context content\n\nhello'", prepared.Command)
+}
+
+func TestExecutor_Execute_ArgsWithoutShell(t *testing.T) {
+	mockRunner := &mocks.MockRunner{}
+	resolver := engine.NewPlaceholderResolver(nil, "")
+	executor := engine.NewExecutor(mockRunner, &mocks.MockProcessRunner{}, resolver, mocks.NewMockFileSystem(), nil)
+
+	agent := domain.Agent{
+		Name: "claude",
+		Bin:  "claude",
+		Args: []string{"{bin}", "--model", "{model}", "--system={role}", "{prompt}"},
+	}
+
+	params := engine.ExecuteParams{
+		Agent:       agent,
+		Model:       "sonnet",
+		UserPrompt:  "it's {date} \"quoted\"",
+		RoleContent: "You're a Go expert",
+		Contexts:    []engine.LoadedContext{},
+		Shell:       "bash",
+	}
+
+	err := executor.Execute(params)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(mockRunner.CalledWith))
+
+	args := mockRunner.CalledWith[0].Args
+	assert.Equal(t, 5, len(args))
+	assert.Equal(t, "claude", args[0])
+	assert.Equal(t, "sonnet", args[2])
+	assert.Equal(t, "--system=You're a Go expert", args[3])
+	// Values are passed verbatim: no escaping, no nested placeholder resolution
+	assert.Equal(t, "it's {date} \"quoted\"", args[4])
+	assert.Equal(t, "", mockRunner.CalledWith[0].Command)
+}
+
+func TestExecutor_Prepare_PromptFile(t *testing.T) {
+	mockRunner := &mocks.MockRunner{}
+	mockFS := mocks.NewMockFileSystem()
+	executor := engine.NewExecutor(mockRunner, &mocks.MockProcessRunner{}, engine.NewPlaceholderResolver(nil, ""), mockFS, nil)
+
+	agent := domain.Agent{
+		Name:           "claude",
+		Bin:            "claude",
+		Command:        "{bin} --model {model} --file '{prompt_file}' '{prompt}'",
+		PromptDelivery: "file",
+	}
+
+	params := engine.ExecuteParams{
+		Agent:      agent,
+		Model:      "sonnet",
+		UserPrompt: "review this",
+		Contexts: []engine.LoadedContext{
+			{Name: "readme", Content: "project readme"},
+		},
+		Shell: "bash",
+	}
+
+	prepared, err := executor.Prepare(params)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "file", prepared.Delivery)
+	assert.Equal(t, "project readme\n\nreview this", mockFS.Files[prepared.PromptFile])
+	// {prompt} is empty: the prompt never travels on the command line
+	assert.Equal(t, "claude --model sonnet --file '"+prepared.PromptFile+"' ''", prepared.Command)
+
+	assert.NoError(t, executor.Cleanup(prepared))
+	assert.False(t, mockFS.Exists(prepared.PromptFile), "prompt file should be removed")
+}
+
+func TestExecutor_Execute_StdinDelivery(t *testing.T) {
+	mockRunner := &mocks.MockRunner{}
+	mockFS := mocks.NewMockFileSystem()
+	var fileAtExec bool
+	mockRunner.OnExec = func() { fileAtExec = mockFS.Exists(mockRunner.StdinPath) }
+	executor := engine.NewExecutor(mockRunner, &mocks.MockProcessRunner{}, engine.NewPlaceholderResolver(nil, ""), mockFS, nil)
+
+	agent := domain.Agent{
+		Name:           "claude",
+		Bin:            "claude",
+		Args:           []string{"{bin}", "--model", "{model}", "--print"},
+		PromptDelivery: "stdin",
+	}
+
+	params := engine.ExecuteParams{
+		Agent:      agent,
+		Model:      "sonnet",
+		UserPrompt: "review this",
+		Shell:      "bash",
+	}
+
+	err := executor.Execute(params)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(mockRunner.CalledWith))
+	assert.Contains(t, mockRunner.StdinPath, "start-prompt-")
+	assert.Equal(t, "claude --model sonnet --print", strings.Join(mockRunner.CalledWith[0].Args, " "))
+	// The prompt file is removed once stdin is redirected, before exec
+	assert.False(t, fileAtExec, "prompt file should be removed")
+
+	// A command that also reads {prompt_file} keeps it
+	agent.Args = []string{"{bin}", "--print", "--file", "{prompt_file}"}
+	params.Agent = agent
+	assert.NoError(t, executor.Execute(params))
+	assert.True(t, fileAtExec, "prompt file should be kept")
+}
+
+func TestExecutor_Execute_PromptTooLarge(t *testing.T) {
+	mockRunner := &mocks.MockRunner{}
+	executor := engine.NewExecutor(mockRunner, &mocks.MockProcessRunner{}, engine.NewPlaceholderResolver(nil, ""), mocks.NewMockFileSystem(), nil)
+
+	agent := domain.Agent{
+		Name:    "claude",
+		Bin:     "claude",
+		Command: "{bin} --model {model} '{prompt}'",
+	}
+
+	params := engine.ExecuteParams{
+		Agent:      agent,
+		Model:      "sonnet",
+		UserPrompt: strings.Repeat("x", 4*1024*1024),
+		Shell:      "bash",
+	}
+
+	err := executor.Execute(params)
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "too large to execute")
+	assert.Contains(t, err.Error(), "prompt_delivery")
+	assert.Equal(t, 0, len(mockRunner.CalledWith))
+}
+
+func TestExecutor_Capture_HeadlessCommand(t *testing.T) {
+	mockRunner := &mocks.MockRunner{}
+	processRunner := &mocks.MockProcessRunner{Output: "the answer", ExitCode: 2}
+	executor := engine.NewExecutor(mockRunner, processRunner, engine.NewPlaceholderResolver(nil, ""), mocks.NewMockFileSystem(), nil)
+
+	agent := domain.Agent{
+		Name:            "claude",
+		Bin:             "claude",
+		Command:         "{bin} --model {model} '{prompt}'",
+		HeadlessCommand: "{bin} --print --model {model} '{prompt}'",
+	}
+
+	params := engine.ExecuteParams{
+		Agent:      agent,
+		Model:      "sonnet",
+		UserPrompt: "hello",
+		Shell:      "bash",
+		Headless:   true,
+	}
+
+	var out strings.Builder
+	code, err := executor.Capture(params, &out)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, code)
+	assert.Equal(t, "the answer", out.String())
+	assert.Equal(t, 0, len(mockRunner.CalledWith))
+	assert.Equal(t, 1, len(processRunner.CalledWith))
+	assert.Equal(t, "claude --print --model sonnet 'hello'", processRunner.CalledWith[0].Command)
+}
+
+func TestExecutor_Capture_StdinDelivery(t *testing.T) {
+	processRunner := &mocks.MockProcessRunner{}
+	executor := engine.NewExecutor(&mocks.MockRunner{}, processRunner, engine.NewPlaceholderResolver(nil, ""), mocks.NewMockFileSystem(), nil)
+
+	agent := domain.Agent{
+		Name:           "claude",
+		Bin:            "claude",
+		Args:           []string{"{bin}", "--model", "{model}"},
+		PromptDelivery: "stdin",
+	}
+
+	params := engine.ExecuteParams{
+		Agent:      agent,
+		Model:      "sonnet",
+		UserPrompt: "from stdin",
+		Shell:      "bash",
+		Headless:   true,
+	}
+
+	var out strings.Builder
+	code, err := executor.Capture(params, &out)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, code)
+	// No headless_command: the regular args template is used
+	assert.Equal(t, "claude --model sonnet", strings.Join(processRunner.CalledWith[0].Args, " "))
+	assert.Equal(t, "from stdin", processRunner.Stdin[0])
+}
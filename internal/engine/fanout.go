@@ -0,0 +1,73 @@
+package engine
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// FanOutResult is one agent's outcome in a fan-out run
+type FanOutResult struct {
+	Agent    string
+	Path     string // File holding the agent's output
+	ExitCode int
+	Bytes    int
+	Duration time.Duration
+	Err      error // The agent could not be run or its output not written
+}
+
+// Failed reports whether the agent errored or exited non-zero
+func (r FanOutResult) Failed() bool {
+	return r.Err != nil || r.ExitCode != 0
+}
+
+// FanOut runs each set of params headless and concurrently, writing each
+// agent's stdout to <outDir>/<agent>.md
+// Returns results in the order of runs; no agent runs if outDir cannot be created
+func (e *Executor) FanOut(runs []ExecuteParams, outDir string) []FanOutResult {
+	results := make([]FanOutResult, len(runs))
+	outputs := make([]bytes.Buffer, len(runs))
+	for i, params := range runs {
+		results[i] = FanOutResult{
+			Agent: params.Agent.Name,
+			Path:  filepath.Join(outDir, params.Agent.Name+".md"),
+		}
+	}
+
+	if err := e.fs.MkdirAll(outDir, 0755); err != nil {
+		err = fmt.Errorf("failed to create output directory: %w", err)
+		for i := range results {
+			results[i].Err = err
+		}
+		return results
+	}
+
+	// Each agent writes its own slot, so results keep the requested order
+	var wg sync.WaitGroup
+	for i, params := range runs {
+		params.Headless = true
+
+		wg.Add(1)
+		go func(i int, params ExecuteParams) {
+			defer wg.Done()
+			start := time.Now()
+			results[i].ExitCode, results[i].Err = e.Capture(params, &outputs[i])
+			results[i].Duration = time.Since(start)
+		}(i, params)
+	}
+	wg.Wait()
+
+	for i := range results {
+		if results[i].Err != nil {
+			continue
+		}
+		results[i].Bytes = outputs[i].Len()
+		if err := e.fs.WriteFile(results[i].Path, outputs[i].Bytes(), 0644); err != nil {
+			results[i].Err = fmt.Errorf("failed to write output file: %w", err)
+		}
+	}
+
+	return results
+}
@@ -0,0 +1,79 @@
+package engine_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/grantcarthew/start/internal/domain"
+	"github.com/grantcarthew/start/internal/engine"
+	"github.com/grantcarthew/start/test/assert"
+	"github.com/grantcarthew/start/test/mocks"
+)
+
+func fanOutRuns(names ...string) []engine.ExecuteParams {
+	var runs []engine.ExecuteParams
+	for _, name := range names {
+		runs = append(runs, engine.ExecuteParams{
+			Agent: domain.Agent{
+				Name:            name,
+				Bin:             name,
+				Command:         "{bin} '{prompt}'",
+				HeadlessCommand: "{bin} --print '{prompt}'",
+			},
+			UserPrompt: "compare me",
+			Shell:      "bash",
+		})
+	}
+	return runs
+}
+
+func TestExecutor_FanOut_WritesOutputPerAgent(t *testing.T) {
+	processRunner := &mocks.MockProcessRunner{Output: "the answer"}
+	fs := mocks.NewMockFileSystem()
+	executor := engine.NewExecutor(&mocks.MockRunner{}, processRunner, engine.NewPlaceholderResolver(nil, ""), fs, nil)
+
+	results := executor.FanOut(fanOutRuns("claude", "gemini", "aichat"), "out")
+
+	assert.Equal(t, 3, len(results))
+	assert.Equal(t, 3, len(processRunner.CalledWith))
+	for i, name := range []string{"claude", "gemini", "aichat"} {
+		assert.Equal(t, name, results[i].Agent)
+		assert.Equal(t, "out/"+name+".md", results[i].Path)
+		assert.Equal(t, 10, results[i].Bytes)
+		assert.False(t, results[i].Failed(), "agent should succeed")
+		assert.Equal(t, "the answer", fs.Files["out/"+name+".md"])
+	}
+	// Fan-out always uses the headless command
+	assert.Contains(t, processRunner.CalledWith[0].Command, "--print 'compare me'")
+}
+
+func TestExecutor_FanOut_NonZeroExit(t *testing.T) {
+	processRunner := &mocks.MockProcessRunner{Output: "partial", ExitCode: 3}
+	fs := mocks.NewMockFileSystem()
+	executor := engine.NewExecutor(&mocks.MockRunner{}, processRunner, engine.NewPlaceholderResolver(nil, ""), fs, nil)
+
+	results := executor.FanOut(fanOutRuns("claude"), "out")
+
+	assert.Equal(t, 3, results[0].ExitCode)
+	assert.NoError(t, results[0].Err)
+	assert.True(t, results[0].Failed(), "non-zero exit should fail")
+	// Output is kept for inspection
+	assert.Equal(t, "partial", fs.Files["out/claude.md"])
+}
+
+func TestExecutor_FanOut_OutputDirFailsFast(t *testing.T) {
+	processRunner := &mocks.MockProcessRunner{Output: "the answer"}
+	fs := mocks.NewMockFileSystem()
+	fs.MkdirErr = errors.New("permission denied")
+	executor := engine.NewExecutor(&mocks.MockRunner{}, processRunner, engine.NewPlaceholderResolver(nil, ""), fs, nil)
+
+	results := executor.FanOut(fanOutRuns("claude", "gemini"), "out")
+
+	// No agent is run when its output has nowhere to go
+	assert.Equal(t, 0, len(processRunner.CalledWith))
+	assert.Equal(t, 2, len(results))
+	for _, result := range results {
+		assert.True(t, result.Failed(), "agent should fail")
+		assert.Contains(t, result.Err.Error(), "failed to create output directory: permission denied")
+	}
+}
@@ -0,0 +1,203 @@
+package engine
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/grantcarthew/start/internal/domain"
+)
+
+// Defaults for glob and directory file sources
+const (
+	DefaultFileHeader = "--- {path} ---" // {path} is the file path relative to the working directory
+	DefaultMaxFiles   = 100
+	DefaultMaxBytes   = 512 * 1024
+)
+
+// IsGlobPattern reports whether a file field contains glob syntax
+func IsGlobPattern(file string) bool {
+	return strings.ContainsAny(file, "*?[")
+}
+
+// FileMatcher expands glob and directory file fields to the files they name
+type FileMatcher struct {
+	fs      domain.FileSystem
+	workDir string
+}
+
+// NewFileMatcher creates a file matcher resolving relative paths against workDir
+func NewFileMatcher(fs domain.FileSystem, workDir string) *FileMatcher {
+	return &FileMatcher{fs: fs, workDir: workDir}
+}
+
+// IsMulti reports whether file is a glob pattern or a directory rather than a single file
+func (m *FileMatcher) IsMulti(file string) bool {
+	return IsGlobPattern(file) || m.fs.IsDir(resolveWorkPath(m.workDir, file))
+}
+
+// Match returns the files matched by a glob pattern or directory in sorted
+// order, leaving out files excluded by .gitignore
+func (m *FileMatcher) Match(file string) ([]string, error) {
+	base, pattern := splitGlob(resolveWorkPath(m.workDir, file))
+	if !m.fs.IsDir(base) {
+		return nil, nil
+	}
+
+	files, err := m.fs.WalkFiles(base)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", base, err)
+	}
+
+	ignores := m.gitignores(base, files)
+
+	var matches []string
+	for _, f := range files {
+		rel, ok := relativeTo(base, f)
+		if !ok || (pattern != "" && !matchGlob(pattern, rel)) {
+			continue
+		}
+		if ignores.ignored(f) {
+			continue
+		}
+		matches = append(matches, f)
+	}
+	sort.Strings(matches)
+
+	return matches, nil
+}
+
+// DisplayPath returns path relative to the working directory when inside it
+func (m *FileMatcher) DisplayPath(path string) string {
+	if rel, ok := relativeTo(m.workDir, path); ok {
+		return filepath.FromSlash(rel)
+	}
+	return path
+}
+
+// gitignores loads .gitignore files from the working directory down to base
+// and from within the walked files, shallowest first
+func (m *FileMatcher) gitignores(base string, files []string) ignoreSet {
+	var dirs []string
+	if _, ok := relativeTo(m.workDir, base); ok {
+		for dir := base; dir != m.workDir; dir = filepath.Dir(dir) {
+			dirs = append(dirs, dir)
+		}
+		dirs = append(dirs, m.workDir)
+	} else {
+		dirs = append(dirs, base)
+	}
+	for _, f := range files {
+		if filepath.Base(f) == ".gitignore" && filepath.Dir(f) != base {
+			dirs = append(dirs, filepath.Dir(f))
+		}
+	}
+	sort.Slice(dirs, func(i, j int) bool { return len(dirs[i]) < len(dirs[j]) })
+
+	var set ignoreSet
+	for _, dir := range dirs {
+		content, err := m.fs.ReadFile(filepath.Join(dir, ".gitignore"))
+		if err != nil {
+			continue
+		}
+		set = append(set, parseGitignore(dir, string(content)))
+	}
+	return set
+}
+
+// splitGlob splits a path into the directory before any glob syntax and the
+// slash-separated pattern after it (empty for a plain directory)
+func splitGlob(path string) (string, string) {
+	if !IsGlobPattern(path) {
+		return path, ""
+	}
+
+	parts := strings.Split(filepath.ToSlash(path), "/")
+	i := 0
+	for i < len(parts) && !IsGlobPattern(parts[i]) {
+		i++
+	}
+	base := filepath.FromSlash(strings.Join(parts[:i], "/"))
+	if base == "" {
+		base = string(filepath.Separator)
+	}
+	return base, strings.Join(parts[i:], "/")
+}
+
+// fileSet is the concatenated content of a glob or directory file source
+type fileSet struct {
+	Paths    []string // Files included, in order
+	Content  string
+	Warnings []string
+}
+
+// readFileSet reads matched files into one document, each file preceded by
+// the header, stopping at the max files and max bytes limits
+// Binary files are left out
+func (p *UTDProcessor) readFileSet(matches []string, header string, maxFiles, maxBytes int) fileSet {
+	if header == "" {
+		header = DefaultFileHeader
+	}
+	if maxFiles <= 0 {
+		maxFiles = DefaultMaxFiles
+	}
+	if maxBytes <= 0 {
+		maxBytes = DefaultMaxBytes
+	}
+
+	var set fileSet
+	var sections []string
+	total, binary := 0, 0
+	for i, path := range matches {
+		if len(set.Paths) == maxFiles {
+			set.Warnings = append(set.Warnings, fmt.Sprintf("Only the first %d of %d matched files included (max_files)", maxFiles, len(matches)))
+			break
+		}
+
+		contents, err := p.fs.ReadFile(path)
+		if err != nil {
+			set.Warnings = append(set.Warnings, fmt.Sprintf("Failed to read %s: %v", path, err))
+			continue
+		}
+		if bytes.IndexByte(contents[:min(len(contents), 8000)], 0) >= 0 {
+			binary++
+			continue
+		}
+		if total+len(contents) > maxBytes {
+			set.Warnings = append(set.Warnings, fmt.Sprintf("Stopped at %d bytes, %d matched files left out (max_bytes)", total, len(matches)-i))
+			break
+		}
+		total += len(contents)
+
+		set.Paths = append(set.Paths, path)
+		title := strings.ReplaceAll(header, "{path}", p.files.DisplayPath(path))
+		sections = append(sections, title+"\n"+strings.TrimRight(string(contents), "\n"))
+	}
+	if binary > 0 {
+		set.Warnings = append(set.Warnings, fmt.Sprintf("Skipped %d binary files", binary))
+	}
+
+	set.Content = strings.Join(sections, "\n\n")
+	return set
+}
+
+// resolveWorkPath resolves a file path (expanding ~ and making absolute against workDir)
+func resolveWorkPath(workDir, path string) string {
+	// Expand tilde
+	if strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err == nil {
+			path = filepath.Join(home, path[2:])
+		}
+	}
+
+	// Make absolute if relative
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(workDir, path)
+	}
+
+	return path
+}
@@ -0,0 +1,152 @@
+package engine
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ignoreRule is one pattern line from a .gitignore file
+type ignoreRule struct {
+	pattern  string
+	negate   bool // !pattern re-includes a path
+	dirOnly  bool // pattern/ only matches directories
+	anchored bool // Pattern contains a slash, matched from the .gitignore directory
+}
+
+// gitignore holds the rules of one .gitignore file, scoped to its directory
+type gitignore struct {
+	dir   string
+	rules []ignoreRule
+}
+
+// parseGitignore parses .gitignore content for the directory dir
+// Supports comments, negation, directory-only and anchored patterns, and **
+func parseGitignore(dir, content string) gitignore {
+	g := gitignore{dir: dir}
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimRight(line, " ")
+
+		var rule ignoreRule
+		if strings.HasPrefix(line, "!") {
+			rule.negate = true
+			line = line[1:]
+		}
+		line = strings.TrimPrefix(line, `\`) // Escaped leading # or !
+		if strings.HasSuffix(line, "/") {
+			rule.dirOnly = true
+			line = strings.TrimRight(line, "/")
+		}
+		if strings.Contains(line, "/") {
+			rule.anchored = true
+			line = strings.TrimPrefix(line, "/")
+		}
+		if line == "" {
+			continue
+		}
+
+		rule.pattern = line
+		g.rules = append(g.rules, rule)
+	}
+	return g
+}
+
+// match reports whether a rule matches rel (slash-separated, relative to the
+// .gitignore directory) and if so whether the last matching rule ignores it
+func (g gitignore) match(rel string, isDir bool) (matched, ignored bool) {
+	for _, rule := range g.rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		var ok bool
+		if rule.anchored {
+			ok = matchGlob(rule.pattern, rel)
+		} else {
+			ok = matchGlob(rule.pattern, path.Base(rel))
+		}
+		if ok {
+			matched, ignored = true, !rule.negate
+		}
+	}
+	return matched, ignored
+}
+
+// ignoreSet applies .gitignore files from several directories, shallowest first
+type ignoreSet []gitignore
+
+// ignored reports whether file is excluded by the set
+// A file is ignored when it or any of its parent directories is; deeper
+// .gitignore files take precedence over shallower ones
+func (s ignoreSet) ignored(file string) bool {
+	for _, g := range s {
+		rel, ok := relativeTo(g.dir, file)
+		if !ok {
+			continue
+		}
+		// Check each parent directory from the shallowest .gitignore down, then the file
+		parts := strings.Split(rel, "/")
+		for i := 1; i <= len(parts); i++ {
+			p := filepath.Join(g.dir, filepath.FromSlash(strings.Join(parts[:i], "/")))
+			if s.ignoredPath(p, i < len(parts)) {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+// ignoredPath evaluates a single path against every .gitignore that contains it
+func (s ignoreSet) ignoredPath(p string, isDir bool) bool {
+	result := false
+	for _, g := range s {
+		rel, ok := relativeTo(g.dir, p)
+		if !ok {
+			continue
+		}
+		if matched, ignored := g.match(rel, isDir); matched {
+			result = ignored
+		}
+	}
+	return result
+}
+
+// relativeTo returns p relative to dir with forward slashes, if p is inside dir
+func relativeTo(dir, p string) (string, bool) {
+	rel, err := filepath.Rel(dir, p)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// matchGlob matches a slash-separated path against a pattern where * and ?
+// stay within one segment and ** matches any number of segments
+func matchGlob(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, parts []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			for i := 0; i <= len(parts); i++ {
+				if matchSegments(pattern[1:], parts[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(parts) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(pattern[0], parts[0]); !ok {
+			return false
+		}
+		pattern, parts = pattern[1:], parts[1:]
+	}
+	return len(parts) == 0
+}
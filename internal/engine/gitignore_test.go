@@ -0,0 +1,53 @@
+package engine
+
+import "testing"
+
+func TestMatchGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		name    string
+		want    bool
+	}{
+		{"*.md", "README.md", true},
+		{"*.md", "docs/README.md", false},
+		{"**/*.md", "README.md", true},
+		{"**/*.md", "docs/adr/001.md", true},
+		{"docs/**", "docs/adr/001.md", true},
+		{"docs/**/001.md", "docs/001.md", true},
+		{"a/**/b", "a/x/y/b", true},
+		{"a/**/b", "a/x/y/c", false},
+		{"ch?.txt", "ch1.txt", true},
+		{"[ab].go", "c.go", false},
+	}
+
+	for _, tt := range tests {
+		if got := matchGlob(tt.pattern, tt.name); got != tt.want {
+			t.Errorf("matchGlob(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGitignore_Rules(t *testing.T) {
+	set := ignoreSet{parseGitignore("/repo", "# comment\n\n/dist\nnode_modules/\n*.tmp\n!important.tmp\ndocs/*.draft\n")}
+
+	tests := []struct {
+		file string
+		want bool
+	}{
+		{"/repo/dist/app.js", true},
+		{"/repo/src/dist/app.js", false}, // Anchored to the .gitignore directory
+		{"/repo/web/node_modules/x/index.js", true},
+		{"/repo/node_modules", false}, // Directory-only rule, this is a file
+		{"/repo/a/b.tmp", true},
+		{"/repo/a/important.tmp", false},
+		{"/repo/docs/plan.draft", true},
+		{"/repo/docs/sub/plan.draft", false},
+		{"/other/x.tmp", false},
+	}
+
+	for _, tt := range tests {
+		if got := set.ignored(tt.file); got != tt.want {
+			t.Errorf("ignored(%q) = %v, want %v", tt.file, got, tt.want)
+		}
+	}
+}
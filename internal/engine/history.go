@@ -0,0 +1,132 @@
+package engine
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/grantcarthew/start/internal/domain"
+)
+
+// NewHistoryEntry builds the history record for a prepared invocation
+func NewHistoryEntry(params ExecuteParams, prepared PreparedCommand) domain.HistoryEntry {
+	now := time.Now()
+	cwd, _ := os.Getwd()
+
+	entry := domain.HistoryEntry{
+		ID:           newHistoryID(now),
+		Timestamp:    now,
+		Cwd:          cwd,
+		Mode:         string(params.Mode),
+		Agent:        params.Agent.Name,
+		Model:        params.ModelName,
+		ModelID:      params.Model,
+		Role:         params.RoleName,
+		Contexts:     []domain.HistoryContext{},
+		Task:         params.TaskName,
+		Instructions: params.Instructions,
+		Params:       params.TaskParams,
+		Shell:        prepared.Shell,
+		Command:      prepared.Command,
+		Args:         prepared.Args,
+		Delivery:     prepared.Delivery,
+		PromptFile:   prepared.PromptFile,
+		Headless:     params.Headless,
+		Output:       params.Output,
+	}
+
+	sel := params.Selection
+	if len(sel.Include) > 0 || len(sel.Exclude) > 0 || len(sel.Tags) > 0 || sel.NoDefaults {
+		entry.ContextFlags = &domain.HistoryContextFlags{
+			Include:    sel.Include,
+			Exclude:    sel.Exclude,
+			Tags:       sel.Tags,
+			NoDefaults: sel.NoDefaults,
+		}
+	}
+
+	// Tasks are replayed from task name and instructions, not the expanded prompt
+	if params.TaskName == "" {
+		entry.UserPrompt = params.UserPrompt
+	}
+
+	// The command only references the prompt file, keep the prompt to recreate it
+	if prepared.PromptFile != "" {
+		entry.Prompt = prepared.Prompt
+	}
+
+	for _, ctx := range params.Contexts {
+		if ctx.Skipped {
+			continue
+		}
+		entry.Contexts = append(entry.Contexts, domain.HistoryContext{
+			Name: ctx.Name,
+			Hash: HashContent(ctx.Content),
+		})
+	}
+
+	return entry
+}
+
+// HashContent returns the content hash recorded for context documents
+func HashContent(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// FindHistoryEntry looks up an entry by exact ID, unique ID prefix, or "last"
+func FindHistoryEntry(entries []domain.HistoryEntry, id string) (domain.HistoryEntry, error) {
+	if len(entries) == 0 {
+		return domain.HistoryEntry{}, fmt.Errorf("history is empty")
+	}
+
+	if id == "last" {
+		return entries[len(entries)-1], nil
+	}
+
+	var matches []domain.HistoryEntry
+	for _, entry := range entries {
+		if entry.ID == id {
+			return entry, nil
+		}
+		if strings.HasPrefix(entry.ID, id) {
+			matches = append(matches, entry)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return domain.HistoryEntry{}, fmt.Errorf("history entry %q not found", id)
+	case 1:
+		return matches[0], nil
+	default:
+		ids := make([]string, len(matches))
+		for i, m := range matches {
+			ids[i] = m.ID
+		}
+		return domain.HistoryEntry{}, fmt.Errorf("history entry %q is ambiguous: %s", id, strings.Join(ids, ", "))
+	}
+}
+
+// PreparedFromHistory rebuilds the prepared command recorded in a history entry
+func PreparedFromHistory(entry domain.HistoryEntry) PreparedCommand {
+	return PreparedCommand{
+		Shell:      entry.Shell,
+		Command:    entry.Command,
+		Args:       entry.Args,
+		Prompt:     entry.Prompt,
+		Delivery:   entry.Delivery,
+		PromptFile: entry.PromptFile,
+	}
+}
+
+// newHistoryID returns a sortable ID: timestamp plus a short random suffix
+func newHistoryID(t time.Time) string {
+	suffix := make([]byte, 2)
+	rand.Read(suffix)
+	return t.Format("20060102-150405") + "-" + hex.EncodeToString(suffix)
+}
@@ -0,0 +1,190 @@
+package engine_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/start/internal/domain"
+	"github.com/grantcarthew/start/internal/engine"
+	"github.com/grantcarthew/start/test/assert"
+	"github.com/grantcarthew/start/test/mocks"
+)
+
+func TestExecutor_Execute_RecordsHistory(t *testing.T) {
+	mockRunner := &mocks.MockRunner{}
+	history := &mocks.MockHistoryStore{}
+	executor := engine.NewExecutor(mockRunner, &mocks.MockProcessRunner{}, engine.NewPlaceholderResolver(nil, ""), mocks.NewMockFileSystem(), history)
+
+	params := engine.ExecuteParams{
+		Agent: domain.Agent{
+			Name:    "claude",
+			Bin:     "claude",
+			Command: "{bin} --model {model} '{prompt}'",
+		},
+		Model:      "claude-sonnet-4",
+		ModelName:  "sonnet",
+		RoleName:   "go-expert",
+		UserPrompt: "task prompt",
+		Contexts: []engine.LoadedContext{
+			{Name: "readme", Content: "project readme"},
+			{Name: "missing", Skipped: true},
+		},
+		Shell:        "bash",
+		Mode:         engine.CommandTypeTask,
+		TaskName:     "code-review",
+		Instructions: "focus on errors",
+	}
+
+	err := executor.Execute(params)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(history.Entries))
+
+	entry := history.Entries[0]
+	assert.Equal(t, "task", entry.Mode)
+	assert.Equal(t, "claude", entry.Agent)
+	assert.Equal(t, "sonnet", entry.Model)
+	assert.Equal(t, "claude-sonnet-4", entry.ModelID)
+	assert.Equal(t, "go-expert", entry.Role)
+	assert.Equal(t, "code-review", entry.Task)
+	assert.Equal(t, "focus on errors", entry.Instructions)
+	// Tasks are replayed by name, the expanded task prompt is not kept
+	assert.Equal(t, "", entry.UserPrompt)
+	assert.Equal(t, mockRunner.CalledWith[0].Command, entry.Command)
+	// Skipped contexts are not recorded
+	assert.Equal(t, 1, len(entry.Contexts))
+	assert.Equal(t, "readme", entry.Contexts[0].Name)
+	assert.Equal(t, engine.HashContent("project readme"), entry.Contexts[0].Hash)
+	// No context flags were given
+	assert.True(t, entry.ContextFlags == nil, "expected no context flags")
+}
+
+func TestNewHistoryEntry_HeadlessAndContextFlags(t *testing.T) {
+	params := engine.ExecuteParams{
+		Agent:     domain.Agent{Name: "claude"},
+		Headless:  true,
+		Output:    "answer.md",
+		Selection: engine.ContextSelection{Include: []string{"extra"}, NoDefaults: true},
+	}
+
+	entry := engine.NewHistoryEntry(params, engine.PreparedCommand{Command: "claude -p 'x'"})
+	assert.True(t, entry.Headless, "expected headless entry")
+	assert.Equal(t, "answer.md", entry.Output)
+	assert.True(t, entry.ContextFlags != nil, "expected context flags")
+	assert.Equal(t, "extra", entry.ContextFlags.Include[0])
+	assert.True(t, entry.ContextFlags.NoDefaults, "expected --no-contexts recorded")
+}
+
+func TestExecutor_Replay(t *testing.T) {
+	mockRunner := &mocks.MockRunner{}
+	mockFS := mocks.NewMockFileSystem()
+	history := &mocks.MockHistoryStore{}
+	executor := engine.NewExecutor(mockRunner, &mocks.MockProcessRunner{}, engine.NewPlaceholderResolver(nil, ""), mockFS, history)
+
+	entry := domain.HistoryEntry{
+		ID:         "20250104-143000-ab12",
+		Agent:      "claude",
+		Args:       []string{"claude", "--file", "/tmp/start-prompt-1.md"},
+		Command:    "claude --file /tmp/start-prompt-1.md",
+		Delivery:   "file",
+		PromptFile: "/tmp/start-prompt-1.md",
+		Prompt:     "recorded prompt",
+	}
+
+	err := executor.Replay(entry)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(mockRunner.CalledWith))
+	assert.Equal(t, 3, len(mockRunner.CalledWith[0].Args))
+
+	// The replay is recorded as a new entry
+	assert.Equal(t, 1, len(history.Entries))
+	assert.NotEqual(t, entry.ID, history.Entries[0].ID)
+}
+
+func TestExecutor_ReplayCapture(t *testing.T) {
+	processRunner := &mocks.MockProcessRunner{Output: "the answer"}
+	history := &mocks.MockHistoryStore{}
+	executor := engine.NewExecutor(&mocks.MockRunner{}, processRunner, engine.NewPlaceholderResolver(nil, ""), mocks.NewMockFileSystem(), history)
+
+	entry := domain.HistoryEntry{
+		ID:         "20250104-143000-ab12",
+		Agent:      "claude",
+		Args:       []string{"claude", "--print"},
+		Command:    "claude --print",
+		Delivery:   "stdin",
+		PromptFile: "/tmp/start-prompt-1.md",
+		Prompt:     "recorded prompt",
+		Headless:   true,
+	}
+
+	var stdout strings.Builder
+	code, err := executor.ReplayCapture(entry, &stdout)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "the answer", stdout.String())
+	assert.Equal(t, "recorded prompt", processRunner.Stdin[0])
+	assert.Equal(t, 1, len(history.Entries))
+	assert.True(t, history.Entries[0].Headless, "expected headless replay entry")
+}
+
+func TestExecutor_NotRecordedWhenChecksFail(t *testing.T) {
+	mockRunner := &mocks.MockRunner{}
+	history := &mocks.MockHistoryStore{}
+	executor := engine.NewExecutor(mockRunner, &mocks.MockProcessRunner{}, engine.NewPlaceholderResolver(nil, ""), mocks.NewMockFileSystem(), history)
+
+	params := engine.ExecuteParams{
+		Agent: domain.Agent{
+			Name:    "claude",
+			Bin:     "claude",
+			Command: "{bin} '{prompt}'",
+		},
+		UserPrompt: strings.Repeat("x", 4*1024*1024),
+		Shell:      "bash",
+	}
+	assert.Error(t, executor.Execute(params))
+
+	entry := domain.HistoryEntry{
+		ID:      "20250104-143000-ab12",
+		Agent:   "claude",
+		Shell:   "bash",
+		Command: "claude '" + strings.Repeat("x", 4*1024*1024) + "'",
+	}
+	assert.Error(t, executor.Replay(entry))
+
+	// A command that cannot run is not recorded
+	assert.Equal(t, 0, len(history.Entries))
+	assert.Equal(t, 0, len(mockRunner.CalledWith))
+}
+
+func TestFindHistoryEntry(t *testing.T) {
+	entries := []domain.HistoryEntry{
+		{ID: "20250104-143000-ab12"},
+		{ID: "20250104-150000-cd34"},
+		{ID: "20250105-090000-ef56"},
+	}
+
+	tests := []struct {
+		name    string
+		id      string
+		want    string
+		wantErr string
+	}{
+		{name: "exact", id: "20250104-150000-cd34", want: "20250104-150000-cd34"},
+		{name: "unique prefix", id: "20250105", want: "20250105-090000-ef56"},
+		{name: "last", id: "last", want: "20250105-090000-ef56"},
+		{name: "ambiguous prefix", id: "20250104", wantErr: "ambiguous"},
+		{name: "not found", id: "2024", wantErr: "not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, err := engine.FindHistoryEntry(entries, tt.id)
+			if tt.wantErr != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, entry.ID)
+		})
+	}
+}
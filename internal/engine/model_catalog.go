@@ -0,0 +1,160 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/grantcarthew/start/internal/domain"
+)
+
+// ModelCatalog discovers the model IDs an agent accepts through its
+// models_command or models_url, and caches them for --model checks
+type ModelCatalog struct {
+	commandRunner domain.CommandRunner
+	fetcher       domain.URLFetcher
+	cache         domain.ModelCache
+}
+
+// ModelList is a discovered set of model IDs
+type ModelList struct {
+	IDs      []string
+	Source   string    // models_command or models_url the IDs came from
+	StoredAt time.Time // When the IDs were discovered
+	Warning  string    // Set when a stale copy of models_url was used
+}
+
+// NewModelCatalog creates a model catalog
+// fetcher may be nil to disable models_url, cache may be nil to disable caching
+func NewModelCatalog(commandRunner domain.CommandRunner, fetcher domain.URLFetcher, cache domain.ModelCache) *ModelCatalog {
+	return &ModelCatalog{
+		commandRunner: commandRunner,
+		fetcher:       fetcher,
+		cache:         cache,
+	}
+}
+
+// Known returns the model IDs cached by the last refresh of an agent
+func (c *ModelCatalog) Known(agentName string) (ModelList, bool) {
+	if c == nil || c.cache == nil {
+		return ModelList{}, false
+	}
+	ids, storedAt, ok := c.cache.Get(agentName)
+	if !ok {
+		return ModelList{}, false
+	}
+	return ModelList{IDs: ids, StoredAt: storedAt}, true
+}
+
+// Refresh discovers an agent's model IDs and caches them
+// models_command is preferred over models_url when both are set
+func (c *ModelCatalog) Refresh(agent domain.Agent, shell string, timeout int) (ModelList, error) {
+	var list ModelList
+	var text string
+
+	switch {
+	case agent.ModelsCommand != "":
+		output, err := c.commandRunner.Run(shell, agent.ModelsCommand, timeout)
+		if err != nil {
+			return list, fmt.Errorf("models_command failed: %w", err)
+		}
+		text = output
+		list.Source = agent.ModelsCommand
+	case agent.ModelsURL != "" && c.fetcher != nil:
+		fetched, err := c.fetcher.Fetch(context.Background(), agent.ModelsURL, time.Duration(timeout)*time.Second)
+		if err != nil {
+			return list, err
+		}
+		if fetched.Stale {
+			list.Warning = fmt.Sprintf("Using cached copy of %s from %s: %v", agent.ModelsURL, fetched.FetchedAt.Format("2006-01-02 15:04"), fetched.StaleErr)
+		}
+		text = fetched.Body
+		list.Source = agent.ModelsURL
+	default:
+		return list, fmt.Errorf("agent %q has no models_command or models_url", agent.Name)
+	}
+
+	ids, err := ParseModelIDs(text)
+	if err != nil {
+		return list, fmt.Errorf("failed to read models from %s: %w", list.Source, err)
+	}
+	list.IDs = ids
+	list.StoredAt = time.Now()
+
+	if c.cache != nil {
+		if err := c.cache.Set(agent.Name, ids); err != nil {
+			return list, err
+		}
+	}
+
+	return list, nil
+}
+
+// ParseModelIDs extracts model IDs from models_command output or a models_url body
+// JSON in the OpenAI ({"data": [{"id": ...}]}) or Gemini ({"models": [{"name": ...}]})
+// shape is understood, as is a JSON array of IDs; anything else is read as
+// one ID per line, first word only, skipping blank and # lines
+func ParseModelIDs(text string) ([]string, error) {
+	text = strings.TrimSpace(text)
+
+	var ids []string
+	switch {
+	case strings.HasPrefix(text, "{") || strings.HasPrefix(text, "["):
+		var doc any
+		if err := json.Unmarshal([]byte(text), &doc); err != nil {
+			return nil, fmt.Errorf("invalid JSON: %w", err)
+		}
+		ids = jsonModelIDs(doc)
+	case strings.HasPrefix(text, "<"):
+		return nil, fmt.Errorf("got HTML, expected JSON or one model ID per line")
+	default:
+		for _, line := range strings.Split(text, "\n") {
+			fields := strings.Fields(line)
+			if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+				continue
+			}
+			ids = append(ids, fields[0])
+		}
+	}
+
+	var unique []string
+	for _, id := range ids {
+		if !slices.Contains(unique, id) {
+			unique = append(unique, id)
+		}
+	}
+	if len(unique) == 0 {
+		return nil, fmt.Errorf("no model IDs found")
+	}
+	return unique, nil
+}
+
+// jsonModelIDs collects the IDs from a decoded JSON model list
+func jsonModelIDs(doc any) []string {
+	var ids []string
+	switch v := doc.(type) {
+	case map[string]any:
+		for _, key := range []string{"data", "models"} {
+			if list, ok := v[key]; ok {
+				return jsonModelIDs(list)
+			}
+		}
+	case []any:
+		for _, item := range v {
+			switch entry := item.(type) {
+			case string:
+				ids = append(ids, entry)
+			case map[string]any:
+				if id, ok := entry["id"].(string); ok {
+					ids = append(ids, id)
+				} else if name, ok := entry["name"].(string); ok {
+					ids = append(ids, strings.TrimPrefix(name, "models/"))
+				}
+			}
+		}
+	}
+	return ids
+}
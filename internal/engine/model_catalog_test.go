@@ -0,0 +1,131 @@
+package engine
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/start/internal/domain"
+	"github.com/grantcarthew/start/test/mocks"
+)
+
+func TestParseModelIDs(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"lines", "gpt-4o\n\n# comment\ngpt-4o-mini\n", []string{"gpt-4o", "gpt-4o-mini"}},
+		{"first word", "openai:gpt-4o  (128k)\nclaude:sonnet\n", []string{"openai:gpt-4o", "claude:sonnet"}},
+		{"duplicates", "a\nb\na\n", []string{"a", "b"}},
+		{"openai json", `{"object": "list", "data": [{"id": "gpt-4o"}, {"id": "o3"}]}`, []string{"gpt-4o", "o3"}},
+		{"gemini json", `{"models": [{"name": "models/gemini-2.5-pro"}]}`, []string{"gemini-2.5-pro"}},
+		{"json array", `["haiku", "sonnet"]`, []string{"haiku", "sonnet"}},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseModelIDs(tt.text)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: ParseModelIDs = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseModelIDs_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"html", "<!DOCTYPE html><html></html>", "got HTML"},
+		{"empty", "\n# nothing\n", "no model IDs found"},
+		{"json without ids", `{"object": "list"}`, "no model IDs found"},
+		{"broken json", `{"data": [`, "invalid JSON"},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseModelIDs(tt.text)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.want, err)
+		}
+	}
+}
+
+func TestModelCatalog_Refresh(t *testing.T) {
+	cmdRunner := mocks.NewMockCommandRunner()
+	cmdRunner.Outputs["aichat --list-models"] = "openai:gpt-4o\nclaude:sonnet\n"
+	fetcher := mocks.NewMockURLFetcher()
+	fetcher.SetBody("https://example.com/models", `{"data": [{"id": "gpt-4o"}]}`)
+	cache := mocks.NewMockModelCache()
+	catalog := NewModelCatalog(cmdRunner, fetcher, cache)
+
+	// models_command wins over models_url
+	list, err := catalog.Refresh(domain.Agent{
+		Name:          "aichat",
+		ModelsCommand: "aichat --list-models",
+		ModelsURL:     "https://example.com/models",
+	}, "bash", 30)
+	if err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if want := []string{"openai:gpt-4o", "claude:sonnet"}; !slices.Equal(list.IDs, want) {
+		t.Errorf("IDs = %v, want %v", list.IDs, want)
+	}
+	if list.Source != "aichat --list-models" {
+		t.Errorf("Source = %q, want the models_command", list.Source)
+	}
+	if len(fetcher.Fetched) != 0 {
+		t.Errorf("Expected models_url not to be fetched, got %v", fetcher.Fetched)
+	}
+
+	list, err = catalog.Refresh(domain.Agent{Name: "openai", ModelsURL: "https://example.com/models"}, "bash", 30)
+	if err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if !slices.Equal(list.IDs, []string{"gpt-4o"}) || list.Source != "https://example.com/models" {
+		t.Errorf("Unexpected models_url result: %+v", list)
+	}
+
+	// Both refreshes are cached per agent
+	known, ok := catalog.Known("aichat")
+	if !ok || len(known.IDs) != 2 {
+		t.Errorf("Expected cached aichat models, got %+v (%v)", known, ok)
+	}
+	if _, ok := catalog.Known("claude"); ok {
+		t.Error("Expected no cached models for claude")
+	}
+}
+
+func TestModelCatalog_RefreshErrors(t *testing.T) {
+	cmdRunner := mocks.NewMockCommandRunner()
+	cmdRunner.SetOutput("", errors.New("exit status 1"))
+	fetcher := mocks.NewMockURLFetcher()
+	fetcher.SetBody("https://example.com/docs", "<html><body>Models</body></html>")
+	cache := mocks.NewMockModelCache()
+	catalog := NewModelCatalog(cmdRunner, fetcher, cache)
+
+	tests := []struct {
+		name  string
+		agent domain.Agent
+		want  string
+	}{
+		{"no source", domain.Agent{Name: "claude"}, `agent "claude" has no models_command or models_url`},
+		{"command fails", domain.Agent{Name: "aichat", ModelsCommand: "aichat --list-models"}, "models_command failed: exit status 1"},
+		{"docs page", domain.Agent{Name: "gemini", ModelsURL: "https://example.com/docs"}, "failed to read models from https://example.com/docs: got HTML"},
+	}
+
+	for _, tt := range tests {
+		_, err := catalog.Refresh(tt.agent, "bash", 30)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected error containing %q, got %v", tt.name, tt.want, err)
+		}
+	}
+	if len(cache.Entries) != 0 {
+		t.Errorf("Expected nothing cached after failures, got %v", cache.Entries)
+	}
+}
@@ -0,0 +1,125 @@
+package engine
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// NameCandidate is a configured name that user input can resolve to
+type NameCandidate struct {
+	Name  string
+	Alias string // Optional second name (task alias, full model ID)
+}
+
+// AmbiguousNameError is returned when input is a prefix of more than one name
+type AmbiguousNameError struct {
+	Kind    string // agent, role, model or task
+	Input   string
+	Matches []string
+}
+
+func (e *AmbiguousNameError) Error() string {
+	return fmt.Sprintf("multiple %ss match %q: %s (use the exact name or a longer prefix)",
+		e.Kind, e.Input, strings.Join(e.Matches, ", "))
+}
+
+// UnknownNameError is returned when input matches no name
+// Suggestion is the closest name by edit distance, empty when nothing is close
+type UnknownNameError struct {
+	Kind       string
+	Input      string
+	Suggestion string
+}
+
+func (e *UnknownNameError) Error() string {
+	msg := fmt.Sprintf("%s %q not found in configuration", e.Kind, e.Input)
+	if e.Suggestion != "" {
+		msg += fmt.Sprintf(", did you mean %q?", e.Suggestion)
+	}
+	return msg
+}
+
+// ResolveName matches user input against configured names (DR-038)
+// Resolution order: exact name → alias → unique prefix of a name or alias
+// Returns *AmbiguousNameError when several names share the prefix and
+// *UnknownNameError, with the closest name as a suggestion, when none match
+func ResolveName(kind, input string, candidates []NameCandidate) (string, error) {
+	// 1. Exact name
+	for _, c := range candidates {
+		if c.Name == input {
+			return c.Name, nil
+		}
+	}
+
+	// 2. Exact alias
+	for _, c := range candidates {
+		if c.Alias != "" && c.Alias == input {
+			return c.Name, nil
+		}
+	}
+
+	// 3. Unique prefix of a name or alias
+	var matches []string
+	for _, c := range candidates {
+		if strings.HasPrefix(c.Name, input) || (c.Alias != "" && strings.HasPrefix(c.Alias, input)) {
+			if !slices.Contains(matches, c.Name) {
+				matches = append(matches, c.Name)
+			}
+		}
+	}
+	slices.Sort(matches)
+	switch {
+	case input != "" && len(matches) == 1:
+		return matches[0], nil
+	case input != "" && len(matches) > 1:
+		return "", &AmbiguousNameError{Kind: kind, Input: input, Matches: matches}
+	}
+
+	// 4. Fuzzy suggestion
+	return "", &UnknownNameError{Kind: kind, Input: input, Suggestion: closestName(input, candidates)}
+}
+
+// closestName returns the candidate name or alias nearest to input, or empty
+// when none is within a third of the input length (at least 2 edits)
+func closestName(input string, candidates []NameCandidate) string {
+	if input == "" {
+		return ""
+	}
+	limit := max(2, len(input)/3)
+	best, bestDist := "", limit+1
+	for _, c := range candidates {
+		for _, name := range []string{c.Name, c.Alias} {
+			if name == "" {
+				continue
+			}
+			d := editDistance(input, name)
+			if d < bestDist || (d == bestDist && name < best) {
+				best, bestDist = name, d
+			}
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
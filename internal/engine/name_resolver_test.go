@@ -0,0 +1,89 @@
+package engine
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestResolveName(t *testing.T) {
+	candidates := []NameCandidate{
+		{Name: "code-reviewer"},
+		{Name: "code-writer"},
+		{Name: "go-expert"},
+		{Name: "sonnet", Alias: "claude-sonnet-4-20250514"},
+		{Name: "review", Alias: "cr"},
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"exact name", "go-expert", "go-expert"},
+		{"exact name wins over prefix", "review", "review"},
+		{"alias", "cr", "review"},
+		{"full model ID", "claude-sonnet-4-20250514", "sonnet"},
+		{"unique name prefix", "go", "go-expert"},
+		{"unique alias prefix", "claude", "sonnet"},
+		{"longer prefix", "code-r", "code-reviewer"},
+	}
+
+	for _, tt := range tests {
+		got, err := ResolveName("role", tt.input, candidates)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: ResolveName(%q) = %q, want %q", tt.name, tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestResolveName_Ambiguous(t *testing.T) {
+	candidates := []NameCandidate{{Name: "code-writer"}, {Name: "code-reviewer"}, {Name: "go-expert"}}
+
+	_, err := ResolveName("role", "code", candidates)
+	var ambiguous *AmbiguousNameError
+	if !errors.As(err, &ambiguous) {
+		t.Fatalf("Expected AmbiguousNameError, got %v", err)
+	}
+	if want := []string{"code-reviewer", "code-writer"}; !slices.Equal(ambiguous.Matches, want) {
+		t.Errorf("Matches = %v, want %v", ambiguous.Matches, want)
+	}
+	if want := `multiple roles match "code": code-reviewer, code-writer (use the exact name or a longer prefix)`; err.Error() != want {
+		t.Errorf("Error = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestResolveName_Unknown(t *testing.T) {
+	candidates := []NameCandidate{{Name: "claude"}, {Name: "gemini"}, {Name: "review", Alias: "cr"}}
+
+	tests := []struct {
+		input      string
+		suggestion string
+		message    string
+	}{
+		{"cluade", "claude", `agent "cluade" not found in configuration, did you mean "claude"?`},
+		{"gemni", "gemini", `agent "gemni" not found in configuration, did you mean "gemini"?`},
+		{"revew", "review", `agent "revew" not found in configuration, did you mean "review"?`},
+		{"openai", "", `agent "openai" not found in configuration`},
+		{"", "", `agent "" not found in configuration`},
+	}
+
+	for _, tt := range tests {
+		_, err := ResolveName("agent", tt.input, candidates)
+		var unknown *UnknownNameError
+		if !errors.As(err, &unknown) {
+			t.Errorf("%q: expected UnknownNameError, got %v", tt.input, err)
+			continue
+		}
+		if unknown.Suggestion != tt.suggestion {
+			t.Errorf("%q: Suggestion = %q, want %q", tt.input, unknown.Suggestion, tt.suggestion)
+		}
+		if err.Error() != tt.message {
+			t.Errorf("%q: Error = %q, want %q", tt.input, err.Error(), tt.message)
+		}
+	}
+}
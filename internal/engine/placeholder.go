@@ -2,21 +2,44 @@ package engine
 
 import (
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/grantcarthew/start/internal/domain"
 )
 
 // PlaceholderResolver resolves placeholders in templates
-type PlaceholderResolver struct{}
+type PlaceholderResolver struct {
+	commandRunner domain.CommandRunner // Runs git for {git_branch}, {git_commit}, {project_root}
+	workDir       string               // Value of {cwd}
+
+	mu       sync.Mutex
+	gitCache map[string]gitResult
+}
 
 // NewPlaceholderResolver creates a new placeholder resolver
-func NewPlaceholderResolver() *PlaceholderResolver {
-	return &PlaceholderResolver{}
+// commandRunner may be nil, which makes git placeholders unavailable
+func NewPlaceholderResolver(commandRunner domain.CommandRunner, workDir string) *PlaceholderResolver {
+	return &PlaceholderResolver{
+		commandRunner: commandRunner,
+		workDir:       workDir,
+		gitCache:      make(map[string]gitResult),
+	}
+}
+
+// SetWorkDir changes the directory {cwd} and the git placeholders refer to
+func (r *PlaceholderResolver) SetWorkDir(workDir string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.workDir = workDir
+	r.gitCache = make(map[string]gitResult)
 }
 
 // Resolve replaces placeholders in a template with provided values
-// Supports: {bin}, {model}, {prompt}, {date}
+// Supports: {bin}, {model}, {prompt}, {date} and the built-in placeholders
 func (r *PlaceholderResolver) Resolve(template string, values map[string]string) string {
 	result := template
+	builtins, _ := r.Builtins(template)
 
 	// Replace all provided values
 	for key, value := range values {
@@ -24,12 +47,74 @@ func (r *PlaceholderResolver) Resolve(template string, values map[string]string)
 		result = strings.ReplaceAll(result, placeholder, value)
 	}
 
+	// Replace built-ins the caller did not provide
+	for key, value := range builtins {
+		if _, ok := values[key]; !ok {
+			result = strings.ReplaceAll(result, "{"+key+"}", value)
+		}
+	}
+
 	// Always replace {date} with current timestamp
 	result = strings.ReplaceAll(result, "{date}", r.getCurrentTimestamp())
 
 	return result
 }
 
+// ResolveCommand replaces placeholders in a command template that will be run by shell
+// For POSIX shells each value is escaped for its quote context (DR-044)
+// Returns *QuoteError if an unquoted placeholder holds an unsafe value
+func (r *PlaceholderResolver) ResolveCommand(template string, values map[string]string, shell string) (string, error) {
+	if !IsPOSIXShell(shell) {
+		// Programming languages: user is responsible for quoting
+		return r.Resolve(template, values), nil
+	}
+
+	all, _ := r.Builtins(template)
+	for key, value := range values {
+		all[key] = value
+	}
+	if _, ok := all["date"]; !ok {
+		all["date"] = r.getCurrentTimestamp()
+	}
+
+	return substituteQuoted(template, all, shell)
+}
+
+// ResolveArg replaces placeholders in a single argv element
+// No escaping is applied since the value is passed to the process directly.
+// Substitution is single-pass, so placeholders inside values are left as-is.
+func (r *PlaceholderResolver) ResolveArg(template string, values map[string]string) string {
+	var sb strings.Builder
+
+	for i := 0; i < len(template); {
+		if template[i] == '{' {
+			if end := strings.IndexByte(template[i:], '}'); end > 0 {
+				key := template[i+1 : i+end]
+				if value, ok := values[key]; ok {
+					sb.WriteString(value)
+					i += end + 1
+					continue
+				}
+				if builtinKeyPattern.MatchString(key) {
+					value, _ := r.builtin(key)
+					sb.WriteString(value)
+					i += end + 1
+					continue
+				}
+				if key == "date" {
+					sb.WriteString(r.getCurrentTimestamp())
+					i += end + 1
+					continue
+				}
+			}
+		}
+		sb.WriteByte(template[i])
+		i++
+	}
+
+	return sb.String()
+}
+
 // getCurrentTimestamp returns the current timestamp in ISO 8601 format with timezone
 func (r *PlaceholderResolver) getCurrentTimestamp() string {
 	return time.Now().Format(time.RFC3339)
@@ -9,7 +9,7 @@ import (
 )
 
 func TestPlaceholderResolver_Resolve_BasicPlaceholders(t *testing.T) {
-	resolver := engine.NewPlaceholderResolver()
+	resolver := engine.NewPlaceholderResolver(nil, "")
 
 	template := "Run {bin} with model {model} and prompt: {prompt}"
 	values := map[string]string{
@@ -25,7 +25,7 @@ func TestPlaceholderResolver_Resolve_BasicPlaceholders(t *testing.T) {
 }
 
 func TestPlaceholderResolver_Resolve_DatePlaceholder(t *testing.T) {
-	resolver := engine.NewPlaceholderResolver()
+	resolver := engine.NewPlaceholderResolver(nil, "")
 
 	template := "Current date: {date}"
 	values := map[string]string{}
@@ -41,7 +41,7 @@ func TestPlaceholderResolver_Resolve_DatePlaceholder(t *testing.T) {
 }
 
 func TestPlaceholderResolver_Resolve_MissingPlaceholder(t *testing.T) {
-	resolver := engine.NewPlaceholderResolver()
+	resolver := engine.NewPlaceholderResolver(nil, "")
 
 	template := "Model: {model}, Missing: {missing}"
 	values := map[string]string{
@@ -56,7 +56,7 @@ func TestPlaceholderResolver_Resolve_MissingPlaceholder(t *testing.T) {
 }
 
 func TestPlaceholderResolver_Resolve_EmptyTemplate(t *testing.T) {
-	resolver := engine.NewPlaceholderResolver()
+	resolver := engine.NewPlaceholderResolver(nil, "")
 
 	template := ""
 	values := map[string]string{
@@ -69,7 +69,7 @@ func TestPlaceholderResolver_Resolve_EmptyTemplate(t *testing.T) {
 }
 
 func TestPlaceholderResolver_Resolve_EmptyValues(t *testing.T) {
-	resolver := engine.NewPlaceholderResolver()
+	resolver := engine.NewPlaceholderResolver(nil, "")
 
 	template := "Date: {date}"
 	values := map[string]string{}
@@ -82,7 +82,7 @@ func TestPlaceholderResolver_Resolve_EmptyValues(t *testing.T) {
 }
 
 func TestPlaceholderResolver_Resolve_MultipleOccurrences(t *testing.T) {
-	resolver := engine.NewPlaceholderResolver()
+	resolver := engine.NewPlaceholderResolver(nil, "")
 
 	template := "{bin} {bin} {model}"
 	values := map[string]string{
@@ -0,0 +1,50 @@
+package engine
+
+import "fmt"
+
+// Prompt layout styles
+const (
+	PromptStylePlain    = "plain"    // Sections joined by blank lines
+	PromptStyleXML      = "xml"      // Each section wrapped in XML-style tags
+	PromptStyleMarkdown = "markdown" // Each section under a markdown heading
+)
+
+// Role placements
+const (
+	RolePlacementSystem = "system" // Role passed only through {role}/{role_file}
+	RolePlacementPrompt = "prompt" // Role also placed at the top of the prompt
+)
+
+// promptSection is one named block of the final prompt
+type promptSection struct {
+	kind    string // "role", "context" or "prompt"
+	name    string
+	content string
+}
+
+// formatSection delimits a section for the layout style
+func formatSection(style string, section promptSection) string {
+	switch style {
+	case PromptStyleXML:
+		if section.name == "" {
+			return fmt.Sprintf("<%s>\n%s\n</%s>", section.kind, section.content, section.kind)
+		}
+		return fmt.Sprintf("<%s name=\"%s\">\n%s\n</%s>", section.kind, section.name, section.content, section.kind)
+	case PromptStyleMarkdown:
+		return fmt.Sprintf("## %s\n\n%s", sectionHeading(section), section.content)
+	default:
+		return section.content
+	}
+}
+
+// sectionHeading returns the markdown heading for a section
+func sectionHeading(section promptSection) string {
+	switch section.kind {
+	case "role":
+		return "Role: " + section.name
+	case "prompt":
+		return "Prompt"
+	default:
+		return section.name
+	}
+}
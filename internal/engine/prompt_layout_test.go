@@ -0,0 +1,85 @@
+package engine_test
+
+import (
+	"testing"
+
+	"github.com/grantcarthew/start/internal/domain"
+	"github.com/grantcarthew/start/internal/engine"
+	"github.com/grantcarthew/start/test/assert"
+	"github.com/grantcarthew/start/test/mocks"
+)
+
+// preparePrompt returns the final prompt built for the layout
+func preparePrompt(t *testing.T, layout domain.PromptLayout) string {
+	t.Helper()
+
+	executor := engine.NewExecutor(&mocks.MockRunner{}, &mocks.MockProcessRunner{}, engine.NewPlaceholderResolver(nil, ""), mocks.NewMockFileSystem(), nil)
+
+	prepared, err := executor.Prepare(engine.ExecuteParams{
+		Agent: domain.Agent{
+			Name:    "smith",
+			Bin:     "smith",
+			Command: "{bin} --model {model} '{prompt}'",
+		},
+		Model:       "test-model",
+		UserPrompt:  "Review this",
+		RoleName:    "reviewer",
+		RoleContent: "You review code.",
+		Contexts: []engine.LoadedContext{
+			{Name: "environment", Content: "Linux"},
+			{Name: "skipped", Skipped: true},
+			{Name: "project", Content: "A CLI"},
+		},
+		Shell:  "bash",
+		Layout: layout,
+	})
+	assert.NoError(t, err)
+
+	return prepared.Prompt
+}
+
+func TestPromptLayout_Plain(t *testing.T) {
+	prompt := preparePrompt(t, domain.PromptLayout{})
+
+	assert.Equal(t, "Linux\n\nA CLI\n\nReview this", prompt)
+}
+
+func TestPromptLayout_XML(t *testing.T) {
+	prompt := preparePrompt(t, domain.PromptLayout{Style: "xml"})
+
+	expected := "<context name=\"environment\">\nLinux\n</context>\n\n" +
+		"<context name=\"project\">\nA CLI\n</context>\n\n" +
+		"<prompt>\nReview this\n</prompt>"
+	assert.Equal(t, expected, prompt)
+}
+
+func TestPromptLayout_MarkdownWithRoleHeaderFooter(t *testing.T) {
+	prompt := preparePrompt(t, domain.PromptLayout{
+		Style:         "markdown",
+		Header:        "Project briefing",
+		Footer:        "Answer concisely.",
+		RolePlacement: "prompt",
+	})
+
+	expected := "Project briefing\n\n" +
+		"## Role: reviewer\n\nYou review code.\n\n" +
+		"## environment\n\nLinux\n\n" +
+		"## project\n\nA CLI\n\n" +
+		"## Prompt\n\nReview this\n\n" +
+		"Answer concisely."
+	assert.Equal(t, expected, prompt)
+}
+
+func TestPromptLayout_PromptOnlyIsNotWrapped(t *testing.T) {
+	executor := engine.NewExecutor(&mocks.MockRunner{}, &mocks.MockProcessRunner{}, engine.NewPlaceholderResolver(nil, ""), mocks.NewMockFileSystem(), nil)
+
+	prepared, err := executor.Prepare(engine.ExecuteParams{
+		Agent:      domain.Agent{Name: "smith", Bin: "smith", Command: "{bin} '{prompt}'"},
+		UserPrompt: "hello",
+		Shell:      "bash",
+		Layout:     domain.PromptLayout{Style: "xml"},
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", prepared.Prompt)
+}
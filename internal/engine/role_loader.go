@@ -2,6 +2,8 @@ package engine
 
 import (
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/grantcarthew/start/internal/domain"
 )
@@ -26,45 +28,81 @@ type LoadedRole struct {
 	Content  string   // Resolved role content (for {role} placeholder)
 	FilePath string   // Path for {role_file} placeholder (original or temp file)
 	IsTemp   bool     // True if FilePath points to a temporary file
+	Cached   bool     // True if command output came from the cache
+	Chain    []string // Roles the content was built from, parents first, ending with Name
 	Warnings []string // Warnings during processing
 }
 
+// DefaultExtendsSeparator joins the content of extended roles
+const DefaultExtendsSeparator = "\n\n"
+
+// Applies reports whether the role's when predicates hold, with the reason when not
+func (l *RoleLoader) Applies(role domain.Role, defaultShell string, defaultTimeout int) (bool, string) {
+	return l.utdProcessor.CheckWhen(role.When, defaultShell, defaultTimeout)
+}
+
 // LoadRole loads and processes a role through UTD
+// Roles named in extends are loaded first, recursively, and their content is
+// joined ahead of the role's own with extends_separator; roles supplies them
+// and may be nil for a role without extends
 func (l *RoleLoader) LoadRole(
 	role domain.Role,
+	roles map[string]domain.Role,
 	defaultShell string,
 	defaultTimeout int,
+	variables map[string]string,
 ) (LoadedRole, error) {
 	result := LoadedRole{
 		Name:     role.Name,
 		Warnings: []string{},
 	}
 
-	// Process through UTD
-	utdInput := UTDInput{
-		File:           role.File,
-		Command:        role.Command,
-		Prompt:         role.Prompt,
-		Shell:          role.Shell,
-		CommandTimeout: role.CommandTimeout,
+	chain, err := RoleChain(role, roles)
+	if err != nil {
+		return result, err
 	}
 
-	utdResult := l.utdProcessor.Process(utdInput, defaultShell, defaultTimeout)
+	var parts []string
+	for _, r := range chain {
+		result.Chain = append(result.Chain, r.Name)
 
-	// Check if processing was skipped
-	if utdResult.Skipped {
-		return result, fmt.Errorf("role processing failed: %v", utdResult.Warnings)
+		// A role made only of its parents has no content of its own
+		if len(r.Extends) > 0 && r.File == "" && r.Command == "" && r.URL == "" && r.Prompt == "" {
+			continue
+		}
+
+		utdResult := l.utdProcessor.Process(roleUTDInput(r, variables), defaultShell, defaultTimeout)
+
+		// Check if processing was skipped
+		if utdResult.Skipped {
+			if r.Name != role.Name {
+				return result, fmt.Errorf("role processing failed for parent %q: %v", r.Name, utdResult.Warnings)
+			}
+			return result, fmt.Errorf("role processing failed: %v", utdResult.Warnings)
+		}
+
+		for _, w := range utdResult.Warnings {
+			if r.Name != role.Name {
+				w = fmt.Sprintf("%s (from parent role %s)", w, r.Name)
+			}
+			result.Warnings = append(result.Warnings, w)
+		}
+		parts = append(parts, utdResult.Content)
+		result.Cached = result.Cached || utdResult.Cached
+		result.FilePath = utdResult.FilePath
 	}
 
-	result.Content = utdResult.Content
-	result.Warnings = utdResult.Warnings
+	separator := role.ExtendsSeparator
+	if separator == "" {
+		separator = DefaultExtendsSeparator
+	}
+	result.Content = strings.Join(parts, separator)
 
 	// Determine file path for {role_file} placeholder
-	// Simple role (file only) -> use original file path
-	// Complex role (UTD) -> create temp file
-	if role.File != "" && role.Command == "" && role.Prompt == "" {
+	// Simple role (single file only) -> use original file path
+	// Complex role (UTD, glob, directory or extends) -> create temp file
+	if len(chain) == 1 && role.File != "" && role.Command == "" && role.URL == "" && role.Prompt == "" && !l.utdProcessor.files.IsMulti(result.FilePath) {
 		// Simple role - use original file path
-		result.FilePath = utdResult.FilePath
 		result.IsTemp = false
 	} else {
 		// Complex role or non-file role - create temp file
@@ -86,6 +124,61 @@ func (l *RoleLoader) LoadRole(
 	return result, nil
 }
 
+// roleUTDInput returns the UTD fields of a role
+func roleUTDInput(role domain.Role, variables map[string]string) UTDInput {
+	return UTDInput{
+		File:           role.File,
+		Command:        role.Command,
+		URL:            role.URL,
+		Prompt:         role.Prompt,
+		Shell:          role.Shell,
+		CommandTimeout: role.CommandTimeout,
+		CacheTTL:       role.CacheTTL,
+		CacheKeyFiles:  role.CacheKeyFiles,
+		FileHeader:     role.FileHeader,
+		MaxFiles:       role.MaxFiles,
+		MaxBytes:       role.MaxBytes,
+		Variables:      variables,
+	}
+}
+
+// RoleChain returns the roles a role is built from: parents from extends in
+// order, each resolved recursively and listed once, then the role itself
+// Unknown parents and inheritance cycles are errors
+func RoleChain(role domain.Role, roles map[string]domain.Role) ([]domain.Role, error) {
+	var chain []domain.Role
+	seen := make(map[string]bool)
+
+	var visit func(r domain.Role, path []string) error
+	visit = func(r domain.Role, path []string) error {
+		path = append(path, r.Name)
+		for _, parentName := range r.Extends {
+			if slices.Contains(path, parentName) {
+				return fmt.Errorf("role inheritance cycle: %s", strings.Join(append(path, parentName), " → "))
+			}
+			if seen[parentName] {
+				continue
+			}
+			parent, ok := roles[parentName]
+			if !ok {
+				return fmt.Errorf("role %q extends %q, which is not in configuration", r.Name, parentName)
+			}
+			parent.Name = parentName
+			if err := visit(parent, path); err != nil {
+				return err
+			}
+		}
+		seen[r.Name] = true
+		chain = append(chain, r)
+		return nil
+	}
+
+	if err := visit(role, nil); err != nil {
+		return nil, err
+	}
+	return chain, nil
+}
+
 // CleanupRole removes temporary files if needed
 func (l *RoleLoader) CleanupRole(role LoadedRole) error {
 	if role.IsTemp && role.FilePath != "" {
@@ -57,10 +57,18 @@ func (m *mockFileSystem) Exists(path string) bool {
 	return ok
 }
 
+func (m *mockFileSystem) IsDir(path string) bool {
+	return false
+}
+
 func (m *mockFileSystem) Glob(pattern string) ([]string, error) {
 	return nil, nil
 }
 
+func (m *mockFileSystem) WalkFiles(root string) ([]string, error) {
+	return nil, nil
+}
+
 func (m *mockFileSystem) MkdirAll(path string, perm os.FileMode) error {
 	return nil
 }
@@ -89,7 +97,7 @@ func TestRoleLoader_LoadRole_SimpleFileRole(t *testing.T) {
 	fs.files["/role.md"] = "Role content"
 
 	cmdRunner := &mockCommandRunner{}
-	utdProcessor := NewUTDProcessor(fs, cmdRunner, "/workdir")
+	utdProcessor := NewUTDProcessor(fs, cmdRunner, "/workdir", nil, nil)
 
 	loader := NewRoleLoader(utdProcessor, fs)
 
@@ -98,7 +106,7 @@ func TestRoleLoader_LoadRole_SimpleFileRole(t *testing.T) {
 		File: "/role.md",
 	}
 
-	result, err := loader.LoadRole(role, "bash", 30)
+	result, err := loader.LoadRole(role, nil, "bash", 30, nil)
 
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
@@ -126,7 +134,7 @@ func TestRoleLoader_LoadRole_ComplexRole(t *testing.T) {
 	fs := newMockFileSystem()
 
 	cmdRunner := &mockCommandRunner{}
-	utdProcessor := NewUTDProcessor(fs, cmdRunner, "/workdir")
+	utdProcessor := NewUTDProcessor(fs, cmdRunner, "/workdir", nil, nil)
 
 	loader := NewRoleLoader(utdProcessor, fs)
 
@@ -135,7 +143,7 @@ func TestRoleLoader_LoadRole_ComplexRole(t *testing.T) {
 		Prompt: "Some prompt text",
 	}
 
-	result, err := loader.LoadRole(role, "bash", 30)
+	result, err := loader.LoadRole(role, nil, "bash", 30, nil)
 
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
@@ -170,7 +178,7 @@ func TestRoleLoader_LoadRole_FileWithCommand(t *testing.T) {
 	fs.files["/original.md"] = "File content"
 
 	cmdRunner := &mockCommandRunner{output: "\nextra"}
-	utdProcessor := NewUTDProcessor(fs, cmdRunner, "/workdir")
+	utdProcessor := NewUTDProcessor(fs, cmdRunner, "/workdir", nil, nil)
 
 	loader := NewRoleLoader(utdProcessor, fs)
 
@@ -180,7 +188,7 @@ func TestRoleLoader_LoadRole_FileWithCommand(t *testing.T) {
 		Command: "echo 'extra'",
 	}
 
-	result, err := loader.LoadRole(role, "bash", 30)
+	result, err := loader.LoadRole(role, nil, "bash", 30, nil)
 
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
@@ -200,7 +208,7 @@ func TestRoleLoader_LoadRole_UTDProcessingFailed(t *testing.T) {
 	// Don't add file to fs - will cause UTD to fail
 
 	cmdRunner := &mockCommandRunner{}
-	utdProcessor := NewUTDProcessor(fs, cmdRunner, "/workdir")
+	utdProcessor := NewUTDProcessor(fs, cmdRunner, "/workdir", nil, nil)
 
 	loader := NewRoleLoader(utdProcessor, fs)
 
@@ -211,7 +219,7 @@ func TestRoleLoader_LoadRole_UTDProcessingFailed(t *testing.T) {
 		Prompt: "Use file: {file_contents}",
 	}
 
-	_, err := loader.LoadRole(role, "bash", 30)
+	_, err := loader.LoadRole(role, nil, "bash", 30, nil)
 
 	if err == nil {
 		t.Error("Expected error when UTD processing fails")
@@ -228,7 +236,7 @@ func TestRoleLoader_LoadRole_TempFileCreationFails(t *testing.T) {
 	fs.tempError = fmt.Errorf("cannot create temp file")
 
 	cmdRunner := &mockCommandRunner{}
-	utdProcessor := NewUTDProcessor(fs, cmdRunner, "/workdir")
+	utdProcessor := NewUTDProcessor(fs, cmdRunner, "/workdir", nil, nil)
 
 	loader := NewRoleLoader(utdProcessor, fs)
 
@@ -237,7 +245,7 @@ func TestRoleLoader_LoadRole_TempFileCreationFails(t *testing.T) {
 		Prompt: "Some prompt",
 	}
 
-	_, err := loader.LoadRole(role, "bash", 30)
+	_, err := loader.LoadRole(role, nil, "bash", 30, nil)
 
 	if err == nil {
 		t.Error("Expected error when temp file creation fails")
@@ -253,7 +261,7 @@ func TestRoleLoader_LoadRole_TempFileWriteFails(t *testing.T) {
 	fs.writeError = fmt.Errorf("write permission denied")
 
 	cmdRunner := &mockCommandRunner{}
-	utdProcessor := NewUTDProcessor(fs, cmdRunner, "/workdir")
+	utdProcessor := NewUTDProcessor(fs, cmdRunner, "/workdir", nil, nil)
 
 	loader := NewRoleLoader(utdProcessor, fs)
 
@@ -262,7 +270,7 @@ func TestRoleLoader_LoadRole_TempFileWriteFails(t *testing.T) {
 		Prompt: "Some prompt",
 	}
 
-	_, err := loader.LoadRole(role, "bash", 30)
+	_, err := loader.LoadRole(role, nil, "bash", 30, nil)
 
 	if err == nil {
 		t.Error("Expected error when temp file write fails")
@@ -283,7 +291,7 @@ func TestRoleLoader_LoadRole_UTDPassesDefaultsCorrectly(t *testing.T) {
 	fs.files["/role.md"] = "Role content"
 
 	cmdRunner := &mockCommandRunner{}
-	utdProcessor := NewUTDProcessor(fs, cmdRunner, "/workdir")
+	utdProcessor := NewUTDProcessor(fs, cmdRunner, "/workdir", nil, nil)
 
 	loader := NewRoleLoader(utdProcessor, fs)
 
@@ -292,7 +300,7 @@ func TestRoleLoader_LoadRole_UTDPassesDefaultsCorrectly(t *testing.T) {
 		File: "/role.md",
 	}
 
-	result, err := loader.LoadRole(role, "bash", 30)
+	result, err := loader.LoadRole(role, nil, "bash", 30, nil)
 
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
@@ -304,6 +312,68 @@ func TestRoleLoader_LoadRole_UTDPassesDefaultsCorrectly(t *testing.T) {
 	}
 }
 
+func TestRoleLoader_LoadRole_Extends(t *testing.T) {
+	fs := newMockFileSystem()
+	utdProcessor := NewUTDProcessor(fs, &mockCommandRunner{}, "/workdir", nil, nil)
+	loader := NewRoleLoader(utdProcessor, fs)
+
+	// Diamond: go-expert and security both extend base, which is listed once
+	roles := map[string]domain.Role{
+		"base":      {Prompt: "Base"},
+		"go-expert": {Prompt: "Go", Extends: []string{"base"}},
+		"security":  {Prompt: "Security", Extends: []string{"base"}},
+		"reviewer":  {Extends: []string{"go-expert", "security"}},
+	}
+	role := roles["reviewer"]
+	role.Name = "reviewer"
+
+	result, err := loader.LoadRole(role, roles, "bash", 30, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if result.Content != "Base\n\nGo\n\nSecurity" {
+		t.Errorf("Expected flattened content, got %q", result.Content)
+	}
+	wantChain := []string{"base", "go-expert", "security", "reviewer"}
+	if fmt.Sprint(result.Chain) != fmt.Sprint(wantChain) {
+		t.Errorf("Expected chain %v, got %v", wantChain, result.Chain)
+	}
+	if !result.IsTemp {
+		t.Error("Expected IsTemp to be true for an extended role")
+	}
+
+	// Custom separator
+	role = domain.Role{Name: "child", Prompt: "Child", Extends: []string{"base"}, ExtendsSeparator: "\n---\n"}
+	result, err = loader.LoadRole(role, roles, "bash", 30, nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result.Content != "Base\n---\nChild" {
+		t.Errorf("Expected custom separator, got %q", result.Content)
+	}
+}
+
+func TestRoleLoader_LoadRole_ExtendsErrors(t *testing.T) {
+	fs := newMockFileSystem()
+	utdProcessor := NewUTDProcessor(fs, &mockCommandRunner{}, "/workdir", nil, nil)
+	loader := NewRoleLoader(utdProcessor, fs)
+
+	roles := map[string]domain.Role{
+		"a": {Prompt: "A", Extends: []string{"b"}},
+		"b": {Prompt: "B", Extends: []string{"a"}},
+	}
+	_, err := loader.LoadRole(domain.Role{Name: "a", Prompt: "A", Extends: []string{"b"}}, roles, "bash", 30, nil)
+	if err == nil || err.Error() != "role inheritance cycle: a → b → a" {
+		t.Errorf("Expected cycle error, got: %v", err)
+	}
+
+	_, err = loader.LoadRole(domain.Role{Name: "c", Prompt: "C", Extends: []string{"missing"}}, roles, "bash", 30, nil)
+	if err == nil || err.Error() != `role "c" extends "missing", which is not in configuration` {
+		t.Errorf("Expected unknown parent error, got: %v", err)
+	}
+}
+
 func TestRoleLoader_CleanupRole_TempFile(t *testing.T) {
 	fs := newMockFileSystem()
 	loader := NewRoleLoader(nil, fs)
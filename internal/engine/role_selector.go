@@ -16,16 +16,17 @@ func NewRoleSelector() *RoleSelector {
 
 // SelectionContext contains the context for role selection
 type SelectionContext struct {
-	RoleFlag    string // --role flag value
-	TaskRole    string // role field from task (if executing task)
-	DefaultRole string // default_role from settings
+	RoleFlag    string   // --role flag value
+	TaskRole    string   // role field from task (if executing task)
+	DefaultRole string   // default_role from settings
+	RoleOrder   []string // Role names in definition order, for the first-role fallback
 }
 
 // Select chooses a role based on precedence rules:
 // 1. CLI --role flag (highest priority)
 // 2. Task role field (if executing a task)
 // 3. default_role setting
-// 4. First role in config (TOML order)
+// 4. First role in config (definition order, explicit order first)
 func (s *RoleSelector) Select(ctx SelectionContext, roles map[string]domain.Role) (domain.Role, error) {
 	if len(roles) == 0 {
 		return domain.Role{}, fmt.Errorf("no roles defined in configuration")
@@ -44,10 +45,15 @@ func (s *RoleSelector) Select(ctx SelectionContext, roles map[string]domain.Role
 		selectedName = ctx.DefaultRole
 	} else {
 		// Precedence 4: First role in config
-		// Note: Go map iteration is not ordered, but the config loader
-		// should preserve TOML order in the map or provide a separate
-		// ordering mechanism. For now, we'll require default_role.
-		return domain.Role{}, fmt.Errorf("no role specified: use --role flag or set default_role in settings")
+		for _, name := range ctx.RoleOrder {
+			if _, ok := roles[name]; ok {
+				selectedName = name
+				break
+			}
+		}
+		if selectedName == "" {
+			return domain.Role{}, fmt.Errorf("no role specified: use --role flag or set default_role in settings")
+		}
 	}
 
 	// Get the selected role
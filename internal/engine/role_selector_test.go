@@ -53,7 +53,15 @@ func TestRoleSelector_Select_Precedence(t *testing.T) {
 			expectError:  false,
 		},
 		{
-			name: "error when no role specified",
+			name: "precedence 4: first defined role when nothing else is set",
+			ctx: SelectionContext{
+				RoleOrder: []string{"removed-role", "task-role", "flag-role"},
+			},
+			expectedRole: "task-role",
+			expectError:  false,
+		},
+		{
+			name: "error when no role specified and no role order",
 			ctx: SelectionContext{
 				RoleFlag:    "",
 				TaskRole:    "",
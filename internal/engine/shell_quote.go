@@ -0,0 +1,272 @@
+package engine
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// QuoteContext represents the shell quoting state around a placeholder
+type QuoteContext string
+
+const (
+	QuoteUnquoted QuoteContext = "unquoted"
+	QuoteSingle   QuoteContext = "single-quoted"
+	QuoteDouble   QuoteContext = "double-quoted"
+)
+
+// IsPOSIXShell reports whether the shell follows POSIX quoting rules (DR-044)
+// fish is included, its single quotes are escaped separately
+// Programming languages (python, node, ruby, etc.) return false and get no escaping
+func IsPOSIXShell(shell string) bool {
+	switch filepath.Base(shell) {
+	case "bash", "sh", "zsh", "fish", "dash", "ksh":
+		return true
+	default:
+		return false
+	}
+}
+
+// isFish reports whether the shell is fish, whose single quotes differ from POSIX
+func isFish(shell string) bool {
+	return filepath.Base(shell) == "fish"
+}
+
+// EscapeSingleQuote escapes a value for use inside single quotes
+// Each ' closes the string, adds an escaped quote (\') and reopens it
+func EscapeSingleQuote(value string) string {
+	return strings.ReplaceAll(value, "'", `'\''`)
+}
+
+// EscapeFishSingleQuote escapes a value for use inside fish single quotes
+// fish reads \' and \\ as escapes there, so both are backslash-escaped
+func EscapeFishSingleQuote(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return strings.ReplaceAll(value, "'", `\'`)
+}
+
+// EscapeDoubleQuote escapes a value for use inside double quotes
+// Only " and \ are escaped; $, ( ) and backticks are left for the shell (DR-044)
+func EscapeDoubleQuote(value string) string {
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return strings.ReplaceAll(value, `"`, `\"`)
+}
+
+// UnsafeChar records a character that cannot appear in an unquoted context
+type UnsafeChar struct {
+	Position int
+	Char     rune
+}
+
+// ValidateUnquoted returns the characters in value that are unsafe without quotes
+// Safe characters: a-z A-Z 0-9 _ - . / : (DR-044)
+func ValidateUnquoted(value string) []UnsafeChar {
+	var unsafe []UnsafeChar
+	for i, c := range value {
+		if !isUnquotedSafe(c) {
+			unsafe = append(unsafe, UnsafeChar{Position: i, Char: c})
+		}
+	}
+	return unsafe
+}
+
+// isUnquotedSafe reports whether a character is safe in an unquoted shell word
+func isUnquotedSafe(c rune) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		return true
+	}
+	return strings.ContainsRune("_-./:", c)
+}
+
+// FormatArgs renders an argv list as a single POSIX shell command line for display
+// Arguments containing unsafe characters are single-quoted
+func FormatArgs(args []string) string {
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		if arg != "" && len(ValidateUnquoted(arg)) == 0 {
+			parts[i] = arg
+		} else {
+			parts[i] = "'" + EscapeSingleQuote(arg) + "'"
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// QuoteError describes a placeholder value that cannot be safely substituted
+type QuoteError struct {
+	Agent       string
+	Config      string // Config file defining the agent command
+	Shell       string
+	Template    string
+	Placeholder string
+	Start       int // Byte offset of '{' in template
+	End         int // Byte offset after '}' in template
+	Context     QuoteContext
+	Value       string
+	Unsafe      []UnsafeChar
+}
+
+func (e *QuoteError) Error() string {
+	var sb strings.Builder
+
+	sb.WriteString("unsafe placeholder substitution detected\n\n")
+	if e.Agent != "" {
+		sb.WriteString(fmt.Sprintf("Agent: %s\n", e.Agent))
+	}
+	if e.Config != "" {
+		sb.WriteString(fmt.Sprintf("Config: %s\n", e.Config))
+	}
+	sb.WriteString("Field: command\n")
+	sb.WriteString(fmt.Sprintf("Template: %s\n", e.Template))
+	sb.WriteString(fmt.Sprintf("          %s^%s^\n", strings.Repeat(" ", e.Start), strings.Repeat("-", max(e.End-e.Start-2, 0))))
+	sb.WriteString(fmt.Sprintf("Position: characters %d-%d\n\n", e.Start, e.End))
+
+	sb.WriteString(fmt.Sprintf("Placeholder: %s\n", e.Placeholder))
+	sb.WriteString(fmt.Sprintf("Quote context: %s\n", e.Context))
+	sb.WriteString(fmt.Sprintf("Value: %q\n\n", truncateValue(e.Value, 60)))
+
+	sb.WriteString("Problematic characters found in value:\n")
+	limit := 10
+	for i, u := range e.Unsafe {
+		if i == limit {
+			sb.WriteString(fmt.Sprintf("  ... and %d more\n", len(e.Unsafe)-limit))
+			break
+		}
+		sb.WriteString(fmt.Sprintf("  - Position %d: %s\n", u.Position, describeChar(u.Char)))
+	}
+
+	sb.WriteString("\nThis placeholder must be quoted in the template to safely contain this value.\n\n")
+	sb.WriteString("Suggested fixes:\n")
+	sb.WriteString(fmt.Sprintf("1. Use single quotes in template:  '%s'\n", e.Placeholder))
+	if isFish(e.Shell) {
+		sb.WriteString("   (will auto-escape single quotes as \\' in content)\n")
+	} else {
+		sb.WriteString("   (will auto-escape single quotes as '\\'' in content)\n")
+	}
+	sb.WriteString(fmt.Sprintf("2. Use double quotes in template: \"%s\"\n", e.Placeholder))
+	sb.WriteString("   (will auto-escape \" and \\, allows $(commands) to execute)\n\n")
+
+	sb.WriteString("Suggested template:\n")
+	sb.WriteString(fmt.Sprintf("  %s'%s'%s", e.Template[:e.Start], e.Placeholder, e.Template[e.End:]))
+
+	return sb.String()
+}
+
+// describeChar returns a readable description of a character for error output
+func describeChar(c rune) string {
+	switch c {
+	case ' ':
+		return "' ' (space)"
+	case '\t':
+		return "'\\t' (tab)"
+	case '\n':
+		return "'\\n' (newline)"
+	case '\'':
+		return "' (single quote)"
+	case '"':
+		return "\" (double quote)"
+	case '$':
+		return "$ (dollar sign)"
+	case '`':
+		return "` (backtick)"
+	default:
+		return fmt.Sprintf("%q", c)
+	}
+}
+
+// truncateValue shortens long values for error output
+func truncateValue(value string, limit int) string {
+	runes := []rune(value)
+	if len(runes) <= limit {
+		return value
+	}
+	return string(runes[:limit]) + "..."
+}
+
+// substituteQuoted replaces known placeholders in a POSIX shell template,
+// escaping each value according to its quote context
+// fish single quotes honour \' and \\, so they are tracked and escaped as such
+func substituteQuoted(template string, values map[string]string, shell string) (string, error) {
+	fish := isFish(shell)
+	var sb strings.Builder
+	state := QuoteUnquoted
+	escaped := false
+
+	for i := 0; i < len(template); {
+		c := template[i]
+
+		if escaped {
+			sb.WriteByte(c)
+			escaped = false
+			i++
+			continue
+		}
+
+		if c == '{' {
+			if end := strings.IndexByte(template[i:], '}'); end > 0 {
+				key := template[i+1 : i+end]
+				if value, ok := values[key]; ok {
+					placeholder := template[i : i+end+1]
+					switch state {
+					case QuoteSingle:
+						if fish {
+							sb.WriteString(EscapeFishSingleQuote(value))
+						} else {
+							sb.WriteString(EscapeSingleQuote(value))
+						}
+					case QuoteDouble:
+						sb.WriteString(EscapeDoubleQuote(value))
+					default:
+						if unsafe := ValidateUnquoted(value); len(unsafe) > 0 {
+							return "", &QuoteError{
+								Shell:       shell,
+								Template:    template,
+								Placeholder: placeholder,
+								Start:       i,
+								End:         i + end + 1,
+								Context:     QuoteUnquoted,
+								Value:       value,
+								Unsafe:      unsafe,
+							}
+						}
+						sb.WriteString(value)
+					}
+					i += end + 1
+					continue
+				}
+			}
+		}
+
+		switch state {
+		case QuoteUnquoted:
+			switch c {
+			case '\'':
+				state = QuoteSingle
+			case '"':
+				state = QuoteDouble
+			case '\\':
+				escaped = true
+			}
+		case QuoteSingle:
+			switch {
+			case c == '\'':
+				state = QuoteUnquoted
+			case c == '\\' && fish:
+				escaped = true
+			}
+		case QuoteDouble:
+			switch c {
+			case '"':
+				state = QuoteUnquoted
+			case '\\':
+				escaped = true
+			}
+		}
+
+		sb.WriteByte(c)
+		i++
+	}
+
+	return sb.String(), nil
+}
@@ -0,0 +1,200 @@
+package engine_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/start/internal/engine"
+	"github.com/grantcarthew/start/test/assert"
+)
+
+func TestIsPOSIXShell(t *testing.T) {
+	tests := []struct {
+		shell string
+		want  bool
+	}{
+		{"bash", true},
+		{"sh", true},
+		{"zsh", true},
+		{"fish", true},
+		{"/bin/bash", true},
+		{"python", false},
+		{"python3", false},
+		{"node", false},
+		{"ruby", false},
+		{"perl", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			assert.Equal(t, tt.want, engine.IsPOSIXShell(tt.shell))
+		})
+	}
+}
+
+func TestEscapeSingleQuote(t *testing.T) {
+	assert.Equal(t, `You'\''re a Go expert`, engine.EscapeSingleQuote("You're a Go expert"))
+	assert.Equal(t, `no quotes`, engine.EscapeSingleQuote("no quotes"))
+}
+
+func TestEscapeFishSingleQuote(t *testing.T) {
+	assert.Equal(t, `You\'re a Go expert`, engine.EscapeFishSingleQuote("You're a Go expert"))
+	assert.Equal(t, `C:\\path\\`, engine.EscapeFishSingleQuote(`C:\path\`))
+}
+
+func TestEscapeDoubleQuote(t *testing.T) {
+	assert.Equal(t, `He said \"hello\"`, engine.EscapeDoubleQuote(`He said "hello"`))
+	assert.Equal(t, `C:\\path`, engine.EscapeDoubleQuote(`C:\path`))
+	// $() and backticks are left for the shell (DR-044)
+	assert.Equal(t, "Today is $(date) `whoami`", engine.EscapeDoubleQuote("Today is $(date) `whoami`"))
+}
+
+func TestValidateUnquoted(t *testing.T) {
+	assert.Equal(t, 0, len(engine.ValidateUnquoted("claude-sonnet-4.5")))
+	assert.Equal(t, 0, len(engine.ValidateUnquoted("/usr/bin/claude")))
+
+	unsafe := engine.ValidateUnquoted("hello world")
+	assert.Equal(t, 1, len(unsafe))
+	assert.Equal(t, 5, unsafe[0].Position)
+	assert.Equal(t, ' ', unsafe[0].Char)
+
+	// Only a-z A-Z 0-9 _ - . / : are safe (DR-044)
+	assert.Equal(t, 4, len(engine.ValidateUnquoted("a+b=c,d@e")))
+	assert.Equal(t, 1, len(engine.ValidateUnquoted("50%")))
+}
+
+func TestPlaceholderResolver_ResolveCommand_SingleQuoted(t *testing.T) {
+	resolver := engine.NewPlaceholderResolver(nil, "")
+
+	result, err := resolver.ResolveCommand(
+		"{bin} --model {model} --append-system-prompt '{role}' '{prompt}'",
+		map[string]string{
+			"bin":    "claude",
+			"model":  "sonnet",
+			"role":   "You're a Go expert",
+			"prompt": "it's fine",
+		},
+		"bash",
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `claude --model sonnet --append-system-prompt 'You'\''re a Go expert' 'it'\''s fine'`, result)
+}
+
+func TestPlaceholderResolver_ResolveCommand_DoubleQuoted(t *testing.T) {
+	resolver := engine.NewPlaceholderResolver(nil, "")
+
+	result, err := resolver.ResolveCommand(
+		`{bin} --prompt "{prompt}"`,
+		map[string]string{
+			"bin":    "gemini",
+			"prompt": `Say "hi" at $(date)`,
+		},
+		"bash",
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `gemini --prompt "Say \"hi\" at $(date)"`, result)
+}
+
+func TestPlaceholderResolver_ResolveCommand_Fish(t *testing.T) {
+	resolver := engine.NewPlaceholderResolver(nil, "")
+
+	// \' inside fish single quotes does not end the string
+	result, err := resolver.ResolveCommand(
+		`{bin} --note 'it\'s' '{prompt}'`,
+		map[string]string{
+			"bin":    "claude",
+			"prompt": `it's C:\dir\`,
+		},
+		"/usr/bin/fish",
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `claude --note 'it\'s' 'it\'s C:\\dir\\'`, result)
+}
+
+func TestPlaceholderResolver_ResolveCommand_QuoteStateTracking(t *testing.T) {
+	resolver := engine.NewPlaceholderResolver(nil, "")
+
+	// A single quote inside double quotes does not open a single-quoted string
+	result, err := resolver.ResolveCommand(
+		`{bin} "it's {role}" '{prompt}'`,
+		map[string]string{
+			"bin":    "agent",
+			"role":   `a "role"`,
+			"prompt": "don't",
+		},
+		"bash",
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, `agent "it's a \"role\"" 'don'\''t'`, result)
+}
+
+func TestPlaceholderResolver_ResolveCommand_UnquotedUnsafe(t *testing.T) {
+	resolver := engine.NewPlaceholderResolver(nil, "")
+
+	_, err := resolver.ResolveCommand(
+		"{bin} {prompt}",
+		map[string]string{
+			"bin":    "smith",
+			"prompt": "hello world",
+		},
+		"bash",
+	)
+
+	assert.Error(t, err)
+
+	var quoteErr *engine.QuoteError
+	assert.True(t, errors.As(err, &quoteErr), "error should be a QuoteError")
+	assert.Equal(t, "{prompt}", quoteErr.Placeholder)
+	assert.Equal(t, 6, quoteErr.Start)
+	assert.Equal(t, 14, quoteErr.End)
+	assert.Equal(t, engine.QuoteUnquoted, quoteErr.Context)
+	assert.Contains(t, err.Error(), "Position: characters 6-14")
+	assert.Contains(t, err.Error(), "{bin} '{prompt}'")
+
+	quoteErr.Agent = "smith"
+	quoteErr.Config = "/home/user/.config/start/agents.toml"
+	assert.Contains(t, quoteErr.Error(), "Agent: smith\nConfig: /home/user/.config/start/agents.toml\nField: command\n")
+}
+
+func TestPlaceholderResolver_ResolveCommand_UnknownPlaceholderUntouched(t *testing.T) {
+	resolver := engine.NewPlaceholderResolver(nil, "")
+
+	result, err := resolver.ResolveCommand(
+		"{bin} {unknown} '{prompt}'",
+		map[string]string{
+			"bin":    "smith",
+			"prompt": "hi",
+		},
+		"bash",
+	)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "smith {unknown} 'hi'", result)
+}
+
+func TestPlaceholderResolver_ResolveCommand_NonPOSIXShell(t *testing.T) {
+	resolver := engine.NewPlaceholderResolver(nil, "")
+
+	// No escaping for programming languages - user handles quoting
+	result, err := resolver.ResolveCommand(
+		"run('{bin}', '{prompt}')",
+		map[string]string{
+			"bin":    "agent",
+			"prompt": "You're",
+		},
+		"python",
+	)
+
+	assert.NoError(t, err)
+	assert.True(t, strings.Contains(result, "'You're'"), "value should be substituted verbatim")
+}
+
+func TestFormatArgs(t *testing.T) {
+	result := engine.FormatArgs([]string{"claude", "--model", "sonnet", "You're here", ""})
+	assert.Equal(t, `claude --model sonnet 'You'\''re here' ''`, result)
+}
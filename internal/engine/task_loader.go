@@ -2,7 +2,6 @@ package engine
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/grantcarthew/start/internal/domain"
 )
@@ -23,32 +22,65 @@ func NewTaskLoader(utdProcessor *UTDProcessor, resolver *PlaceholderResolver) *T
 
 // LoadedTask represents a processed task
 type LoadedTask struct {
-	Name         string
-	Prompt       string   // Final task prompt with placeholders resolved
-	CommandExec  string   // Command that was executed (for display)
-	Warnings     []string // Warnings during processing
+	Name        string
+	Prompt      string            // Final task prompt with placeholders resolved
+	CommandExec string            // Command that was executed (for display)
+	Cached      bool              // True if command output came from the cache
+	Warnings    []string          // Warnings during processing
+	Params      map[string]string // Parameter values used for {param:name}
 }
 
 // LoadTask loads and processes a task through UTD with instructions
+// params holds values from ResolveParams for {param:name}
 func (l *TaskLoader) LoadTask(
 	task domain.Task,
 	instructions string,
 	defaultShell string,
 	defaultTimeout int,
+	variables map[string]string,
+	params map[string]string,
 ) (LoadedTask, error) {
 	result := LoadedTask{
 		Name:     task.Name,
 		Warnings: []string{},
+		Params:   params,
+	}
+
+	if ok, reason := l.utdProcessor.CheckWhen(task.When, defaultShell, defaultTimeout); !ok {
+		return result, fmt.Errorf("task %q does not apply here (%s)", task.Name, reason)
 	}
 
 	// Process through UTD to get file contents and command output
 	utdInput := UTDInput{
 		File:           task.File,
 		Command:        task.Command,
+		URL:            task.URL,
 		Prompt:         task.Prompt,
 		Shell:          task.Shell,
 		CommandTimeout: task.CommandTimeout,
+		CacheTTL:       task.CacheTTL,
+		CacheKeyFiles:  task.CacheKeyFiles,
+		FileHeader:     task.FileHeader,
+		MaxFiles:       task.MaxFiles,
+		MaxBytes:       task.MaxBytes,
+		Variables:      variables,
+		Params:         params,
+	}
+	if utdInput.Params == nil {
+		utdInput.Params = map[string]string{}
+	}
+
+	// {instructions} goes into the template in the same pass as file and
+	// command content, after built-ins, so user text is never expanded
+	// Default to "None" if instructions are empty (per DR-009)
+	instructionsValue := instructions
+	if instructionsValue == "" {
+		instructionsValue = "None"
 	}
+	utdInput.Placeholders = map[string]string{"instructions": instructionsValue}
+
+	// Without a prompt the task file is the template
+	utdInput.FileTemplate = true
 
 	utdResult := l.utdProcessor.Process(utdInput, defaultShell, defaultTimeout)
 
@@ -59,24 +91,7 @@ func (l *TaskLoader) LoadTask(
 
 	result.Warnings = utdResult.Warnings
 	result.CommandExec = task.Command
-
-	// Now resolve task-specific placeholders
-	// The UTD processor already handled {file}, {file_contents}, {command}, {command_output}
-	// We need to handle {instructions} placeholder
-	prompt := utdResult.Content
-
-	// Handle {instructions} placeholder
-	// Default to "None" if instructions are empty (per DR-009)
-	instructionsValue := instructions
-	if instructionsValue == "" {
-		instructionsValue = "None"
-	}
-	prompt = strings.ReplaceAll(prompt, "{instructions}", instructionsValue)
-
-	// Handle universal placeholders ({date}) through resolver
-	// Pass empty values map since we only need {date} which is automatic
-	prompt = l.resolver.Resolve(prompt, map[string]string{})
-
-	result.Prompt = prompt
+	result.Cached = utdResult.Cached
+	result.Prompt = utdResult.Content
 	return result, nil
 }
@@ -110,12 +110,12 @@ func TestTaskLoader_LoadTask(t *testing.T) {
 			}
 
 			// Create components
-			utdProcessor := NewUTDProcessor(fs, cmdRunner, ".")
-			resolver := NewPlaceholderResolver()
+			utdProcessor := NewUTDProcessor(fs, cmdRunner, ".", nil, nil)
+			resolver := NewPlaceholderResolver(nil, "")
 			loader := NewTaskLoader(utdProcessor, resolver)
 
 			// Execute
-			result, err := loader.LoadTask(tt.task, tt.instructions, "bash", 30)
+			result, err := loader.LoadTask(tt.task, tt.instructions, "bash", 30, nil, nil)
 
 			// Check error
 			if tt.wantErr {
@@ -148,11 +148,11 @@ func TestTaskLoader_DatePlaceholder(t *testing.T) {
 
 	fs := mocks.NewMockFileSystem()
 	cmdRunner := mocks.NewMockCommandRunner()
-	utdProcessor := NewUTDProcessor(fs, cmdRunner, ".")
-	resolver := NewPlaceholderResolver()
+	utdProcessor := NewUTDProcessor(fs, cmdRunner, ".", nil, nil)
+	resolver := NewPlaceholderResolver(nil, "")
 	loader := NewTaskLoader(utdProcessor, resolver)
 
-	result, err := loader.LoadTask(task, "test instructions", "bash", 30)
+	result, err := loader.LoadTask(task, "test instructions", "bash", 30, nil, nil)
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
 	}
@@ -167,3 +167,44 @@ func TestTaskLoader_DatePlaceholder(t *testing.T) {
 		t.Errorf("Prompt does not contain instructions: %q", result.Prompt)
 	}
 }
+
+func TestTaskLoader_BuiltinsOnlyInTemplate(t *testing.T) {
+	// Built-ins resolve in the task template, but not in instructions or
+	// command output inserted into it
+	t.Setenv("START_TEST_SECRET", "leaked")
+
+	fs := mocks.NewMockFileSystem()
+	fs.Files["task.md"] = "Secret {env:START_TEST_SECRET}\n{command_output}\n{instructions}"
+	cmdRunner := mocks.NewMockCommandRunner()
+	cmdRunner.Outputs["git log -1"] = "output {env:START_TEST_SECRET}"
+	utdProcessor := NewUTDProcessor(fs, cmdRunner, ".", nil, nil)
+	loader := NewTaskLoader(utdProcessor, NewPlaceholderResolver(nil, ""))
+
+	tests := []struct {
+		name string
+		task domain.Task
+		want string
+	}{
+		{
+			name: "prompt",
+			task: domain.Task{Name: "p", Command: "git log -1", Prompt: "Secret {env:START_TEST_SECRET}\n{command_output}\n{instructions}"},
+			want: "Secret leaked\noutput {env:START_TEST_SECRET}\nask {env:START_TEST_SECRET} {file}",
+		},
+		{
+			name: "file",
+			task: domain.Task{Name: "f", Command: "git log -1", File: "task.md"},
+			want: "Secret leaked\noutput {env:START_TEST_SECRET}\nask {env:START_TEST_SECRET} {file}",
+		},
+	}
+
+	for _, tt := range tests {
+		result, err := loader.LoadTask(tt.task, "ask {env:START_TEST_SECRET} {file}", "bash", 30, nil, nil)
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", tt.name, err)
+			continue
+		}
+		if result.Prompt != tt.want {
+			t.Errorf("%s: Prompt = %q, want %q", tt.name, result.Prompt, tt.want)
+		}
+	}
+}
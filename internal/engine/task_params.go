@@ -0,0 +1,195 @@
+package engine
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/grantcarthew/start/internal/domain"
+)
+
+// Task parameter types
+const (
+	ParamTypeString = "string"
+	ParamTypeInt    = "int"
+	ParamTypeBool   = "bool"
+	ParamTypeEnum   = "enum"
+	ParamTypePath   = "path"
+)
+
+// paramPattern matches {param:name} references to task parameters
+var paramPattern = regexp.MustCompile(`\{param:([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// ExpandParams replaces {param:name} references with parameter values
+// Returns a warning for each reference to an undeclared parameter, which is left as-is
+func ExpandParams(template string, params map[string]string) (string, []string) {
+	return expandReferences(paramPattern, template, params, "Parameter %s is not defined")
+}
+
+// ParseParamFlags parses --param key=value flags
+func ParseParamFlags(flags []string) (map[string]string, error) {
+	return parseAssignments("--param", flags)
+}
+
+// ParamNames returns a task's parameter names in definition order
+// Params missing from ParamOrder follow in name order
+func ParamNames(task domain.Task) []string {
+	var names []string
+	for _, name := range task.ParamOrder {
+		if _, ok := task.Params[name]; ok {
+			names = append(names, name)
+		}
+	}
+
+	var rest []string
+	for name := range task.Params {
+		if !slices.Contains(names, name) {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+
+	return append(names, rest...)
+}
+
+// ParamType returns the parameter type, defaulting to string
+func ParamType(param domain.TaskParam) string {
+	if param.Type == "" {
+		return ParamTypeString
+	}
+	return param.Type
+}
+
+// DescribeParam returns a short usage form, e.g. "scope=api|web" or "depth:int"
+func DescribeParam(name string, param domain.TaskParam) string {
+	var usage string
+	switch ParamType(param) {
+	case ParamTypeEnum:
+		usage = name + "=" + strings.Join(param.Values, "|")
+	case ParamTypeString:
+		usage = name
+	default:
+		usage = name + ":" + ParamType(param)
+	}
+
+	if param.Default != nil {
+		usage += fmt.Sprintf(" (default %v)", param.Default)
+	} else if param.Required {
+		usage += " (required)"
+	}
+	return usage
+}
+
+// ResolveParams validates parameter values for a task
+// Values come from supplied (--param) first, then positional args for required
+// params in definition order, then defaults. Positional args left over are
+// returned for instructions
+func (l *TaskLoader) ResolveParams(task domain.Task, supplied map[string]string, positional []string) (map[string]string, []string, error) {
+	names := ParamNames(task)
+
+	for name := range supplied {
+		if _, ok := task.Params[name]; !ok {
+			if len(names) == 0 {
+				return nil, nil, fmt.Errorf("task %q has no parameters (got --param %s)", task.Name, name)
+			}
+			return nil, nil, fmt.Errorf("unknown parameter %q for task %q (available: %s)", name, task.Name, strings.Join(names, ", "))
+		}
+	}
+
+	values := make(map[string]string, len(names))
+	for _, name := range names {
+		param := task.Params[name]
+
+		value, ok := supplied[name]
+		if !ok && param.Required && len(positional) > 0 {
+			value, positional, ok = positional[0], positional[1:], true
+		}
+		// An empty value leaves an optional parameter unset
+		if ok && value == "" && !param.Required {
+			values[name] = ""
+			continue
+		}
+		if !ok {
+			switch {
+			case param.Default != nil:
+				value = fmt.Sprint(param.Default)
+			case param.Required:
+				return nil, nil, fmt.Errorf("missing required parameter %q for task %q (use --param %s=<value>)", name, task.Name, name)
+			default:
+				values[name] = ""
+				continue
+			}
+		}
+
+		normalized, err := l.normalizeParam(param, value)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid parameter %q for task %q: %w", name, task.Name, err)
+		}
+		values[name] = normalized
+	}
+
+	return values, positional, nil
+}
+
+// normalizeParam checks a value against the parameter type
+func (l *TaskLoader) normalizeParam(param domain.TaskParam, value string) (string, error) {
+	switch ParamType(param) {
+	case ParamTypeInt:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return "", fmt.Errorf("%q is not an integer", value)
+		}
+		return strconv.Itoa(n), nil
+
+	case ParamTypeBool:
+		switch strings.ToLower(value) {
+		case "yes", "on":
+			return "true", nil
+		case "no", "off":
+			return "false", nil
+		}
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", fmt.Errorf("%q is not a boolean (use true or false)", value)
+		}
+		return strconv.FormatBool(b), nil
+
+	case ParamTypeEnum:
+		if !slices.Contains(param.Values, value) {
+			return "", fmt.Errorf("%q must be one of: %s", value, strings.Join(param.Values, ", "))
+		}
+
+	case ParamTypePath:
+		if !l.utdProcessor.fs.Exists(l.utdProcessor.resolvePath(value)) {
+			return "", fmt.Errorf("path %q does not exist", value)
+		}
+	}
+
+	return value, nil
+}
+
+// SampleParams returns example values for previewing a task: the default, the
+// first enum value, or a type placeholder
+func SampleParams(task domain.Task) map[string]string {
+	values := make(map[string]string, len(task.Params))
+	for name, param := range task.Params {
+		switch {
+		case param.Default != nil:
+			values[name] = fmt.Sprint(param.Default)
+		case ParamType(param) == ParamTypeEnum && len(param.Values) > 0:
+			values[name] = param.Values[0]
+		case ParamType(param) == ParamTypeInt:
+			values[name] = "1"
+		case ParamType(param) == ParamTypeBool:
+			values[name] = "true"
+		case ParamType(param) == ParamTypePath:
+			values[name] = "."
+		default:
+			values[name] = "<" + name + ">"
+		}
+	}
+	return values
+}
@@ -0,0 +1,138 @@
+package engine_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/start/internal/domain"
+	"github.com/grantcarthew/start/internal/engine"
+	"github.com/grantcarthew/start/test/assert"
+	"github.com/grantcarthew/start/test/mocks"
+)
+
+// reviewTask declares one parameter of each type
+func reviewTask() domain.Task {
+	return domain.Task{
+		Name:   "review",
+		Prompt: "Review {param:scope} at depth {param:depth}",
+		Params: map[string]domain.TaskParam{
+			"scope":  {Type: "enum", Values: []string{"api", "web"}, Required: true},
+			"depth":  {Type: "int", Default: int64(2)},
+			"strict": {Type: "bool"},
+			"spec":   {Type: "path"},
+			"note":   {},
+		},
+		ParamOrder: []string{"scope", "depth", "strict", "spec", "note"},
+	}
+}
+
+func newParamLoader(files ...string) *engine.TaskLoader {
+	fs := mocks.NewMockFileSystem()
+	for _, file := range files {
+		fs.Files[file] = ""
+	}
+	utd := engine.NewUTDProcessor(fs, mocks.NewMockCommandRunner(), "/work", nil, nil)
+	return engine.NewTaskLoader(utd, engine.NewPlaceholderResolver(nil, "/work"))
+}
+
+func TestTaskLoader_ResolveParams_FlagsAndDefaults(t *testing.T) {
+	loader := newParamLoader("/work/api.yaml")
+
+	params, rest, err := loader.ResolveParams(reviewTask(), map[string]string{
+		"scope":  "web",
+		"strict": "yes",
+		"spec":   "api.yaml",
+	}, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "web", params["scope"])
+	assert.Equal(t, "2", params["depth"])
+	assert.Equal(t, "true", params["strict"])
+	assert.Equal(t, "api.yaml", params["spec"])
+	assert.Equal(t, "", params["note"])
+	assert.Equal(t, 0, len(rest))
+}
+
+func TestTaskLoader_ResolveParams_Positional(t *testing.T) {
+	loader := newParamLoader()
+
+	// Positional args fill required params, the rest are instructions
+	params, rest, err := loader.ResolveParams(reviewTask(), map[string]string{"depth": "05"}, []string{"api", "focus", "on", "auth"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "api", params["scope"])
+	assert.Equal(t, "5", params["depth"])
+	assert.Equal(t, 3, len(rest))
+	assert.Equal(t, "focus", rest[0])
+}
+
+func TestTaskLoader_ResolveParams_Errors(t *testing.T) {
+	loader := newParamLoader()
+
+	tests := []struct {
+		name     string
+		supplied map[string]string
+		expect   string
+	}{
+		{"missing required", map[string]string{}, `missing required parameter "scope"`},
+		{"enum value", map[string]string{"scope": "cli"}, "must be one of: api, web"},
+		{"int value", map[string]string{"scope": "api", "depth": "deep"}, "is not an integer"},
+		{"bool value", map[string]string{"scope": "api", "strict": "maybe"}, "is not a boolean"},
+		{"missing path", map[string]string{"scope": "api", "spec": "nope.yaml"}, `path "nope.yaml" does not exist`},
+		{"unknown param", map[string]string{"scope": "api", "level": "1"}, `unknown parameter "level"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := loader.ResolveParams(reviewTask(), tt.supplied, nil)
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), tt.expect)
+		})
+	}
+}
+
+func TestTaskLoader_LoadTaskParams(t *testing.T) {
+	loader := newParamLoader()
+
+	result, err := loader.LoadTask(reviewTask(), "", "bash", 30, nil, map[string]string{"scope": "api", "depth": "3"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "Review api at depth 3", result.Prompt)
+	assert.Equal(t, "api", result.Params["scope"])
+}
+
+func TestExpandParams_Undeclared(t *testing.T) {
+	result, warnings := engine.ExpandParams("{param:scope} {param:other}", map[string]string{"scope": "api"})
+
+	assert.Equal(t, "api {param:other}", result)
+	assert.Equal(t, 1, len(warnings))
+	assert.Contains(t, warnings[0], "{param:other}")
+}
+
+func TestParamNames_DefinitionOrder(t *testing.T) {
+	task := reviewTask()
+	task.ParamOrder = []string{"spec", "scope"}
+
+	names := engine.ParamNames(task)
+
+	// Params missing from the recorded order follow by name
+	assert.Equal(t, "spec,scope,depth,note,strict", strings.Join(names, ","))
+}
+
+func TestSampleParams(t *testing.T) {
+	samples := engine.SampleParams(reviewTask())
+
+	assert.Equal(t, "api", samples["scope"])
+	assert.Equal(t, "2", samples["depth"])
+	assert.Equal(t, "true", samples["strict"])
+	assert.Equal(t, ".", samples["spec"])
+	assert.Equal(t, "<note>", samples["note"])
+}
+
+func TestDescribeParam(t *testing.T) {
+	task := reviewTask()
+
+	assert.Equal(t, "scope=api|web (required)", engine.DescribeParam("scope", task.Params["scope"]))
+	assert.Equal(t, "depth:int (default 2)", engine.DescribeParam("depth", task.Params["depth"]))
+	assert.Equal(t, "note", engine.DescribeParam("note", task.Params["note"]))
+}
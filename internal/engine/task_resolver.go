@@ -1,11 +1,20 @@
 package engine
 
 import (
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/grantcarthew/start/internal/domain"
 )
 
+// ErrTaskNotFound is returned by Resolve when no task name or alias matches
+var ErrTaskNotFound = errors.New("not found")
+
+// GlobalTaskPrefix scopes an extends reference to the global config
+const GlobalTaskPrefix = "global:"
+
 // TaskResolver resolves task names and aliases
 type TaskResolver struct{}
 
@@ -22,42 +31,219 @@ type ResolveResult struct {
 
 // Resolve resolves a task name or alias
 // Resolution order: local task name → local alias → global task name → global alias
-// Returns the resolved task or error if not found
+// Returns the effective task, with extends applied, or error if not found
 func (r *TaskResolver) Resolve(
 	input string,
 	localTasks map[string]domain.Task,
 	globalTasks map[string]domain.Task,
 ) (domain.Task, error) {
+	task, isLocal, found := r.find(input, localTasks, globalTasks)
+	if !found {
+		return domain.Task{}, fmt.Errorf("task %q %w", input, ErrTaskNotFound)
+	}
+	return r.Effective(task, isLocal, localTasks, globalTasks)
+}
+
+// find looks up a task name or alias, reporting whether it came from local config
+func (r *TaskResolver) find(
+	input string,
+	localTasks map[string]domain.Task,
+	globalTasks map[string]domain.Task,
+) (domain.Task, bool, bool) {
 	// 1. Check local task name (exact match)
 	if task, exists := localTasks[input]; exists {
 		task.Name = input
-		return task, nil
+		return task, true, true
 	}
 
 	// 2. Check local task alias
 	for name, task := range localTasks {
 		if task.Alias == input {
 			task.Name = name
-			return task, nil
+			return task, true, true
 		}
 	}
 
 	// 3. Check global task name (exact match)
 	if task, exists := globalTasks[input]; exists {
 		task.Name = input
-		return task, nil
+		return task, false, true
 	}
 
 	// 4. Check global task alias
 	for name, task := range globalTasks {
 		if task.Alias == input {
 			task.Name = name
-			return task, nil
+			return task, false, true
+		}
+	}
+
+	return domain.Task{}, false, false
+}
+
+// Effective applies a task's extends chain, parents first, each overridden
+// field by field by the task that extends it
+// isLocal says which config the task came from: a plain reference prefers
+// local tasks, except the task's own name, which reaches the global definition
+func (r *TaskResolver) Effective(
+	task domain.Task,
+	isLocal bool,
+	localTasks map[string]domain.Task,
+	globalTasks map[string]domain.Task,
+) (domain.Task, error) {
+	chain := []domain.Task{task}
+	path := []string{task.Name}
+	seen := []string{taskKey(task.Name, isLocal)}
+
+	for current := task; current.Extends != ""; {
+		parent, parentLocal, ok := r.findParent(current.Extends, current.Name, isLocal, localTasks, globalTasks)
+		if !ok {
+			return task, fmt.Errorf("task %q extends %q, which is not in configuration", current.Name, current.Extends)
+		}
+
+		path = append(path, current.Extends)
+		key := taskKey(parent.Name, parentLocal)
+		if slices.Contains(seen, key) {
+			return task, fmt.Errorf("task inheritance cycle: %s", strings.Join(path, " → "))
+		}
+		seen = append(seen, key)
+
+		chain = append(chain, parent)
+		current, isLocal = parent, parentLocal
+	}
+
+	result := chain[len(chain)-1]
+	for i := len(chain) - 2; i >= 0; i-- {
+		result = overrideTask(result, chain[i])
+	}
+	return result, nil
+}
+
+// findParent looks up an extends reference made by the task from
+func (r *TaskResolver) findParent(
+	ref, from string,
+	fromLocal bool,
+	localTasks map[string]domain.Task,
+	globalTasks map[string]domain.Task,
+) (domain.Task, bool, bool) {
+	name, globalOnly := strings.CutPrefix(ref, GlobalTaskPrefix)
+
+	if task, ok := localTasks[name]; ok && !globalOnly && !(fromLocal && name == from) {
+		task.Name = name
+		return task, true, true
+	}
+	if task, ok := globalTasks[name]; ok && (fromLocal || name != from) {
+		task.Name = name
+		return task, false, true
+	}
+	return domain.Task{}, false, false
+}
+
+// taskKey identifies a task by scope and name
+func taskKey(name string, isLocal bool) string {
+	if isLocal {
+		return "local:" + name
+	}
+	return GlobalTaskPrefix + name
+}
+
+// overrideTask returns parent with every field the child sets replaced
+// Aliases and order are not inherited. The prompt sources (file, command,
+// url and prompt) override one by one, but a child prompt only keeps the
+// inherited sources it references. Prompt sources and steps replace each other
+func overrideTask(parent, child domain.Task) domain.Task {
+	result := parent
+	result.Name = child.Name
+	result.Alias = child.Alias
+	result.Order = child.Order
+	result.Extends = child.Extends
+
+	overrideString(&result.Description, child.Description)
+	overrideString(&result.Role, child.Role)
+	overrideString(&result.Agent, child.Agent)
+	overrideString(&result.Shell, child.Shell)
+	overrideString(&result.CacheTTL, child.CacheTTL)
+	overrideString(&result.FileHeader, child.FileHeader)
+	overrideInt(&result.CommandTimeout, child.CommandTimeout)
+	overrideInt(&result.MaxFiles, child.MaxFiles)
+	overrideInt(&result.MaxBytes, child.MaxBytes)
+
+	if child.CacheKeyFiles != nil {
+		result.CacheKeyFiles = child.CacheKeyFiles
+	}
+	if child.Contexts != nil {
+		result.Contexts = child.Contexts
+	}
+	if child.When != nil {
+		result.When = child.When
+	}
+
+	if len(child.Steps) > 0 {
+		result.File, result.Command, result.URL, result.Prompt = "", "", "", ""
+		result.Steps = child.Steps
+	} else if child.File != "" || child.Command != "" || child.URL != "" || child.Prompt != "" {
+		overrideString(&result.File, child.File)
+		overrideString(&result.Command, child.Command)
+		overrideString(&result.URL, child.URL)
+		overrideString(&result.Prompt, child.Prompt)
+		result.Steps = nil
+
+		// A new prompt leaves no unused file, command or url behind
+		if child.Prompt != "" {
+			if child.File == "" && !promptUses(child.Prompt, "{file}", "{file_contents}") {
+				result.File = ""
+			}
+			if child.Command == "" && !promptUses(child.Prompt, "{command}", "{command_output}") {
+				result.Command = ""
+			}
+			if child.URL == "" && !promptUses(child.Prompt, "{url}", "{url_contents}") {
+				result.URL = ""
+			}
+		}
+	}
+
+	// Params merge by name, new ones follow the parent's in definition order
+	if len(child.Params) > 0 {
+		result.Params = make(map[string]domain.TaskParam, len(parent.Params)+len(child.Params))
+		for name, param := range parent.Params {
+			result.Params[name] = param
+		}
+		result.ParamOrder = slices.Clone(parent.ParamOrder)
+		for _, name := range child.ParamOrder {
+			if _, ok := parent.Params[name]; !ok {
+				result.ParamOrder = append(result.ParamOrder, name)
+			}
+		}
+		for name, param := range child.Params {
+			result.Params[name] = param
 		}
 	}
 
-	// Task not found
-	return domain.Task{}, fmt.Errorf("task %q not found", input)
+	return result
+}
+
+// promptUses reports whether prompt contains any of the placeholders
+func promptUses(prompt string, placeholders ...string) bool {
+	for _, placeholder := range placeholders {
+		if strings.Contains(prompt, placeholder) {
+			return true
+		}
+	}
+	return false
+}
+
+// overrideString replaces dst when value is set
+func overrideString(dst *string, value string) {
+	if value != "" {
+		*dst = value
+	}
+}
+
+// overrideInt replaces dst when value is set
+func overrideInt(dst *int, value int) {
+	if value != 0 {
+		*dst = value
+	}
 }
 
 // ListAllTasks returns all tasks from local and global configs
@@ -71,14 +257,28 @@ func (r *TaskResolver) ListAllTasks(
 	// Start with global tasks
 	for name, task := range globalTasks {
 		task.Name = name
-		result[name] = task
+		result[name] = r.effectiveOrSelf(task, false, localTasks, globalTasks)
 	}
 
 	// Override with local tasks
 	for name, task := range localTasks {
 		task.Name = name
-		result[name] = task
+		result[name] = r.effectiveOrSelf(task, true, localTasks, globalTasks)
 	}
 
 	return result
 }
+
+// effectiveOrSelf returns the effective task, or the task as written when its
+// extends chain is broken
+func (r *TaskResolver) effectiveOrSelf(
+	task domain.Task,
+	isLocal bool,
+	localTasks map[string]domain.Task,
+	globalTasks map[string]domain.Task,
+) domain.Task {
+	if effective, err := r.Effective(task, isLocal, localTasks, globalTasks); err == nil {
+		return effective
+	}
+	return task
+}
@@ -1,6 +1,8 @@
 package engine
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/grantcarthew/start/internal/domain"
@@ -34,11 +36,11 @@ func TestTaskResolver_Resolve(t *testing.T) {
 	}
 
 	tests := []struct {
-		name        string
-		input       string
-		wantTask    string // task name
-		wantPrompt  string // to verify we got right task
-		wantErr     bool
+		name       string
+		input      string
+		wantTask   string // task name
+		wantPrompt string // to verify we got right task
+		wantErr    bool
 	}{
 		{
 			name:       "resolve local task by name",
@@ -194,3 +196,87 @@ func TestTaskResolver_EmptyConfigs(t *testing.T) {
 		t.Errorf("Expected 0 tasks with empty configs, got %d", len(allTasks))
 	}
 }
+
+func TestTaskResolver_Extends(t *testing.T) {
+	globalTasks := map[string]domain.Task{
+		"code-review": {
+			Alias:       "cr",
+			Description: "Review staged changes",
+			Role:        "code-reviewer",
+			File:        "REVIEW.md",
+			Command:     "git diff --staged",
+			Prompt:      "Review against {file}:\n{command_output}",
+			Params:      map[string]domain.TaskParam{"focus": {Default: "all"}},
+			ParamOrder:  []string{"focus"},
+		},
+	}
+	localTasks := map[string]domain.Task{
+		// Same name as the global task: a plain reference reaches the global one
+		"code-review": {Extends: "code-review", Role: "go-expert"},
+		"security-review": {
+			Extends:    "global:code-review",
+			Prompt:     "Security review:\n{command_output}",
+			Params:     map[string]domain.TaskParam{"depth": {Type: "int"}},
+			ParamOrder: []string{"depth"},
+		},
+	}
+
+	resolver := NewTaskResolver()
+
+	task, err := resolver.Resolve("code-review", localTasks, globalTasks)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if task.Role != "go-expert" || task.Command != "git diff --staged" || task.Description != "Review staged changes" {
+		t.Errorf("Expected local role over inherited fields, got %+v", task)
+	}
+	if task.Alias != "" {
+		t.Errorf("Expected alias not inherited, got %q", task.Alias)
+	}
+	if task.File != "REVIEW.md" {
+		t.Errorf("Expected file inherited with the prompt, got %q", task.File)
+	}
+
+	task, err = resolver.Resolve("security-review", localTasks, globalTasks)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if task.Prompt != "Security review:\n{command_output}" || task.Command != "git diff --staged" || task.Role != "code-reviewer" {
+		t.Errorf("Expected prompt override on the global task, got %+v", task)
+	}
+	// The new prompt does not use the parent's file, so it is not inherited
+	if task.File != "" {
+		t.Errorf("Expected unreferenced file dropped, got %q", task.File)
+	}
+	if len(task.Params) != 2 || strings.Join(task.ParamOrder, ",") != "focus,depth" {
+		t.Errorf("Expected merged params focus,depth, got %v %v", task.Params, task.ParamOrder)
+	}
+	if len(globalTasks["code-review"].Params) != 1 {
+		t.Error("Expected parent params left unchanged")
+	}
+}
+
+func TestTaskResolver_ExtendsErrors(t *testing.T) {
+	resolver := NewTaskResolver()
+
+	localTasks := map[string]domain.Task{
+		"a": {Extends: "b", Prompt: "A"},
+		"b": {Extends: "a", Prompt: "B"},
+		"c": {Extends: "global:missing", Prompt: "C"},
+	}
+
+	_, err := resolver.Resolve("a", localTasks, nil)
+	if err == nil || err.Error() != "task inheritance cycle: a → b → a" {
+		t.Errorf("Expected cycle error, got: %v", err)
+	}
+
+	_, err = resolver.Resolve("c", localTasks, nil)
+	if err == nil || err.Error() != `task "c" extends "global:missing", which is not in configuration` {
+		t.Errorf("Expected unknown parent error, got: %v", err)
+	}
+
+	_, err = resolver.Resolve("nonexistent", localTasks, nil)
+	if !errors.Is(err, ErrTaskNotFound) || err.Error() != `task "nonexistent" not found` {
+		t.Errorf("Expected not found error, got: %v", err)
+	}
+}
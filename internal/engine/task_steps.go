@@ -0,0 +1,93 @@
+package engine
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/grantcarthew/start/internal/domain"
+)
+
+// Step failure policies
+const (
+	StepOnFailureStop     = "stop"
+	StepOnFailureContinue = "continue"
+	StepOnFailureRetry    = "retry"
+)
+
+// stepPattern matches {step:name} references to earlier step outputs
+var stepPattern = regexp.MustCompile(`\{step:([A-Za-z0-9_-]+)\}`)
+
+// ExpandStepOutputs replaces {step:name} references with step outputs
+// Returns a warning for each reference to a step without output, which is left as-is
+func ExpandStepOutputs(template string, outputs map[string]string) (string, []string) {
+	return expandReferences(stepPattern, template, outputs, "Step output %s is not available")
+}
+
+// StepRunFunc runs one step headless and returns its output
+// outputs holds the outputs of the steps run so far
+type StepRunFunc func(step domain.TaskStep, outputs map[string]string) (string, error)
+
+// StepResult reports the outcome of one step attempt
+type StepResult struct {
+	Step     domain.TaskStep
+	Attempt  int
+	Output   string
+	Err      error
+	Retrying bool // The step failed and will run again
+}
+
+// StepOnFailure returns the step failure policy, defaulting to stop
+func StepOnFailure(step domain.TaskStep) string {
+	if step.OnFailure == "" {
+		return StepOnFailureStop
+	}
+	return step.OnFailure
+}
+
+// RunSteps runs steps in order, applying each step's failure policy
+// report is called after every attempt. Returns the outputs by step name; a
+// failed step that continues has empty output
+func RunSteps(steps []domain.TaskStep, run StepRunFunc, report func(StepResult)) (map[string]string, error) {
+	outputs := make(map[string]string, len(steps))
+
+	for _, step := range steps {
+		attempts := 1
+		if StepOnFailure(step) == StepOnFailureRetry {
+			retries := step.Retries
+			if retries == 0 {
+				retries = 1
+			}
+			attempts += retries
+		}
+
+		var err error
+		for attempt := 1; attempt <= attempts; attempt++ {
+			var output string
+			output, err = run(step, outputs)
+			result := StepResult{
+				Step:     step,
+				Attempt:  attempt,
+				Output:   output,
+				Err:      err,
+				Retrying: err != nil && attempt < attempts,
+			}
+			if report != nil {
+				report(result)
+			}
+			if err == nil {
+				outputs[step.Name] = output
+				break
+			}
+		}
+
+		if err != nil {
+			if StepOnFailure(step) == StepOnFailureContinue {
+				outputs[step.Name] = ""
+				continue
+			}
+			return outputs, fmt.Errorf("step %q failed: %w", step.Name, err)
+		}
+	}
+
+	return outputs, nil
+}
@@ -0,0 +1,87 @@
+package engine_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/grantcarthew/start/internal/domain"
+	"github.com/grantcarthew/start/internal/engine"
+	"github.com/grantcarthew/start/test/assert"
+)
+
+func TestRunSteps_ChainsOutputs(t *testing.T) {
+	steps := []domain.TaskStep{
+		{Name: "summarise", Prompt: "Summarise the diff"},
+		{Name: "notes", Prompt: "Release notes for: {step:summarise}"},
+	}
+
+	run := func(step domain.TaskStep, outputs map[string]string) (string, error) {
+		prompt, _ := engine.ExpandStepOutputs(step.Prompt, outputs)
+		return "<" + prompt + ">", nil
+	}
+
+	outputs, err := engine.RunSteps(steps, run, nil)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "<Summarise the diff>", outputs["summarise"])
+	assert.Equal(t, "<Release notes for: <Summarise the diff>>", outputs["notes"])
+}
+
+func TestRunSteps_FailurePolicies(t *testing.T) {
+	tests := []struct {
+		name      string
+		step      domain.TaskStep
+		failures  int // Attempts that fail before one succeeds
+		wantErr   bool
+		wantRuns  int
+		wantAfter bool // The following step ran
+	}{
+		{"stop", domain.TaskStep{Name: "a"}, 1, true, 1, false},
+		{"continue", domain.TaskStep{Name: "a", OnFailure: "continue"}, 5, false, 1, true},
+		{"retry succeeds", domain.TaskStep{Name: "a", OnFailure: "retry", Retries: 2}, 2, false, 3, true},
+		{"retry exhausted", domain.TaskStep{Name: "a", OnFailure: "retry"}, 5, true, 2, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runs := 0
+			after := false
+			var reports []engine.StepResult
+
+			run := func(step domain.TaskStep, outputs map[string]string) (string, error) {
+				if step.Name == "b" {
+					after = true
+					return "done", nil
+				}
+				runs++
+				if runs <= tt.failures {
+					return "", errors.New("exit status 1")
+				}
+				return "ok", nil
+			}
+
+			outputs, err := engine.RunSteps([]domain.TaskStep{tt.step, {Name: "b"}}, run, func(r engine.StepResult) {
+				reports = append(reports, r)
+			})
+
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantRuns, runs)
+			assert.Equal(t, tt.wantAfter, after)
+			assert.True(t, len(reports) >= runs, "expected a report per attempt")
+			if tt.name == "continue" {
+				assert.Equal(t, "", outputs["a"])
+			}
+		})
+	}
+}
+
+func TestExpandStepOutputs_Missing(t *testing.T) {
+	result, warnings := engine.ExpandStepOutputs("{step:later}", map[string]string{})
+
+	assert.Equal(t, "{step:later}", result)
+	assert.Equal(t, 1, len(warnings))
+}
@@ -1,10 +1,14 @@
 package engine
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
+	"math"
 	"strings"
+	"time"
 
 	"github.com/grantcarthew/start/internal/domain"
 )
@@ -15,43 +19,79 @@ type UTDProcessor struct {
 	fs            domain.FileSystem
 	commandRunner domain.CommandRunner
 	workDir       string
+	cache         domain.OutputCache
+	fetcher       domain.URLFetcher
+	resolver      *PlaceholderResolver // Built-in placeholders in prompt templates
+	files         *FileMatcher         // Glob and directory file fields
 }
 
 // NewUTDProcessor creates a new UTD processor
-func NewUTDProcessor(fs domain.FileSystem, commandRunner domain.CommandRunner, workDir string) *UTDProcessor {
+// cache may be nil to disable command output caching, fetcher may be nil to
+// disable url fields
+func NewUTDProcessor(fs domain.FileSystem, commandRunner domain.CommandRunner, workDir string, cache domain.OutputCache, fetcher domain.URLFetcher) *UTDProcessor {
 	return &UTDProcessor{
 		fs:            fs,
 		commandRunner: commandRunner,
 		workDir:       workDir,
+		cache:         cache,
+		fetcher:       fetcher,
+		resolver:      NewPlaceholderResolver(commandRunner, workDir),
+		files:         NewFileMatcher(fs, workDir),
 	}
 }
 
+// SetWorkDir changes the directory relative file fields and built-in
+// placeholders are resolved against
+func (p *UTDProcessor) SetWorkDir(workDir string) {
+	p.workDir = workDir
+	p.resolver.SetWorkDir(workDir)
+	p.files = NewFileMatcher(p.fs, workDir)
+}
+
 // UTDInput represents the UTD fields from config
 type UTDInput struct {
 	File           string
 	Command        string
+	URL            string
 	Prompt         string
 	Shell          string
 	CommandTimeout int
+	CacheTTL       string            // Duration to reuse command output (empty = no caching)
+	CacheKeyFiles  []string          // Files whose content is part of the cache key
+	Variables      map[string]string // Values for {var:name} in file, command and prompt
+	Params         map[string]string // Values for {param:name} (tasks only, nil = not expanded)
+	FileHeader     string            // Header before each file of a glob or directory (default DefaultFileHeader)
+	MaxFiles       int               // Most files read from a glob or directory (0 = DefaultMaxFiles)
+	MaxBytes       int               // Most bytes read from a glob or directory (0 = DefaultMaxBytes)
+	Placeholders   map[string]string // Extra {name} values for the template, e.g. {instructions}
+	FileTemplate   bool              // Without a prompt the file is the template (tasks)
 }
 
 // UTDResult represents the processed result
 type UTDResult struct {
 	Content  string   // Final resolved content
 	FilePath string   // Resolved file path (if file field present)
+	Files    []string // Files read for a glob or directory file field
 	Warnings []string // Any warnings during processing
 	Skipped  bool     // True if section should be skipped
+	Cached   bool     // True if command output came from the cache
+	CachedAt time.Time
 }
 
 // Process resolves a UTD pattern into final content
 func (p *UTDProcessor) Process(input UTDInput, defaultShell string, defaultTimeout int) UTDResult {
+	return p.ProcessContext(context.Background(), input, defaultShell, defaultTimeout)
+}
+
+// ProcessContext resolves a UTD pattern, bounding any command by ctx's deadline
+func (p *UTDProcessor) ProcessContext(ctx context.Context, input UTDInput, defaultShell string, defaultTimeout int) UTDResult {
 	result := UTDResult{
 		Warnings: []string{},
 	}
 
 	// Validate: at least one field must be present
-	if input.File == "" && input.Command == "" && input.Prompt == "" {
-		result.Warnings = append(result.Warnings, "Empty section: at least one of file, command, or prompt required")
+	if input.File == "" && input.Command == "" && input.URL == "" && input.Prompt == "" {
+		result.Warnings = append(result.Warnings, "Empty section: at least one of file, command, url, or prompt required")
 		result.Skipped = true
 		return result
 	}
@@ -66,15 +106,55 @@ func (p *UTDProcessor) Process(input UTDInput, defaultShell string, defaultTimeo
 		timeout = defaultTimeout
 	}
 
+	// Expand {var:name} and {param:name} before anything else so they can name files and commands
+	// The command is run by the shell, so its variables and params are quoted for their context
+	command, warnings, commandErr := expandCommand(input.Command, input.Variables, input.Params, shell)
+	input.Command = command
+	result.Warnings = append(result.Warnings, warnings...)
+	for _, field := range []*string{&input.File, &input.URL, &input.Prompt} {
+		expanded, warnings := ExpandVariables(*field, input.Variables)
+		*field = expanded
+		result.Warnings = append(result.Warnings, warnings...)
+	}
+	if input.Params != nil {
+		for _, field := range []*string{&input.File, &input.URL, &input.Prompt} {
+			expanded, warnings := ExpandParams(*field, input.Params)
+			*field = expanded
+			result.Warnings = append(result.Warnings, warnings...)
+		}
+	}
+
 	// Process based on field combinations
 	hasFile := input.File != ""
 	hasCommand := input.Command != ""
+	hasURL := input.URL != ""
 	hasPrompt := input.Prompt != ""
 
 	// Read file if present
 	var fileContents string
 	var filePath string
-	if hasFile {
+	if hasFile && p.files.IsMulti(input.File) {
+		// Glob or directory - concatenate every match, {file} lists the paths
+		filePath = p.resolvePath(input.File)
+		matches, err := p.files.Match(input.File)
+		if err != nil {
+			result.Warnings = append(result.Warnings, err.Error())
+		}
+		set := p.readFileSet(matches, input.FileHeader, input.MaxFiles, input.MaxBytes)
+		result.Warnings = append(result.Warnings, set.Warnings...)
+		if len(set.Paths) == 0 {
+			if hasPrompt && (strings.Contains(input.Prompt, "{file}") || strings.Contains(input.Prompt, "{file_contents}")) {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("No files match: %s", filePath))
+				result.Skipped = true
+				return result
+			}
+			result.Warnings = append(result.Warnings, fmt.Sprintf("No files match: %s (ignored)", filePath))
+		}
+		fileContents = set.Content
+		result.FilePath = filePath
+		result.Files = set.Paths
+		filePath = strings.Join(set.Paths, "\n")
+	} else if hasFile {
 		filePath = p.resolvePath(input.File)
 		contents, err := p.fs.ReadFile(filePath)
 		if err != nil {
@@ -94,20 +174,55 @@ func (p *UTDProcessor) Process(input UTDInput, defaultShell string, defaultTimeo
 	// Execute command if present
 	var commandOutput string
 	if hasCommand {
-		output, err := p.commandRunner.Run(shell, input.Command, timeout)
-		if err != nil {
+		// Never run past the deadline shared by all commands in this run
+		if deadline, ok := ctx.Deadline(); ok {
+			remaining := int(math.Ceil(time.Until(deadline).Seconds()))
+			if remaining < timeout {
+				timeout = remaining
+			}
+		}
+
+		ttl, cacheKey := p.cacheLookup(input, shell, &result)
+
+		if output, storedAt, ok := p.cachedOutput(cacheKey, ttl); ok {
+			commandOutput = output
+			result.Cached = true
+			result.CachedAt = storedAt
+		} else if commandErr != nil {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("Command skipped: %v", commandErr))
+		} else if ctx.Err() != nil || timeout <= 0 {
+			result.Warnings = append(result.Warnings, "Command skipped: command deadline exceeded")
+		} else if output, err := p.commandRunner.Run(shell, input.Command, timeout); err != nil {
 			result.Warnings = append(result.Warnings, fmt.Sprintf("Command failed: %v", err))
 			commandOutput = "" // Use empty output on failure
 		} else {
 			commandOutput = strings.TrimRight(output, "\n")
+			// Only successful output is cached
+			if cacheKey != "" {
+				if err := p.cache.Set(cacheKey, commandOutput); err != nil {
+					result.Warnings = append(result.Warnings, fmt.Sprintf("Failed to cache command output: %v", err))
+				}
+			}
+		}
+	}
+
+	// Fetch url if present
+	var urlContents string
+	if hasURL {
+		contents, ok := p.fetchURL(ctx, input.URL, timeout, &result)
+		if !ok && hasPrompt && (strings.Contains(input.Prompt, "{url}") || strings.Contains(input.Prompt, "{url_contents}")) {
+			result.Skipped = true
+			return result
 		}
+		urlContents = contents
 	}
 
 	// Determine final content based on field combinations
 	switch {
 	case hasPrompt:
-		// Prompt is the template - inject file and command placeholders
-		content := input.Prompt
+		// Prompt is the template - resolve built-ins, then inject file and command placeholders
+		content, warnings := p.resolver.ResolveBuiltins(input.Prompt)
+		result.Warnings = append(result.Warnings, warnings...)
 
 		// Check if file placeholders are used
 		usesFile := strings.Contains(content, "{file}") || strings.Contains(content, "{file_contents}")
@@ -131,27 +246,53 @@ func (p *UTDProcessor) Process(input UTDInput, defaultShell string, defaultTimeo
 			return result
 		}
 
-		// Replace file placeholders
-		content = strings.ReplaceAll(content, "{file}", filePath)
-		content = strings.ReplaceAll(content, "{file_contents}", fileContents)
+		// Check if url placeholders are used
+		usesURL := strings.Contains(content, "{url}") || strings.Contains(content, "{url_contents}")
+		if hasURL && !usesURL {
+			result.Warnings = append(result.Warnings, "URL defined but not used in prompt")
+		}
+		if !hasURL && usesURL {
+			result.Warnings = append(result.Warnings, "No url defined but prompt uses {url}")
+			result.Skipped = true
+			return result
+		}
 
-		// Replace command placeholders
-		content = strings.ReplaceAll(content, "{command}", input.Command)
-		content = strings.ReplaceAll(content, "{command_output}", commandOutput)
+		// Replace file, command and url placeholders
+		result.Content = fillTemplate(content, input.Placeholders, map[string]string{
+			"file":           filePath,
+			"file_contents":  fileContents,
+			"command":        input.Command,
+			"command_output": commandOutput,
+			"url":            input.URL,
+			"url_contents":   urlContents,
+		})
 
-		result.Content = content
+	case hasURL && (hasFile || hasCommand):
+		// URL content only reaches the prompt through {url_contents}
+		result.Warnings = append(result.Warnings, "URL defined but not used (add a prompt with {url_contents})")
+		result.Content = fileContents
+		if !hasFile {
+			result.Content = commandOutput
+		}
 
 	case hasFile && hasCommand:
 		// File + command (no prompt) - check if file contains command placeholders
-		if strings.Contains(fileContents, "{command}") || strings.Contains(fileContents, "{command_output}") {
-			content := fileContents
-			content = strings.ReplaceAll(content, "{command}", input.Command)
-			content = strings.ReplaceAll(content, "{command_output}", commandOutput)
-			result.Content = content
-		} else {
+		content := fileContents
+		if input.FileTemplate {
+			content = p.expandFileTemplate(content, input, &result)
+		}
+		if !strings.Contains(content, "{command}") && !strings.Contains(content, "{command_output}") {
 			result.Warnings = append(result.Warnings, "Command defined but not used in file")
-			result.Content = fileContents
 		}
+		result.Content = fillTemplate(content, input.Placeholders, map[string]string{
+			"command":        input.Command,
+			"command_output": commandOutput,
+		})
+
+	case hasFile && input.FileTemplate:
+		// Only file, used as the template
+		content := p.expandFileTemplate(fileContents, input, &result)
+		result.Content = fillTemplate(content, input.Placeholders, nil)
 
 	case hasFile:
 		// Only file - use contents directly
@@ -160,25 +301,135 @@ func (p *UTDProcessor) Process(input UTDInput, defaultShell string, defaultTimeo
 	case hasCommand:
 		// Only command - use output directly
 		result.Content = commandOutput
+
+	case hasURL:
+		// Only url - use the fetched content directly
+		result.Content = urlContents
 	}
 
 	return result
 }
 
-// resolvePath resolves a file path (expanding ~ and making absolute)
-func (p *UTDProcessor) resolvePath(path string) string {
-	// Expand tilde
-	if strings.HasPrefix(path, "~/") {
-		home, err := os.UserHomeDir()
-		if err == nil {
-			path = filepath.Join(home, path[2:])
+// expandFileTemplate resolves {var:name}, {param:name} and built-in
+// placeholders in file contents used as the template
+func (p *UTDProcessor) expandFileTemplate(content string, input UTDInput, result *UTDResult) string {
+	content, warnings := ExpandVariables(content, input.Variables)
+	result.Warnings = append(result.Warnings, warnings...)
+	if input.Params != nil {
+		content, warnings = ExpandParams(content, input.Params)
+		result.Warnings = append(result.Warnings, warnings...)
+	}
+	content, warnings = p.resolver.ResolveBuiltins(content)
+	result.Warnings = append(result.Warnings, warnings...)
+	return content
+}
+
+// expandCommand expands {var:name} and {param:name} in a command field run by shell
+// For POSIX shells each value is escaped for its quote context (DR-044), and
+// an unquoted value that is unsafe in a shell word is an error
+func expandCommand(command string, variables, params map[string]string, shell string) (string, []string, error) {
+	expanded, warnings := ExpandVariables(command, variables)
+	values := variableValues(variables)
+	if params != nil {
+		var paramWarnings []string
+		expanded, paramWarnings = ExpandParams(expanded, params)
+		warnings = append(warnings, paramWarnings...)
+		for name, value := range params {
+			values["param:"+name] = value
 		}
 	}
+	if !IsPOSIXShell(shell) {
+		return expanded, warnings, nil
+	}
 
-	// Make absolute if relative
-	if !filepath.IsAbs(path) {
-		path = filepath.Join(p.workDir, path)
+	expanded, err := substituteQuoted(command, values, shell)
+	var quoteErr *QuoteError
+	if errors.As(err, &quoteErr) {
+		return command, warnings, fmt.Errorf("%s holds %q, quote it in the command as '%s'",
+			quoteErr.Placeholder, truncateValue(quoteErr.Value, 40), quoteErr.Placeholder)
 	}
+	return expanded, warnings, err
+}
+
+// fillTemplate replaces {name} placeholders in a single pass, so inserted
+// content (file, command output, url body, instructions) is never scanned
+// for placeholders itself
+func fillTemplate(template string, extra, values map[string]string) string {
+	var pairs []string
+	for _, m := range []map[string]string{extra, values} {
+		for name, value := range m {
+			pairs = append(pairs, "{"+name+"}", value)
+		}
+	}
+	if len(pairs) == 0 {
+		return template
+	}
+	return strings.NewReplacer(pairs...).Replace(template)
+}
+
+// fetchURL fetches a url field within the command timeout and ctx's deadline
+// Returns false when no content is available, live or cached
+func (p *UTDProcessor) fetchURL(ctx context.Context, url string, timeout int, result *UTDResult) (string, bool) {
+	if p.fetcher == nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("URL not fetched: %s (url fields are not available here)", url))
+		return "", false
+	}
+
+	fetched, err := p.fetcher.Fetch(ctx, url, time.Duration(timeout)*time.Second)
+	if err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("URL fetch failed: %v", err))
+		return "", false
+	}
+	if fetched.Stale {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("Using cached copy of %s from %s: %v", url, fetched.FetchedAt.Format("2006-01-02 15:04"), fetched.StaleErr))
+	}
+	return fetched.Body, true
+}
 
-	return path
+// cacheLookup returns the cache TTL and key for a command, or an empty key
+// when caching is disabled for this input
+func (p *UTDProcessor) cacheLookup(input UTDInput, shell string, result *UTDResult) (time.Duration, string) {
+	if p.cache == nil || input.CacheTTL == "" {
+		return 0, ""
+	}
+
+	ttl, err := time.ParseDuration(input.CacheTTL)
+	if err != nil || ttl <= 0 {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("Invalid cache_ttl %q (caching disabled)", input.CacheTTL))
+		return 0, ""
+	}
+
+	return ttl, p.cacheKey(input.Command, shell, input.CacheKeyFiles)
+}
+
+// cachedOutput returns a still valid cached output for key
+func (p *UTDProcessor) cachedOutput(key string, ttl time.Duration) (string, time.Time, bool) {
+	if key == "" {
+		return "", time.Time{}, false
+	}
+	return p.cache.Get(key, ttl)
+}
+
+// cacheKey hashes the command, shell, working directory and key file contents
+func (p *UTDProcessor) cacheKey(command, shell string, keyFiles []string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00", command, shell, p.workDir)
+
+	for _, file := range keyFiles {
+		path := p.resolvePath(file)
+		contents, err := p.fs.ReadFile(path)
+		if err != nil {
+			// A missing key file is part of the key, creating it invalidates
+			fmt.Fprintf(h, "%s\x00missing\x00", path)
+			continue
+		}
+		fmt.Fprintf(h, "%s\x00%x\x00", path, sha256.Sum256(contents))
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// resolvePath resolves a file path (expanding ~ and making absolute)
+func (p *UTDProcessor) resolvePath(path string) string {
+	return resolveWorkPath(p.workDir, path)
 }
@@ -1,8 +1,12 @@
 package engine
 
 import (
+	"errors"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/grantcarthew/start/internal/domain"
 	"github.com/grantcarthew/start/test/mocks"
 )
 
@@ -11,7 +15,7 @@ func TestUTDProcessor_FileOnly(t *testing.T) {
 	fs.Files["/test/role.md"] = "Role content"
 
 	cmdRunner := mocks.NewMockCommandRunner()
-	processor := NewUTDProcessor(fs, cmdRunner, "/test")
+	processor := NewUTDProcessor(fs, cmdRunner, "/test", nil, nil)
 
 	input := UTDInput{
 		File: "role.md",
@@ -35,7 +39,7 @@ func TestUTDProcessor_CommandOnly(t *testing.T) {
 	cmdRunner := mocks.NewMockCommandRunner()
 	cmdRunner.SetOutput("command output", nil)
 
-	processor := NewUTDProcessor(fs, cmdRunner, "/test")
+	processor := NewUTDProcessor(fs, cmdRunner, "/test", nil, nil)
 
 	input := UTDInput{
 		Command: "git status",
@@ -54,7 +58,7 @@ func TestUTDProcessor_CommandOnly(t *testing.T) {
 func TestUTDProcessor_PromptOnly(t *testing.T) {
 	fs := mocks.NewMockFileSystem()
 	cmdRunner := mocks.NewMockCommandRunner()
-	processor := NewUTDProcessor(fs, cmdRunner, "/test")
+	processor := NewUTDProcessor(fs, cmdRunner, "/test", nil, nil)
 
 	input := UTDInput{
 		Prompt: "Static prompt text",
@@ -75,7 +79,7 @@ func TestUTDProcessor_FileWithPrompt(t *testing.T) {
 	fs.Files["/test/role.md"] = "Role content"
 
 	cmdRunner := mocks.NewMockCommandRunner()
-	processor := NewUTDProcessor(fs, cmdRunner, "/test")
+	processor := NewUTDProcessor(fs, cmdRunner, "/test", nil, nil)
 
 	input := UTDInput{
 		File:   "role.md",
@@ -98,7 +102,7 @@ func TestUTDProcessor_CommandWithPrompt(t *testing.T) {
 	cmdRunner := mocks.NewMockCommandRunner()
 	cmdRunner.SetOutput("git output", nil)
 
-	processor := NewUTDProcessor(fs, cmdRunner, "/test")
+	processor := NewUTDProcessor(fs, cmdRunner, "/test", nil, nil)
 
 	input := UTDInput{
 		Command: "git status",
@@ -123,7 +127,7 @@ func TestUTDProcessor_AllThreeFields(t *testing.T) {
 	cmdRunner := mocks.NewMockCommandRunner()
 	cmdRunner.SetOutput("cmd output", nil)
 
-	processor := NewUTDProcessor(fs, cmdRunner, "/test")
+	processor := NewUTDProcessor(fs, cmdRunner, "/test", nil, nil)
 
 	input := UTDInput{
 		File:    "doc.md",
@@ -145,7 +149,7 @@ func TestUTDProcessor_AllThreeFields(t *testing.T) {
 func TestUTDProcessor_MissingFile(t *testing.T) {
 	fs := mocks.NewMockFileSystem()
 	cmdRunner := mocks.NewMockCommandRunner()
-	processor := NewUTDProcessor(fs, cmdRunner, "/test")
+	processor := NewUTDProcessor(fs, cmdRunner, "/test", nil, nil)
 
 	input := UTDInput{
 		File:   "missing.md",
@@ -165,7 +169,7 @@ func TestUTDProcessor_MissingFile(t *testing.T) {
 func TestUTDProcessor_EmptySection(t *testing.T) {
 	fs := mocks.NewMockFileSystem()
 	cmdRunner := mocks.NewMockCommandRunner()
-	processor := NewUTDProcessor(fs, cmdRunner, "/test")
+	processor := NewUTDProcessor(fs, cmdRunner, "/test", nil, nil)
 
 	input := UTDInput{}
 
@@ -183,7 +187,7 @@ func TestUTDProcessor_TildeExpansion(t *testing.T) {
 	fs := mocks.NewMockFileSystem()
 	// Mock will handle tilde expansion in resolvePath
 	cmdRunner := mocks.NewMockCommandRunner()
-	processor := NewUTDProcessor(fs, cmdRunner, "/test")
+	processor := NewUTDProcessor(fs, cmdRunner, "/test", nil, nil)
 
 	input := UTDInput{
 		File: "~/role.md",
@@ -196,3 +200,379 @@ func TestUTDProcessor_TildeExpansion(t *testing.T) {
 		t.Errorf("Expected tilde to be expanded, got %q", result.FilePath)
 	}
 }
+
+func TestUTDProcessor_CommandCache(t *testing.T) {
+	fs := mocks.NewMockFileSystem()
+	fs.Files["/test/go.sum"] = "v1"
+
+	cmdRunner := mocks.NewMockCommandRunner()
+	cmdRunner.SetOutput("first run", nil)
+	cache := mocks.NewMockOutputCache()
+
+	processor := NewUTDProcessor(fs, cmdRunner, "/test", cache, nil)
+
+	input := UTDInput{
+		Command:       "go list -m all",
+		CacheTTL:      "10m",
+		CacheKeyFiles: []string{"go.sum"},
+	}
+
+	result := processor.Process(input, "bash", 30)
+	if result.Cached {
+		t.Errorf("Expected first run not to be cached")
+	}
+	if result.Content != "first run" {
+		t.Errorf("Expected 'first run', got %q", result.Content)
+	}
+
+	// Second run is served from the cache
+	cmdRunner.SetOutput("second run", nil)
+	result = processor.Process(input, "bash", 30)
+	if !result.Cached {
+		t.Errorf("Expected second run to be cached")
+	}
+	if result.Content != "first run" {
+		t.Errorf("Expected cached 'first run', got %q", result.Content)
+	}
+
+	// Changing a key file invalidates the cached output
+	fs.Files["/test/go.sum"] = "v2"
+	result = processor.Process(input, "bash", 30)
+	if result.Cached {
+		t.Errorf("Expected cache miss after key file changed")
+	}
+	if result.Content != "second run" {
+		t.Errorf("Expected 'second run', got %q", result.Content)
+	}
+}
+
+func TestUTDProcessor_CommandCacheExpired(t *testing.T) {
+	fs := mocks.NewMockFileSystem()
+	cmdRunner := mocks.NewMockCommandRunner()
+	cmdRunner.SetOutput("fresh", nil)
+	cache := mocks.NewMockOutputCache()
+
+	processor := NewUTDProcessor(fs, cmdRunner, "/test", cache, nil)
+	input := UTDInput{Command: "date", CacheTTL: "1m"}
+
+	processor.Process(input, "bash", 30)
+	for key := range cache.StoredAt {
+		cache.Entries[key] = "stale"
+		cache.StoredAt[key] = time.Now().Add(-2 * time.Minute)
+	}
+
+	result := processor.Process(input, "bash", 30)
+	if result.Cached {
+		t.Errorf("Expected expired entry not to be used")
+	}
+	if result.Content != "fresh" {
+		t.Errorf("Expected 'fresh', got %q", result.Content)
+	}
+}
+
+func TestUTDProcessor_CommandCacheFailureNotStored(t *testing.T) {
+	fs := mocks.NewMockFileSystem()
+	cmdRunner := mocks.NewMockCommandRunner()
+	cmdRunner.SetOutput("", errors.New("exit status 1"))
+	cache := mocks.NewMockOutputCache()
+
+	processor := NewUTDProcessor(fs, cmdRunner, "/test", cache, nil)
+	processor.Process(UTDInput{Command: "false", CacheTTL: "1h"}, "bash", 30)
+
+	if len(cache.Entries) != 0 {
+		t.Errorf("Expected failed command output not to be cached, got %d entries", len(cache.Entries))
+	}
+}
+
+func TestUTDProcessor_CommandCacheInvalidTTL(t *testing.T) {
+	fs := mocks.NewMockFileSystem()
+	cmdRunner := mocks.NewMockCommandRunner()
+	cmdRunner.SetOutput("output", nil)
+	cache := mocks.NewMockOutputCache()
+
+	processor := NewUTDProcessor(fs, cmdRunner, "/test", cache, nil)
+	result := processor.Process(UTDInput{Command: "ls", CacheTTL: "soon"}, "bash", 30)
+
+	if result.Content != "output" {
+		t.Errorf("Expected 'output', got %q", result.Content)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "Invalid cache_ttl") {
+		t.Errorf("Expected invalid cache_ttl warning, got %v", result.Warnings)
+	}
+	if len(cache.Entries) != 0 {
+		t.Errorf("Expected nothing cached with invalid TTL")
+	}
+}
+
+func TestUTDProcessor_PromptBuiltins(t *testing.T) {
+	fs := mocks.NewMockFileSystem()
+	cmdRunner := mocks.NewMockCommandRunner()
+	cmdRunner.Outputs["git rev-parse --abbrev-ref HEAD"] = "feature/x\n"
+
+	processor := NewUTDProcessor(fs, cmdRunner, "/test", nil, nil)
+	result := processor.Process(UTDInput{
+		Prompt: "Branch {git_branch} in {cwd}, token {env:START_TEST_UNSET_TOKEN}",
+	}, "bash", 30)
+
+	if result.Content != "Branch feature/x in /test, token " {
+		t.Errorf("Expected built-ins resolved, got %q", result.Content)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "START_TEST_UNSET_TOKEN") {
+		t.Errorf("Expected warning for unset variable, got %v", result.Warnings)
+	}
+}
+
+func TestUTDProcessor_SetWorkDir(t *testing.T) {
+	fs := mocks.NewMockFileSystem()
+	fs.Files["/project/notes.md"] = "Project notes"
+	cmdRunner := mocks.NewMockCommandRunner()
+
+	processor := NewUTDProcessor(fs, cmdRunner, "/test", nil, nil)
+	processor.SetWorkDir("/project")
+	result := processor.Process(UTDInput{
+		File:   "notes.md",
+		Prompt: "{file_contents} in {cwd}",
+	}, "bash", 30)
+
+	if result.Content != "Project notes in /project" {
+		t.Errorf("Expected content from the new directory, got %q", result.Content)
+	}
+	if result.FilePath != "/project/notes.md" {
+		t.Errorf("Expected file resolved against the new directory, got %q", result.FilePath)
+	}
+}
+
+func TestUTDProcessor_Variables(t *testing.T) {
+	fs := mocks.NewMockFileSystem()
+	fs.Files["/test/docs/style.md"] = "Style guide"
+	cmdRunner := mocks.NewMockCommandRunner()
+
+	processor := NewUTDProcessor(fs, cmdRunner, "/test", nil, nil)
+	result := processor.Process(UTDInput{
+		File:      "{var:style_guide_path}",
+		Prompt:    "Project {var:jira_project}, {var:unknown}:\n{file_contents}",
+		Variables: map[string]string{"style_guide_path": "docs/style.md", "jira_project": "OPS"},
+	}, "bash", 30)
+
+	if result.Content != "Project OPS, {var:unknown}:\nStyle guide" {
+		t.Errorf("Expected variables expanded, got %q", result.Content)
+	}
+	if result.FilePath != "/test/docs/style.md" {
+		t.Errorf("Expected file path from variable, got %q", result.FilePath)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "{var:unknown}") {
+		t.Errorf("Expected warning for undefined variable, got %v", result.Warnings)
+	}
+}
+
+func TestUTDProcessor_CommandVariablesQuoted(t *testing.T) {
+	fs := mocks.NewMockFileSystem()
+	cmdRunner := mocks.NewMockCommandRunner()
+	cmdRunner.Outputs[`grep -r 'TODO list' .`] = "found"
+	cmdRunner.Outputs[`grep -r 'it'\''s; rm -rf ~' .`] = "escaped"
+	processor := NewUTDProcessor(fs, cmdRunner, "/test", nil, nil)
+
+	tests := []struct {
+		name    string
+		command string
+		value   string
+		want    string
+		warning string
+	}{
+		{"spaced value", "grep -r '{var:pattern}' .", "TODO list", "found", ""},
+		{"quote and semicolon", "grep -r '{var:pattern}' .", "it's; rm -rf ~", "escaped", ""},
+		{"unquoted spaced value", "grep -r {var:pattern} .", "TODO list", "", "Command skipped: {var:pattern} holds \"TODO list\""},
+	}
+
+	for _, tt := range tests {
+		result := processor.Process(UTDInput{
+			Command:   tt.command,
+			Variables: map[string]string{"pattern": tt.value},
+		}, "bash", 30)
+
+		if result.Content != tt.want {
+			t.Errorf("%s: Content = %q, want %q", tt.name, result.Content, tt.want)
+		}
+		if tt.warning != "" && (len(result.Warnings) == 0 || !strings.Contains(result.Warnings[0], tt.warning)) {
+			t.Errorf("%s: expected warning containing %q, got %v", tt.name, tt.warning, result.Warnings)
+		}
+	}
+}
+
+func TestUTDProcessor_CommandParamsQuoted(t *testing.T) {
+	fs := mocks.NewMockFileSystem()
+	cmdRunner := mocks.NewMockCommandRunner()
+	cmdRunner.Outputs[`wc -l "my notes.md"`] = "3"
+	processor := NewUTDProcessor(fs, cmdRunner, "/test", nil, nil)
+
+	result := processor.Process(UTDInput{
+		Command: `wc -l "{param:file}"`,
+		Params:  map[string]string{"file": "my notes.md"},
+	}, "bash", 30)
+	if result.Content != "3" {
+		t.Errorf("Content = %q, want %q", result.Content, "3")
+	}
+
+	result = processor.Process(UTDInput{
+		Command: "wc -l {param:file}",
+		Params:  map[string]string{"file": "my notes.md"},
+	}, "bash", 30)
+	if result.Content != "" {
+		t.Errorf("unquoted param: Content = %q, want empty", result.Content)
+	}
+	if len(result.Warnings) == 0 || !strings.Contains(result.Warnings[0], "Command skipped: {param:file}") {
+		t.Errorf("unquoted param: expected command skipped warning, got %v", result.Warnings)
+	}
+}
+
+func TestUTDProcessor_GlobFiles(t *testing.T) {
+	fs := mocks.NewMockFileSystem()
+	fs.Files["/test/docs/adr/002-cache.md"] = "Cache decision\n"
+	fs.Files["/test/docs/adr/001-cli.md"] = "CLI decision\n"
+	fs.Files["/test/docs/adr/notes.txt"] = "not an ADR"
+	fs.Files["/test/docs/adr/old/000-draft.md"] = "Draft"
+
+	processor := NewUTDProcessor(fs, mocks.NewMockCommandRunner(), "/test", nil, nil)
+
+	result := processor.Process(UTDInput{File: "docs/adr/*.md"}, "bash", 30)
+
+	if result.Skipped {
+		t.Fatalf("Expected not skipped, got warnings: %v", result.Warnings)
+	}
+	want := "--- docs/adr/001-cli.md ---\nCLI decision\n\n--- docs/adr/002-cache.md ---\nCache decision"
+	if result.Content != want {
+		t.Errorf("Content mismatch\nGot:  %q\nWant: %q", result.Content, want)
+	}
+	if len(result.Files) != 2 {
+		t.Errorf("Expected 2 files, got %v", result.Files)
+	}
+
+	// ** crosses directories, {file} lists the matched paths
+	result = processor.Process(UTDInput{
+		File:       "docs/**/*.md",
+		Prompt:     "Files:\n{file}",
+		FileHeader: "## {path}",
+	}, "bash", 30)
+	want = "Files:\n/test/docs/adr/001-cli.md\n/test/docs/adr/002-cache.md\n/test/docs/adr/old/000-draft.md"
+	if result.Content != want {
+		t.Errorf("Content mismatch\nGot:  %q\nWant: %q", result.Content, want)
+	}
+}
+
+func TestUTDProcessor_DirectoryRespectsGitignore(t *testing.T) {
+	fs := mocks.NewMockFileSystem()
+	fs.Files["/test/.gitignore"] = "*.log\nbuild/\n!keep.log\n"
+	fs.Files["/test/proto/api.proto"] = "service API {}"
+	fs.Files["/test/proto/debug.log"] = "noise"
+	fs.Files["/test/proto/keep.log"] = "kept"
+	fs.Files["/test/proto/build/gen.proto"] = "generated"
+	fs.Files["/test/proto/vendor/.gitignore"] = "*.proto\n"
+	fs.Files["/test/proto/vendor/dep.proto"] = "vendored"
+
+	processor := NewUTDProcessor(fs, mocks.NewMockCommandRunner(), "/test", nil, nil)
+
+	result := processor.Process(UTDInput{File: "proto"}, "bash", 30)
+
+	var got []string
+	for _, path := range result.Files {
+		got = append(got, strings.TrimPrefix(path, "/test/proto/"))
+	}
+	want := "api.proto keep.log vendor/.gitignore"
+	if strings.Join(got, " ") != want {
+		t.Errorf("Files mismatch\nGot:  %v\nWant: %s", got, want)
+	}
+}
+
+func TestUTDProcessor_FileSetLimits(t *testing.T) {
+	fs := mocks.NewMockFileSystem()
+	fs.Files["/test/notes/a.md"] = "aaaa"
+	fs.Files["/test/notes/b.md"] = "bbbb"
+	fs.Files["/test/notes/c.md"] = "cccc"
+	fs.Files["/test/notes/d.bin"] = "\x00\x01"
+
+	processor := NewUTDProcessor(fs, mocks.NewMockCommandRunner(), "/test", nil, nil)
+
+	result := processor.Process(UTDInput{File: "notes", MaxFiles: 2}, "bash", 30)
+	if len(result.Files) != 2 || !strings.Contains(strings.Join(result.Warnings, "\n"), "max_files") {
+		t.Errorf("Expected 2 files and a max_files warning, got %v %v", result.Files, result.Warnings)
+	}
+
+	result = processor.Process(UTDInput{File: "notes", MaxBytes: 10}, "bash", 30)
+	if len(result.Files) != 2 || !strings.Contains(strings.Join(result.Warnings, "\n"), "max_bytes") {
+		t.Errorf("Expected 2 files and a max_bytes warning, got %v %v", result.Files, result.Warnings)
+	}
+
+	result = processor.Process(UTDInput{File: "notes/*.bin"}, "bash", 30)
+	if len(result.Files) != 0 || !strings.Contains(strings.Join(result.Warnings, "\n"), "Skipped 1 binary files") {
+		t.Errorf("Expected the binary file skipped, got %v %v", result.Files, result.Warnings)
+	}
+
+	// No matches skips a prompt that needs them
+	result = processor.Process(UTDInput{File: "notes/*.go", Prompt: "{file_contents}"}, "bash", 30)
+	if !result.Skipped {
+		t.Errorf("Expected skipped when nothing matches, got %q", result.Content)
+	}
+}
+
+func TestUTDProcessor_URL(t *testing.T) {
+	fs := mocks.NewMockFileSystem()
+	fetcher := mocks.NewMockURLFetcher()
+	fetcher.SetBody("https://example.com/standards.md", "# Standards")
+
+	processor := NewUTDProcessor(fs, mocks.NewMockCommandRunner(), "/test", nil, fetcher)
+
+	// URL only uses the body directly
+	result := processor.Process(UTDInput{URL: "https://example.com/standards.md"}, "bash", 30)
+	if result.Skipped || result.Content != "# Standards" {
+		t.Errorf("Expected '# Standards', got %q (skipped %v, warnings %v)", result.Content, result.Skipped, result.Warnings)
+	}
+
+	// Prompt placeholders
+	result = processor.Process(UTDInput{
+		URL:    "https://example.com/standards.md",
+		Prompt: "From {url}:\n{url_contents}",
+	}, "bash", 30)
+	if result.Content != "From https://example.com/standards.md:\n# Standards" {
+		t.Errorf("Unexpected content %q", result.Content)
+	}
+
+	// A failed fetch skips a section whose prompt needs the content
+	result = processor.Process(UTDInput{
+		URL:    "https://example.com/missing.md",
+		Prompt: "{url_contents}",
+	}, "bash", 30)
+	if !result.Skipped {
+		t.Errorf("Expected skipped when url cannot be fetched")
+	}
+	if len(result.Warnings) == 0 || !strings.Contains(result.Warnings[0], "URL fetch failed") {
+		t.Errorf("Expected fetch failure warning, got %v", result.Warnings)
+	}
+}
+
+func TestUTDProcessor_URLStaleCache(t *testing.T) {
+	fetcher := mocks.NewMockURLFetcher()
+	fetcher.Responses["https://example.com/a.md"] = domain.FetchedURL{
+		Body:      "cached",
+		FetchedAt: time.Date(2025, 1, 2, 3, 4, 0, 0, time.UTC),
+		Stale:     true,
+		StaleErr:  errors.New("connection refused"),
+	}
+
+	processor := NewUTDProcessor(mocks.NewMockFileSystem(), mocks.NewMockCommandRunner(), "/test", nil, fetcher)
+
+	result := processor.Process(UTDInput{URL: "https://example.com/a.md"}, "bash", 30)
+	if result.Skipped || result.Content != "cached" {
+		t.Errorf("Expected cached content, got %q (skipped %v)", result.Content, result.Skipped)
+	}
+	want := "Using cached copy of https://example.com/a.md from 2025-01-02 03:04: connection refused"
+	if len(result.Warnings) != 1 || result.Warnings[0] != want {
+		t.Errorf("Expected stale warning %q, got %v", want, result.Warnings)
+	}
+
+	// Without a fetcher the url is reported, not fetched
+	processor = NewUTDProcessor(mocks.NewMockFileSystem(), mocks.NewMockCommandRunner(), "/test", nil, nil)
+	result = processor.Process(UTDInput{URL: "https://example.com/a.md", Prompt: "{url_contents}"}, "bash", 30)
+	if !result.Skipped {
+		t.Errorf("Expected skipped without a fetcher")
+	}
+}
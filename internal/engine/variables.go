@@ -0,0 +1,64 @@
+package engine
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// variablePattern matches {var:name} references to [variables]
+var variablePattern = regexp.MustCompile(`\{var:([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// ExpandVariables replaces {var:name} references with their values
+// Returns a warning for each reference to an undefined variable, which is left as-is
+func ExpandVariables(template string, variables map[string]string) (string, []string) {
+	return expandReferences(variablePattern, template, variables, "Variable %s is not defined")
+}
+
+// expandReferences replaces pattern matches with the value named by the first
+// submatch, leaving undefined references as-is with one warning each
+func expandReferences(pattern *regexp.Regexp, template string, values map[string]string, warning string) (string, []string) {
+	var warnings []string
+	seen := make(map[string]bool)
+
+	result := pattern.ReplaceAllStringFunc(template, func(ref string) string {
+		name := pattern.FindStringSubmatch(ref)[1]
+		if value, ok := values[name]; ok {
+			return value
+		}
+		if !seen[name] {
+			warnings = append(warnings, fmt.Sprintf(warning, ref))
+			seen[name] = true
+		}
+		return ref
+	})
+
+	return result, warnings
+}
+
+// variableValues returns variables keyed as placeholders ("var:name") for the resolver
+func variableValues(variables map[string]string) map[string]string {
+	values := make(map[string]string, len(variables))
+	for name, value := range variables {
+		values["var:"+name] = value
+	}
+	return values
+}
+
+// ParseVariableOverrides parses --var key=value flags
+func ParseVariableOverrides(flags []string) (map[string]string, error) {
+	return parseAssignments("--var", flags)
+}
+
+// parseAssignments parses repeated key=value flag values
+func parseAssignments(flagName string, flags []string) (map[string]string, error) {
+	values := make(map[string]string, len(flags))
+	for _, flag := range flags {
+		name, value, ok := strings.Cut(flag, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid %s %q: expected key=value", flagName, flag)
+		}
+		values[name] = value
+	}
+	return values, nil
+}
@@ -0,0 +1,40 @@
+package engine_test
+
+import (
+	"testing"
+
+	"github.com/grantcarthew/start/internal/engine"
+	"github.com/grantcarthew/start/test/assert"
+)
+
+func TestExpandVariables(t *testing.T) {
+	vars := map[string]string{"jira_project": "OPS", "style_guide_path": "docs/style.md"}
+
+	result, warnings := engine.ExpandVariables("Project {var:jira_project}, see {var:style_guide_path}", vars)
+
+	assert.Equal(t, "Project OPS, see docs/style.md", result)
+	assert.Equal(t, 0, len(warnings))
+}
+
+func TestExpandVariables_UndefinedWarnsOnce(t *testing.T) {
+	result, warnings := engine.ExpandVariables("{var:missing} and {var:missing}", nil)
+
+	assert.Equal(t, "{var:missing} and {var:missing}", result)
+	assert.Equal(t, 1, len(warnings))
+	assert.Contains(t, warnings[0], "{var:missing}")
+}
+
+func TestParseVariableOverrides(t *testing.T) {
+	overrides, err := engine.ParseVariableOverrides([]string{"project=OPS", "query=a=b", "empty="})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "OPS", overrides["project"])
+	assert.Equal(t, "a=b", overrides["query"])
+	assert.Equal(t, "", overrides["empty"])
+
+	_, err = engine.ParseVariableOverrides([]string{"novalue"})
+	assert.Error(t, err)
+
+	_, err = engine.ParseVariableOverrides([]string{"=value"})
+	assert.Error(t, err)
+}
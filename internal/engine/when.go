@@ -0,0 +1,90 @@
+package engine
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/grantcarthew/start/internal/domain"
+)
+
+// CheckWhen evaluates the when predicates of a role, context or task against
+// the working directory
+// Returns false and the first unmet predicate as the reason
+func (p *UTDProcessor) CheckWhen(when *domain.When, defaultShell string, defaultTimeout int) (bool, string) {
+	if when == nil {
+		return true, ""
+	}
+
+	if when.FileExists != "" {
+		path := resolveWorkPath(p.workDir, when.FileExists)
+		if !p.fs.Exists(path) || p.fs.IsDir(path) {
+			return false, fmt.Sprintf("file_exists: %s not found", when.FileExists)
+		}
+	}
+
+	if when.DirExists != "" {
+		if !p.fs.IsDir(resolveWorkPath(p.workDir, when.DirExists)) {
+			return false, fmt.Sprintf("dir_exists: %s not found", when.DirExists)
+		}
+	}
+
+	if when.EnvSet != "" {
+		if _, ok := os.LookupEnv(when.EnvSet); !ok {
+			return false, fmt.Sprintf("env_set: %s is not set", when.EnvSet)
+		}
+	}
+
+	if when.GitRepo != nil {
+		inRepo := p.inGitRepo()
+		if *when.GitRepo && !inRepo {
+			return false, "git_repo: not in a git repository"
+		}
+		if !*when.GitRepo && inRepo {
+			return false, "git_repo: in a git repository"
+		}
+	}
+
+	if when.PathGlob != "" {
+		if !matchWorkDir(when.PathGlob, p.workDir) {
+			return false, fmt.Sprintf("path_glob: %s does not match %s", p.workDir, when.PathGlob)
+		}
+	}
+
+	// Commands last, the other predicates are cheap
+	if when.CommandSucceeds != "" {
+		if _, err := p.commandRunner.Run(defaultShell, when.CommandSucceeds, defaultTimeout); err != nil {
+			return false, fmt.Sprintf("command_succeeds: %s failed", when.CommandSucceeds)
+		}
+	}
+
+	return true, ""
+}
+
+// inGitRepo reports whether the working directory is inside a git repository
+func (p *UTDProcessor) inGitRepo() bool {
+	for dir := p.workDir; ; dir = filepath.Dir(dir) {
+		if p.fs.Exists(filepath.Join(dir, ".git")) {
+			return true
+		}
+		if filepath.Dir(dir) == dir {
+			return false
+		}
+	}
+}
+
+// matchWorkDir reports whether a path_glob pattern matches the working directory
+// ~ is expanded, and a relative pattern matches the end of the path
+func matchWorkDir(pattern, workDir string) bool {
+	if strings.HasPrefix(pattern, "~/") {
+		if home, err := os.UserHomeDir(); err == nil {
+			pattern = filepath.Join(home, pattern[2:])
+		}
+	}
+	pattern = filepath.ToSlash(pattern)
+	if !strings.HasPrefix(pattern, "/") {
+		pattern = "**/" + pattern
+	}
+	return matchGlob(strings.TrimSuffix(pattern, "/"), filepath.ToSlash(workDir))
+}
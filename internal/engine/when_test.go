@@ -0,0 +1,91 @@
+package engine
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/start/internal/domain"
+	"github.com/grantcarthew/start/test/mocks"
+)
+
+func TestCheckWhen(t *testing.T) {
+	fs := mocks.NewMockFileSystem()
+	fs.Files["/repo/.git"] = "gitdir: elsewhere"
+	fs.Files["/repo/app/AGENTS.md"] = "# Agents"
+	fs.Files["/repo/app/docs/adr/001.md"] = "ADR"
+
+	t.Setenv("START_WHEN_TEST", "1")
+
+	yes, no := true, false
+	tests := []struct {
+		name   string
+		when   *domain.When
+		want   bool
+		reason string
+	}{
+		{"nil", nil, true, ""},
+		{"file exists", &domain.When{FileExists: "AGENTS.md"}, true, ""},
+		{"file missing", &domain.When{FileExists: "CLAUDE.md"}, false, "file_exists: CLAUDE.md not found"},
+		{"file is a directory", &domain.When{FileExists: "docs"}, false, "file_exists: docs not found"},
+		{"dir exists", &domain.When{DirExists: "docs/adr"}, true, ""},
+		{"dir missing", &domain.When{DirExists: "src"}, false, "dir_exists: src not found"},
+		{"env set", &domain.When{EnvSet: "START_WHEN_TEST"}, true, ""},
+		{"env unset", &domain.When{EnvSet: "START_WHEN_UNSET"}, false, "env_set: START_WHEN_UNSET is not set"},
+		{"git repo from subdirectory", &domain.When{GitRepo: &yes}, true, ""},
+		{"outside git repo", &domain.When{GitRepo: &no}, false, "git_repo: in a git repository"},
+		{"path glob absolute", &domain.When{PathGlob: "/repo/**"}, true, ""},
+		{"path glob relative", &domain.When{PathGlob: "app"}, true, ""},
+		{"path glob mismatch", &domain.When{PathGlob: "/work/**"}, false, "path_glob: /repo/app does not match /work/**"},
+		{"all must hold", &domain.When{FileExists: "AGENTS.md", EnvSet: "START_WHEN_UNSET"}, false, "env_set: START_WHEN_UNSET is not set"},
+	}
+
+	processor := NewUTDProcessor(fs, mocks.NewMockCommandRunner(), "/repo/app", nil, nil)
+	for _, tt := range tests {
+		ok, reason := processor.CheckWhen(tt.when, "bash", 30)
+		if ok != tt.want || reason != tt.reason {
+			t.Errorf("%s: CheckWhen = (%v, %q), want (%v, %q)", tt.name, ok, reason, tt.want, tt.reason)
+		}
+	}
+}
+
+func TestCheckWhen_CommandSucceeds(t *testing.T) {
+	cmdRunner := mocks.NewMockCommandRunner()
+	processor := NewUTDProcessor(mocks.NewMockFileSystem(), cmdRunner, "/test", nil, nil)
+	when := &domain.When{CommandSucceeds: "command -v go"}
+
+	if ok, reason := processor.CheckWhen(when, "bash", 30); !ok {
+		t.Errorf("Expected command to succeed, got %q", reason)
+	}
+
+	cmdRunner.SetOutput("", errors.New("exit status 1"))
+	ok, reason := processor.CheckWhen(when, "bash", 30)
+	if ok || !strings.Contains(reason, "command -v go failed") {
+		t.Errorf("Expected command failure, got (%v, %q)", ok, reason)
+	}
+}
+
+func TestContextLoader_WhenSkips(t *testing.T) {
+	fs := mocks.NewMockFileSystem()
+	fs.Files["/test/notes.md"] = "Notes"
+
+	loader := NewContextLoader(NewUTDProcessor(fs, mocks.NewMockCommandRunner(), "/test", nil, nil))
+	contexts := map[string]domain.Context{
+		"agents": {File: "AGENTS.md", When: &domain.When{FileExists: "AGENTS.md"}},
+		"notes":  {File: "notes.md", When: &domain.When{FileExists: "notes.md"}},
+	}
+
+	loaded := loader.LoadContexts(contexts, []string{"agents", "notes"}, CommandTypeInteractive, "bash", 30, LoadOptions{})
+	if len(loaded) != 2 {
+		t.Fatalf("Expected 2 contexts, got %d", len(loaded))
+	}
+	if !loaded[0].Skipped || loaded[0].SkipReason != "file_exists: AGENTS.md not found" {
+		t.Errorf("Expected agents skipped by when, got %+v", loaded[0])
+	}
+	if len(loaded[0].Warnings) != 0 {
+		t.Errorf("Expected no file warnings for a when skip, got %v", loaded[0].Warnings)
+	}
+	if loaded[1].Skipped || loaded[1].Content != "Notes" {
+		t.Errorf("Expected notes loaded, got %+v", loaded[1])
+	}
+}
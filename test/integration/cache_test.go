@@ -0,0 +1,62 @@
+package integration
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/start/test/assert"
+)
+
+// TestCache_CommandOutput tests that cached command output is reused and can be cleared
+func TestCache_CommandOutput(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ensureStartBinary(t)
+	home := writeConfigFiles(t, map[string]string{
+		"contexts.toml": `[contexts.stamp]
+command = "date +%s%N"
+cache_ttl = "1h"
+`,
+	})
+
+	run := func(args ...string) string {
+		cmd := exec.Command(getBinaryPath(t), args...)
+		cmd.Env = []string{"HOME=" + home, "PATH=" + os.Getenv("PATH")}
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Logf("Command output: %s", string(output))
+		}
+		assert.NoError(t, err)
+		return string(output)
+	}
+
+	// contentOf returns the context body between the separators
+	contentOf := func(output string) string {
+		parts := strings.Split(output, "─────────────────────────────────────────────────")
+		if len(parts) < 3 {
+			t.Fatalf("Unexpected show output: %s", output)
+		}
+		return strings.TrimSpace(parts[1])
+	}
+
+	first := run("show", "context", "stamp")
+	if strings.Contains(first, "Command output: cached") {
+		t.Errorf("Expected first run to execute the command, got: %s", first)
+	}
+
+	second := run("show", "context", "stamp")
+	assert.Contains(t, second, "Command output: cached")
+	assert.Equal(t, contentOf(first), contentOf(second))
+
+	output := run("cache", "clear")
+	assert.Contains(t, output, "Command output cache cleared")
+
+	third := run("show", "context", "stamp")
+	if strings.Contains(third, "Command output: cached") {
+		t.Errorf("Expected cache miss after clear, got: %s", third)
+	}
+}
@@ -0,0 +1,280 @@
+package integration
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/grantcarthew/start/test/assert"
+)
+
+// headlessTestConfig returns a config whose smith agent prints the prompt and exits with exitCode
+func headlessTestConfig(t *testing.T, exitCode string) string {
+	t.Helper()
+
+	smithBinPath, err := filepath.Abs(filepath.Join("..", "..", "bin", "smith"))
+	assert.NoError(t, err)
+
+	// Without SMITH_OUTPUT_DIR smith prints its last argument to stdout
+	return writeConfigFiles(t, map[string]string{
+		"agents.toml": `[agents.smith]
+bin = "` + smithBinPath + `"
+command = "{bin} --interactive --model {model} '{prompt}'"
+headless_command = "{bin} --model {model} '{prompt}'; exit ` + exitCode + `"
+default_model = "test"
+
+  [agents.smith.models]
+  test = "test-model-123"
+`,
+		"config.toml": `[settings]
+default_agent = "smith"
+default_role = "test-role"
+shell = "bash"
+`,
+		"roles.toml": `[roles.test-role]
+prompt = "You are a test assistant."
+`,
+	})
+}
+
+// TestHeadless_Capture tests that --capture runs the agent as a child and prints its output
+func TestHeadless_Capture(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ensureSmithBinary(t)
+	home := headlessTestConfig(t, "0")
+
+	cmd := exec.Command(getBinaryPath(t), "prompt", "--capture", "hello from capture")
+	cmd.Env = []string{
+		"HOME=" + home,
+		"PATH=" + os.Getenv("PATH"),
+	}
+	output, err := cmd.Output()
+	assert.NoError(t, err)
+	assert.Equal(t, "hello from capture\n", string(output))
+}
+
+// TestHeadless_OutputFileAndExitCode tests --output and exit code pass-through
+func TestHeadless_OutputFileAndExitCode(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ensureSmithBinary(t)
+	home := headlessTestConfig(t, "3")
+	outputFile := filepath.Join(home, "answer.md")
+
+	cmd := exec.Command(getBinaryPath(t), "--output", outputFile, "write this down")
+	cmd.Env = []string{
+		"HOME=" + home,
+		"PATH=" + os.Getenv("PATH"),
+	}
+	output, err := cmd.CombinedOutput()
+
+	exitErr, ok := err.(*exec.ExitError)
+	assert.True(t, ok, "expected non-zero exit, got: "+string(output))
+	if ok {
+		assert.Equal(t, 3, exitErr.ExitCode())
+	}
+	assert.Contains(t, string(output), "Agent output written to")
+	assert.NotContains(t, string(output), "Usage:")
+
+	data, err := os.ReadFile(outputFile)
+	assert.NoError(t, err)
+	assert.Contains(t, string(data), "write this down")
+}
+
+// TestHeadless_FanOut tests that --agents runs each agent and writes <outdir>/<agent>.md
+func TestHeadless_FanOut(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ensureSmithBinary(t)
+	smithBinPath, err := filepath.Abs(filepath.Join("..", "..", "bin", "smith"))
+	assert.NoError(t, err)
+
+	agent := func(name, exitCode string) string {
+		return `[agents.` + name + `]
+bin = "` + smithBinPath + `"
+command = "{bin} --interactive --model {model} '{prompt}'"
+headless_command = "{bin} --model {model} '{prompt}'; exit ` + exitCode + `"
+default_model = "test"
+
+  [agents.` + name + `.models]
+  test = "test-model-123"
+
+`
+	}
+	// quick reports the model it was given, nomodel has no default to fall back to
+	models := `[agents.quick]
+bin = "` + smithBinPath + `"
+command = "{bin} --interactive --model {model} '{prompt}'"
+headless_command = "{bin} --model {model} '{prompt}' > /dev/null; echo {model}"
+default_model = "test"
+
+  [agents.quick.models]
+  test = "test-model-123"
+  fast = "fast-model-456"
+
+[agents.nomodel]
+bin = "` + smithBinPath + `"
+command = "{bin} --model {model} '{prompt}'"
+
+  [agents.nomodel.models]
+  test = "test-model-123"
+`
+	home := writeConfigFiles(t, map[string]string{
+		"agents.toml": agent("smith", "0") + agent("jones", "0") + agent("broken", "2") + models,
+		"config.toml": `[settings]
+default_agent = "smith"
+default_role = "test-role"
+shell = "bash"
+`,
+		"roles.toml": `[roles.test-role]
+prompt = "You are a test assistant."
+`,
+		"tasks.toml": `[tasks.review]
+prompt = "Review: {instructions}"
+`,
+	})
+	outDir := filepath.Join(home, "results")
+
+	run := func(args ...string) (string, error) {
+		cmd := exec.Command(getBinaryPath(t), args...)
+		cmd.Env = []string{
+			"HOME=" + home,
+			"PATH=" + os.Getenv("PATH"),
+		}
+		output, err := cmd.CombinedOutput()
+		return string(output), err
+	}
+
+	output, err := run("task", "review", "--agents", "smith,jones", "--outdir", outDir, "the diff")
+	assert.NoError(t, err)
+	assert.Contains(t, output, "Running 2 agents: smith, jones")
+	assert.Contains(t, output, "✓ smith")
+	assert.Contains(t, output, "✓ jones")
+	for _, name := range []string{"smith", "jones"} {
+		data, err := os.ReadFile(filepath.Join(outDir, name+".md"))
+		assert.NoError(t, err)
+		assert.Equal(t, "Review: the diff\n", string(data))
+	}
+
+	t.Run("failed agent", func(t *testing.T) {
+		output, err := run("--agents", "smith,broken", "--outdir", outDir, "compare this")
+		assert.Error(t, err)
+		assert.Contains(t, output, "✗ broken")
+		assert.Contains(t, output, "exit 2")
+		assert.Contains(t, output, "1 of 2 agents failed")
+		assert.NotContains(t, output, "Usage:")
+	})
+
+	t.Run("duplicates after prefix resolution", func(t *testing.T) {
+		output, err := run("--agents", "jo,jones", "--outdir", outDir, "compare this")
+		assert.NoError(t, err)
+		assert.Contains(t, output, "Running 1 agents: jones")
+	})
+
+	t.Run("model per agent", func(t *testing.T) {
+		output, err := run("--agents", "quick,smith", "--model", "fast", "--outdir", outDir, "compare this")
+		assert.NoError(t, err)
+		assert.Contains(t, output, "✓ quick")
+		assert.Contains(t, output, "✓ smith")
+		// fast is only one of quick's models, smith keeps its default
+		data, err := os.ReadFile(filepath.Join(outDir, "quick.md"))
+		assert.NoError(t, err)
+		assert.Equal(t, "fast-model-456\n", string(data))
+	})
+
+	t.Run("model failure fails one agent", func(t *testing.T) {
+		output, err := run("--agents", "nomodel,smith", "--outdir", outDir, "compare this")
+		assert.Error(t, err)
+		assert.Contains(t, output, "✗ nomodel")
+		assert.Contains(t, output, "no default model for agent \"nomodel\"")
+		assert.Contains(t, output, "✓ smith")
+		assert.Contains(t, output, "1 of 2 agents failed")
+	})
+
+	t.Run("single agent flags rejected", func(t *testing.T) {
+		output, err := run("--agents", "smith,jones", "--capture", "compare this")
+		assert.Error(t, err)
+		assert.Contains(t, output, "--capture cannot be used with --agents")
+	})
+}
+
+// TestHeadless_Replay tests that replay keeps the headless output file and the
+// context flags of the recorded invocation
+func TestHeadless_Replay(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ensureSmithBinary(t)
+	home := headlessTestConfig(t, "0")
+	writeConfigFilesIn(t, home, map[string]string{
+		"contexts.toml": `[contexts.notes]
+prompt = "project notes"
+required = true
+
+[contexts.extra]
+prompt = "extra notes"
+`,
+	})
+	outputFile := filepath.Join(home, "answer.md")
+
+	run := func(args ...string) (string, error) {
+		cmd := exec.Command(getBinaryPath(t), args...)
+		cmd.Env = []string{
+			"HOME=" + home,
+			"XDG_STATE_HOME=" + filepath.Join(home, "state"),
+			"PATH=" + os.Getenv("PATH"),
+		}
+		output, err := cmd.CombinedOutput()
+		return string(output), err
+	}
+	answer := func() string {
+		data, err := os.ReadFile(outputFile)
+		assert.NoError(t, err)
+		assert.NoError(t, os.Remove(outputFile))
+		return string(data)
+	}
+
+	output, err := run("prompt", "--output", outputFile, "--no-context", "notes", "--context", "extra", "the question")
+	if err != nil {
+		t.Logf("Command output: %s", output)
+	}
+	assert.NoError(t, err)
+	recorded := answer()
+	assert.Contains(t, recorded, "extra notes")
+	assert.NotContains(t, recorded, "project notes")
+
+	// The recorded command is written to the recorded output file again
+	output, err = run("replay", "last")
+	assert.NoError(t, err)
+	assert.Contains(t, output, "Agent output written to")
+	assert.Equal(t, recorded, answer())
+
+	// --reread applies the recorded context flags, so nothing has changed
+	output, err = run("replay", "last", "--reread")
+	assert.NoError(t, err)
+	assert.NotContains(t, output, "Context")
+	assert.Equal(t, recorded, answer())
+
+	// --capture overrides the recorded output file
+	output, err = run("replay", "last", "--capture")
+	assert.NoError(t, err)
+	assert.Contains(t, output, "the question")
+	_, err = os.Stat(outputFile)
+	assert.True(t, os.IsNotExist(err), "output file should not be written")
+
+	// An interactive entry only runs headless through --reread
+	_, err = run("prompt", "interactive question")
+	assert.NoError(t, err)
+	output, err = run("replay", "last", "--capture")
+	assert.Error(t, err)
+	assert.Contains(t, output, "use --reread to run it headless")
+}
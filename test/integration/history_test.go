@@ -0,0 +1,195 @@
+package integration
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/grantcarthew/start/internal/domain"
+	"github.com/grantcarthew/start/test/assert"
+)
+
+// TestHistory_RecordAndReplay tests that launches are recorded and can be replayed
+func TestHistory_RecordAndReplay(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ensureSmithBinary(t)
+
+	smithBinPath, err := filepath.Abs(filepath.Join("..", "..", "bin", "smith"))
+	assert.NoError(t, err)
+
+	home := t.TempDir()
+	notesPath := filepath.Join(home, "notes.md")
+	err = os.WriteFile(notesPath, []byte("original notes"), 0644)
+	assert.NoError(t, err)
+
+	home = writeConfigFilesIn(t, home, map[string]string{
+		"agents.toml": `[agents.smith]
+bin = "` + smithBinPath + `"
+command = "{bin} --model {model} '{prompt}'"
+default_model = "test"
+
+  [agents.smith.models]
+  test = "test-model-123"
+`,
+		"config.toml": `[settings]
+default_agent = "smith"
+default_role = "test-role"
+`,
+		"roles.toml": `[roles.test-role]
+prompt = "You are a test assistant."
+`,
+		"contexts.toml": `[contexts.notes]
+file = "` + notesPath + `"
+prompt = "{file_contents}"
+required = true
+`,
+	})
+
+	outputDir := filepath.Join(home, "smith-output")
+	err = os.MkdirAll(outputDir, 0755)
+	assert.NoError(t, err)
+
+	startBinPath, err := filepath.Abs(getBinaryPath(t))
+	assert.NoError(t, err)
+
+	run := func(args ...string) (string, error) {
+		cmd := exec.Command(startBinPath, args...)
+		cmd.Dir = home
+		cmd.Env = []string{
+			"HOME=" + home,
+			"XDG_STATE_HOME=" + filepath.Join(home, "state"),
+			"SMITH_OUTPUT_DIR=" + outputDir,
+			"PATH=" + os.Getenv("PATH"),
+		}
+		output, err := cmd.CombinedOutput()
+		return string(output), err
+	}
+
+	output, err := run("prompt", "first run")
+	if err != nil {
+		t.Logf("Command output: %s", output)
+	}
+	assert.NoError(t, err)
+
+	// History records the invocation
+	output, err = run("history", "--json")
+	assert.NoError(t, err)
+	var entries []domain.HistoryEntry
+	err = json.Unmarshal([]byte(output), &entries)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(entries))
+	assert.Equal(t, "prompt", entries[0].Mode)
+	assert.Equal(t, "smith", entries[0].Agent)
+	assert.Equal(t, "first run", entries[0].UserPrompt)
+	assert.Equal(t, 1, len(entries[0].Contexts))
+
+	// Replay runs the recorded command, old context content included
+	err = os.WriteFile(notesPath, []byte("updated notes"), 0644)
+	assert.NoError(t, err)
+	output, err = run("replay", "last")
+	if err != nil {
+		t.Logf("Command output: %s", output)
+	}
+	assert.NoError(t, err)
+	promptData, err := os.ReadFile(filepath.Join(outputDir, "prompt.md"))
+	assert.NoError(t, err)
+	assert.Equal(t, "original notes\n\nfirst run", string(promptData))
+
+	// Replay with --reread picks up the new content and reports the change
+	output, err = run("replay", entries[0].ID, "--reread")
+	assert.NoError(t, err)
+	assert.Contains(t, output, "Context notes changed since recording")
+	promptData, err = os.ReadFile(filepath.Join(outputDir, "prompt.md"))
+	assert.NoError(t, err)
+	assert.Equal(t, "updated notes\n\nfirst run", string(promptData))
+
+	output, err = run("history")
+	assert.NoError(t, err)
+	assert.Contains(t, output, entries[0].ID)
+}
+
+// TestHistory_ReplayInRecordedDirectory tests that --reread resolves relative
+// files and {cwd} against the recorded directory, not the current one
+func TestHistory_ReplayInRecordedDirectory(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ensureSmithBinary(t)
+
+	smithBinPath, err := filepath.Abs(filepath.Join("..", "..", "bin", "smith"))
+	assert.NoError(t, err)
+
+	home := writeConfigFiles(t, map[string]string{
+		"agents.toml": `[agents.smith]
+bin = "` + smithBinPath + `"
+command = "{bin} --model {model} '{prompt}'"
+default_model = "test"
+
+  [agents.smith.models]
+  test = "test-model-123"
+`,
+		"config.toml": `[settings]
+default_agent = "smith"
+default_role = "test-role"
+`,
+		"roles.toml": `[roles.test-role]
+prompt = "You are a test assistant."
+`,
+		"contexts.toml": `[contexts.notes]
+file = "notes.md"
+prompt = "{file_contents} in {cwd}"
+required = true
+`,
+	})
+
+	project := filepath.Join(home, "project")
+	outputDir := filepath.Join(home, "smith-output")
+	for _, dir := range []string{project, outputDir} {
+		assert.NoError(t, os.MkdirAll(dir, 0755))
+	}
+	assert.NoError(t, os.WriteFile(filepath.Join(project, "notes.md"), []byte("project notes"), 0644))
+
+	startBinPath, err := filepath.Abs(getBinaryPath(t))
+	assert.NoError(t, err)
+
+	run := func(dir string, args ...string) (string, error) {
+		cmd := exec.Command(startBinPath, args...)
+		cmd.Dir = dir
+		cmd.Env = []string{
+			"HOME=" + home,
+			"XDG_STATE_HOME=" + filepath.Join(home, "state"),
+			"SMITH_OUTPUT_DIR=" + outputDir,
+			"PATH=" + os.Getenv("PATH"),
+		}
+		output, err := cmd.CombinedOutput()
+		return string(output), err
+	}
+
+	output, err := run(project, "prompt", "first run")
+	if err != nil {
+		t.Logf("Command output: %s", output)
+	}
+	assert.NoError(t, err)
+
+	// Replayed from elsewhere, the context is read from the project again
+	output, err = run(home, "replay", "last", "--reread")
+	if err != nil {
+		t.Logf("Command output: %s", output)
+	}
+	assert.NoError(t, err)
+	promptData, err := os.ReadFile(filepath.Join(outputDir, "prompt.md"))
+	assert.NoError(t, err)
+	assert.Equal(t, "project notes in "+project+"\n\nfirst run", string(promptData))
+
+	// A recorded directory that is gone is an error
+	assert.NoError(t, os.RemoveAll(project))
+	output, err = run(home, "replay", "last")
+	assert.Error(t, err)
+	assert.Contains(t, output, "cannot change to recorded directory")
+}
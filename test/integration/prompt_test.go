@@ -0,0 +1,125 @@
+package integration
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/grantcarthew/start/test/assert"
+)
+
+// TestPrompt_RequiredContextsOnly tests that start prompt includes only required contexts
+func TestPrompt_RequiredContextsOnly(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ensureSmithBinary(t)
+
+	smithBinPath, err := filepath.Abs(filepath.Join("..", "..", "bin", "smith"))
+	assert.NoError(t, err)
+
+	home := writeConfigFiles(t, map[string]string{
+		"agents.toml": `[agents.smith]
+bin = "` + smithBinPath + `"
+command = "{bin} --model {model} '{prompt}'"
+default_model = "test"
+
+  [agents.smith.models]
+  test = "test-model-123"
+  other = "other-model-456"
+`,
+		"config.toml": `[settings]
+default_agent = "smith"
+default_role = "test-role"
+`,
+		"roles.toml": `[roles.test-role]
+prompt = "You are a test assistant."
+`,
+		"contexts.toml": `[contexts.required-doc]
+prompt = "Required context"
+required = true
+
+[contexts.optional-doc]
+prompt = "Optional context"
+`,
+	})
+
+	outputDir := filepath.Join(home, "smith-output")
+	err = os.MkdirAll(outputDir, 0755)
+	assert.NoError(t, err)
+
+	cmd := exec.Command(getBinaryPath(t), "prompt", "--model", "other", "what's this?")
+	cmd.Env = []string{
+		"HOME=" + home,
+		"SMITH_OUTPUT_DIR=" + outputDir,
+		"PATH=" + os.Getenv("PATH"),
+	}
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Logf("Command output: %s", string(output))
+	}
+	assert.NoError(t, err)
+
+	promptData, err := os.ReadFile(filepath.Join(outputDir, "prompt.md"))
+	assert.NoError(t, err)
+	assert.Equal(t, "Required context\n\nwhat's this?", string(promptData))
+
+	argsData, err := os.ReadFile(filepath.Join(outputDir, "args.txt"))
+	assert.NoError(t, err)
+	assert.Contains(t, string(argsData), "other-model-456")
+}
+
+// TestPrompt_StdinDelivery tests that prompt_delivery = "stdin" feeds the prompt to the agent's stdin
+func TestPrompt_StdinDelivery(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ensureSmithBinary(t)
+
+	smithBinPath, err := filepath.Abs(filepath.Join("..", "..", "bin", "smith"))
+	assert.NoError(t, err)
+
+	// The shell reads stdin with cat and passes it to smith as the last argument
+	home := writeConfigFiles(t, map[string]string{
+		"agents.toml": `[agents.smith]
+bin = "` + smithBinPath + `"
+command = "{bin} --model {model} \"$(cat)\""
+prompt_delivery = "stdin"
+default_model = "test"
+
+  [agents.smith.models]
+  test = "test-model-123"
+`,
+		"config.toml": `[settings]
+default_agent = "smith"
+default_role = "test-role"
+shell = "bash"
+`,
+		"roles.toml": `[roles.test-role]
+prompt = "You are a test assistant."
+`,
+	})
+
+	outputDir := filepath.Join(home, "smith-output")
+	err = os.MkdirAll(outputDir, 0755)
+	assert.NoError(t, err)
+
+	cmd := exec.Command(getBinaryPath(t), "prompt", "it's a 'quoted' $HOME prompt")
+	cmd.Env = []string{
+		"HOME=" + home,
+		"SMITH_OUTPUT_DIR=" + outputDir,
+		"PATH=" + os.Getenv("PATH"),
+	}
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Logf("Command output: %s", string(output))
+	}
+	assert.NoError(t, err)
+
+	promptData, err := os.ReadFile(filepath.Join(outputDir, "prompt.md"))
+	assert.NoError(t, err)
+	assert.Equal(t, "it's a 'quoted' $HOME prompt", string(promptData))
+}
@@ -0,0 +1,560 @@
+package integration
+
+import (
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/grantcarthew/start/test/assert"
+)
+
+// writeConfigFiles writes global config files into a temp home directory
+// Returns the home directory path
+func writeConfigFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	return writeConfigFilesIn(t, t.TempDir(), files)
+}
+
+// writeConfigFilesIn writes config files into home/.config/start and returns home
+func writeConfigFilesIn(t *testing.T, tempDir string, files map[string]string) string {
+	t.Helper()
+
+	configDir := filepath.Join(tempDir, ".config", "start")
+	err := os.MkdirAll(configDir, 0755)
+	assert.NoError(t, err)
+
+	for name, content := range files {
+		err = os.WriteFile(filepath.Join(configDir, name), []byte(content), 0644)
+		assert.NoError(t, err)
+	}
+
+	return tempDir
+}
+
+// showTestConfig is a minimal config with one required and one optional context
+var showTestConfig = map[string]string{
+	"agents.toml": `[agents.smith]
+bin = "smith"
+command = "{bin} --model {model} --role '{role}' '{prompt}'"
+default_model = "test"
+
+  [agents.smith.models]
+  test = "test-model-123"
+`,
+	"config.toml": `[settings]
+default_agent = "smith"
+default_role = "test-role"
+`,
+	"roles.toml": `[roles.test-role]
+prompt = "You're a test assistant."
+`,
+	"contexts.toml": `[contexts.greeting]
+command = "echo hello"
+required = true
+
+[contexts.extra]
+prompt = "Optional context"
+`,
+	"tasks.toml": `[tasks.review]
+alias = "rv"
+prompt = "Review with: {instructions}"
+`,
+}
+
+// TestShow_Preview tests that start show prints the command without executing it
+func TestShow_Preview(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ensureStartBinary(t)
+	home := writeConfigFiles(t, showTestConfig)
+
+	cmd := exec.Command(getBinaryPath(t), "show", "hello world")
+	cmd.Env = []string{"HOME=" + home, "PATH=" + os.Getenv("PATH")}
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Logf("Command output: %s", string(output))
+	}
+	assert.NoError(t, err)
+
+	out := string(output)
+	assert.Contains(t, out, "PREVIEW - NOT EXECUTING")
+	assert.Contains(t, out, "Agent: smith (model: test-model-123)")
+	assert.Contains(t, out, "greeting")
+	assert.Contains(t, out, "extra")
+	assert.Contains(t, out, `smith --model test-model-123 --role 'You'\''re a test assistant.'`)
+	assert.Contains(t, out, "PREVIEW ONLY: Agent not executed")
+}
+
+// TestShow_TaskJSON tests the JSON preview for a task
+func TestShow_TaskJSON(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ensureStartBinary(t)
+	home := writeConfigFiles(t, showTestConfig)
+
+	cmd := exec.Command(getBinaryPath(t), "show", "task", "rv", "security", "--json")
+	cmd.Env = []string{"HOME=" + home, "PATH=" + os.Getenv("PATH")}
+	output, err := cmd.Output()
+	assert.NoError(t, err)
+
+	var preview struct {
+		Mode     string `json:"mode"`
+		Agent    string `json:"agent"`
+		ModelID  string `json:"model_id"`
+		Task     string `json:"task"`
+		Prompt   string `json:"prompt"`
+		Command  string `json:"command"`
+		Contexts []struct {
+			Name     string `json:"name"`
+			Required bool   `json:"required"`
+		} `json:"contexts"`
+	}
+	err = json.Unmarshal(output, &preview)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "task", preview.Mode)
+	assert.Equal(t, "smith", preview.Agent)
+	assert.Equal(t, "test-model-123", preview.ModelID)
+	assert.Equal(t, "review", preview.Task)
+	assert.Equal(t, "hello\n\nReview with: security", preview.Prompt)
+
+	// Tasks load required contexts only
+	assert.Equal(t, 1, len(preview.Contexts))
+	assert.Equal(t, "greeting", preview.Contexts[0].Name)
+}
+
+// TestShow_ContentViewers tests the role, context and agent viewers
+func TestShow_ContentViewers(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ensureStartBinary(t)
+	files := map[string]string{}
+	for name, content := range showTestConfig {
+		files[name] = content
+	}
+	files["agents.toml"] += `
+[agents.smith-fast]
+extends = "smith"
+default_model = "test"
+`
+	home := writeConfigFiles(t, files)
+
+	tests := []struct {
+		name   string
+		args   []string
+		expect string
+	}{
+		{"role", []string{"show", "role"}, "You're a test assistant."},
+		{"context", []string{"show", "context", "greeting"}, "hello"},
+		{"context prefix", []string{"show", "context", "greet"}, "Context: greeting"},
+		{"agent", []string{"show", "agent"}, "test → test-model-123"},
+		{"agent variant global scope", []string{"show", "agent", "smith-fast", "--scope", "global"}, "test → test-model-123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := exec.Command(getBinaryPath(t), tt.args...)
+			cmd.Env = []string{"HOME=" + home, "PATH=" + os.Getenv("PATH")}
+			output, err := cmd.CombinedOutput()
+			if err != nil {
+				t.Logf("Command output: %s", string(output))
+			}
+			assert.NoError(t, err)
+			assert.Contains(t, string(output), tt.expect)
+		})
+	}
+
+	// A misspelled context name gets a suggestion, as roles and agents do
+	cmd := exec.Command(getBinaryPath(t), "show", "context", "greetnig")
+	cmd.Env = []string{"HOME=" + home, "PATH=" + os.Getenv("PATH")}
+	output, err := cmd.CombinedOutput()
+	assert.Error(t, err)
+	assert.Contains(t, string(output), `did you mean "greeting"?`)
+}
+
+// TestShow_PromptLayout tests that the preview renders the configured prompt layout
+func TestShow_PromptLayout(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ensureStartBinary(t)
+
+	files := make(map[string]string)
+	for name, content := range showTestConfig {
+		files[name] = content
+	}
+	files["config.toml"] += `
+[settings.prompt_layout]
+style = "xml"
+footer = "Be brief."
+`
+	home := writeConfigFiles(t, files)
+
+	cmd := exec.Command(getBinaryPath(t), "show", "prompt", "explain", "--json")
+	cmd.Env = []string{"HOME=" + home, "PATH=" + os.Getenv("PATH")}
+	output, err := cmd.Output()
+	assert.NoError(t, err)
+
+	var preview struct {
+		Prompt string `json:"prompt"`
+		Layout string `json:"prompt_layout"`
+	}
+	err = json.Unmarshal(output, &preview)
+	assert.NoError(t, err)
+
+	assert.Equal(t, "xml, footer", preview.Layout)
+	assert.Equal(t, "<context name=\"greeting\">\nhello\n</context>\n\n<prompt>\nexplain\n</prompt>\n\nBe brief.", preview.Prompt)
+}
+
+// TestShow_TaskVariables tests [variables] expansion and --var overrides in a task preview
+func TestShow_TaskVariables(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ensureStartBinary(t)
+
+	files := make(map[string]string)
+	for name, content := range showTestConfig {
+		files[name] = content
+	}
+	files["config.toml"] += `
+[variables]
+jira_project = "CORE"
+`
+	files["tasks.toml"] += `
+[tasks.jira]
+prompt = "Triage {var:jira_project} issues"
+`
+	home := writeConfigFiles(t, files)
+
+	prompt := func(args ...string) string {
+		cmd := exec.Command(getBinaryPath(t), append([]string{"show", "task", "jira", "--json"}, args...)...)
+		cmd.Env = []string{"HOME=" + home, "PATH=" + os.Getenv("PATH")}
+		output, err := cmd.Output()
+		assert.NoError(t, err)
+
+		var preview struct {
+			Prompt string `json:"prompt"`
+		}
+		err = json.Unmarshal(output, &preview)
+		assert.NoError(t, err)
+		return preview.Prompt
+	}
+
+	assert.Equal(t, "hello\n\nTriage CORE issues", prompt())
+	assert.Equal(t, "hello\n\nTriage OPS issues", prompt("--var", "jira_project=OPS"))
+}
+
+// TestShow_GlobContext tests glob file sources in a preview and in config context test
+func TestShow_GlobContext(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ensureStartBinary(t)
+	files := map[string]string{}
+	for name, content := range showTestConfig {
+		files[name] = content
+	}
+	files["contexts.toml"] = `[contexts.adrs]
+file = "docs/adr/**/*.md"
+file_header = "## {path}"
+required = true
+`
+	home := writeConfigFiles(t, files)
+
+	project := t.TempDir()
+	for name, content := range map[string]string{
+		".gitignore":             "drafts/\n",
+		"docs/adr/001-cli.md":    "Use cobra",
+		"docs/adr/002-toml.md":   "Use TOML",
+		"docs/adr/drafts/003.md": "Undecided",
+		"docs/adr/README.txt":    "Index",
+	} {
+		path := filepath.Join(project, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+
+	startBinPath, err := filepath.Abs(getBinaryPath(t))
+	assert.NoError(t, err)
+
+	run := func(args ...string) string {
+		cmd := exec.Command(startBinPath, args...)
+		cmd.Dir = project
+		cmd.Env = []string{"HOME=" + home, "PATH=" + os.Getenv("PATH")}
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Logf("Command output: %s", string(output))
+		}
+		assert.NoError(t, err)
+		return string(output)
+	}
+
+	var preview struct {
+		Prompt string `json:"prompt"`
+	}
+	err = json.Unmarshal([]byte(run("show", "task", "review", "--json")), &preview)
+	assert.NoError(t, err)
+	assert.Equal(t, "## docs/adr/001-cli.md\nUse cobra\n\n## docs/adr/002-toml.md\nUse TOML\n\nReview with: None", preview.Prompt)
+
+	out := run("config", "context", "test", "adrs")
+	assert.Contains(t, out, "✓ 2 files match")
+	assert.Contains(t, out, "docs/adr/001-cli.md")
+}
+
+// TestShow_WhenContext tests that a context with unmet when predicates is skipped with its reason
+func TestShow_WhenContext(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ensureStartBinary(t)
+	files := map[string]string{}
+	for name, content := range showTestConfig {
+		files[name] = content
+	}
+	files["contexts.toml"] = `[contexts.agents]
+file = "AGENTS.md"
+required = true
+
+[contexts.agents.when]
+file_exists = "AGENTS.md"
+`
+	home := writeConfigFiles(t, files)
+
+	startBinPath, err := filepath.Abs(getBinaryPath(t))
+	assert.NoError(t, err)
+
+	run := func(dir string) string {
+		cmd := exec.Command(startBinPath, "show", "task", "review")
+		cmd.Dir = dir
+		cmd.Env = []string{"HOME=" + home, "PATH=" + os.Getenv("PATH")}
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Logf("Command output: %s", string(output))
+		}
+		assert.NoError(t, err)
+		return string(output)
+	}
+
+	// Without AGENTS.md the context is skipped with its reason, not a missing file warning
+	out := run(t.TempDir())
+	assert.Contains(t, out, "skipped, when file_exists: AGENTS.md not found")
+	assert.NotContains(t, out, "File not found")
+
+	project := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(project, "AGENTS.md"), []byte("Agent notes"), 0644))
+	out = run(project)
+	assert.Contains(t, out, "✓ agents")
+	assert.Contains(t, out, "Agent notes")
+}
+
+// TestShow_ContextSelection tests the context selection flags and task contexts
+func TestShow_ContextSelection(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ensureStartBinary(t)
+	files := map[string]string{}
+	for name, content := range showTestConfig {
+		files[name] = content
+	}
+	files["contexts.toml"] = `[contexts.greeting]
+prompt = "Hello"
+required = true
+
+[contexts.owasp]
+prompt = "OWASP top ten"
+tags = ["security"]
+
+[contexts.style]
+prompt = "Style guide"
+`
+	files["tasks.toml"] = `[tasks.review]
+prompt = "Review with: {instructions}"
+contexts = ["style"]
+`
+	home := writeConfigFiles(t, files)
+
+	contextNames := func(args ...string) []string {
+		cmd := exec.Command(getBinaryPath(t), append(args, "--json")...)
+		cmd.Env = []string{"HOME=" + home, "PATH=" + os.Getenv("PATH")}
+		output, err := cmd.Output()
+		assert.NoError(t, err)
+
+		var preview struct {
+			Contexts []struct {
+				Name string `json:"name"`
+			} `json:"contexts"`
+		}
+		assert.NoError(t, json.Unmarshal(output, &preview))
+		var names []string
+		for _, ctx := range preview.Contexts {
+			names = append(names, ctx.Name)
+		}
+		return names
+	}
+
+	assert.Equal(t, "greeting", strings.Join(contextNames("show", "prompt", "hi"), ","))
+	assert.Equal(t, "greeting,owasp", strings.Join(contextNames("show", "prompt", "hi", "--context-tag", "security"), ","))
+	assert.Equal(t, "greeting,style", strings.Join(contextNames("show", "task", "review"), ","))
+	assert.Equal(t, "style", strings.Join(contextNames("show", "task", "review", "--no-context", "greeting"), ","))
+	assert.Equal(t, "owasp", strings.Join(contextNames("show", "--no-contexts", "--context", "owasp"), ","))
+
+	// Unknown names are rejected
+	cmd := exec.Command(getBinaryPath(t), "show", "--context", "missing")
+	cmd.Env = []string{"HOME=" + home, "PATH=" + os.Getenv("PATH")}
+	output, err := cmd.CombinedOutput()
+	assert.Error(t, err)
+	assert.Contains(t, string(output), `context "missing" not found`)
+}
+
+// TestShow_NameResolution tests prefix matching, ambiguity and suggestions for flag values and task names
+func TestShow_NameResolution(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ensureStartBinary(t)
+	files := map[string]string{}
+	for name, content := range showTestConfig {
+		files[name] = content
+	}
+	files["agents.toml"] = `[agents.smith]
+bin = "smith"
+command = "{bin} --model {model} --role '{role}' '{prompt}'"
+default_model = "test"
+
+  [agents.smith.models]
+  test = "test-model-123"
+  large = "large-model-456"
+`
+	files["roles.toml"] = `[roles.test-role]
+prompt = "You're a test assistant."
+
+[roles.code-reviewer]
+prompt = "You review code."
+
+[roles.code-writer]
+prompt = "You write code."
+`
+	files["tasks.toml"] = `[tasks.review]
+alias = "rv"
+prompt = "Review with: {instructions}"
+
+[tasks.refactor]
+prompt = "Refactor: {instructions}"
+`
+	home := writeConfigFiles(t, files)
+
+	run := func(args ...string) (string, error) {
+		cmd := exec.Command(getBinaryPath(t), args...)
+		cmd.Env = []string{"HOME=" + home, "PATH=" + os.Getenv("PATH")}
+		output, err := cmd.CombinedOutput()
+		return string(output), err
+	}
+
+	// Unique prefixes resolve to the full name
+	output, err := run("show", "--agent", "smi", "--role", "code-r", "--model", "lar", "hi")
+	assert.NoError(t, err)
+	assert.Contains(t, output, "smith")
+	assert.Contains(t, output, "code-reviewer")
+	assert.Contains(t, output, "large-model-456")
+
+	output, err = run("show", "task", "rev", "now")
+	assert.NoError(t, err)
+	assert.Contains(t, output, "Review with: now")
+
+	// Ambiguous prefixes list the matches when stdin is not a terminal
+	output, err = run("show", "--role", "code", "hi")
+	assert.Error(t, err)
+	assert.Contains(t, output, `multiple roles match "code": code-reviewer, code-writer`)
+
+	output, err = run("show", "task", "re")
+	assert.Error(t, err)
+	assert.Contains(t, output, `multiple tasks match "re": refactor, review`)
+
+	// Unknown names suggest the closest match
+	output, err = run("show", "--agent", "simth", "hi")
+	assert.Error(t, err)
+	assert.Contains(t, output, `agent "simth" not found in configuration, did you mean "smith"?`)
+
+	// Unknown models pass through with a warning
+	output, err = run("show", "--model", "other-model", "hi")
+	assert.NoError(t, err)
+	assert.Contains(t, output, `Model "other-model" not in config, passing through to CLI`)
+	assert.Contains(t, output, "--model other-model")
+}
+
+// TestShow_ModelCatalog tests model discovery through models_command and the --model checks it enables
+func TestShow_ModelCatalog(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ensureStartBinary(t)
+	files := map[string]string{}
+	for name, content := range showTestConfig {
+		files[name] = content
+	}
+	files["agents.toml"] = `[agents.smith]
+bin = "smith"
+command = "{bin} --model {model} --role '{role}' '{prompt}'"
+default_model = "test"
+models_command = 'printf "test-model-123\nsmith-large-2\nsmith-mini-1\n"'
+
+  [agents.smith.models]
+  test = "test-model-123"
+  old = "smith-old-0"
+`
+	home := writeConfigFiles(t, files)
+
+	run := func(args ...string) (string, error) {
+		cmd := exec.Command(getBinaryPath(t), args...)
+		cmd.Env = []string{"HOME=" + home, "PATH=" + os.Getenv("PATH"), "XDG_CACHE_HOME=" + filepath.Join(home, ".cache")}
+		output, err := cmd.CombinedOutput()
+		return string(output), err
+	}
+
+	// Before a refresh --model only knows the configured models
+	output, err := run("show", "--model", "smith-large-2", "hi")
+	assert.NoError(t, err)
+	assert.Contains(t, output, "run 'start config agent models smith --refresh' to check it")
+
+	output, err = run("config", "agent", "models", "smith")
+	assert.NoError(t, err)
+	assert.Contains(t, output, "Discovered: (none, run 'start config agent models smith --refresh')")
+
+	output, err = run("config", "agent", "models", "smith", "--refresh")
+	assert.NoError(t, err)
+	assert.Contains(t, output, "✓ Refreshed 3 models from printf")
+	assert.Contains(t, output, "⚠ old = smith-old-0 (not in discovered models)")
+	assert.Contains(t, output, "  smith-mini-1")
+
+	// Discovered IDs are known without a warning, and resolve by prefix
+	output, err = run("show", "--model", "smith-large-2", "hi")
+	assert.NoError(t, err)
+	assert.NotContains(t, output, "passing through")
+
+	output, err = run("show", "--model", "smith-m", "hi")
+	assert.NoError(t, err)
+	assert.Contains(t, output, "--model smith-mini-1")
+
+	// Unknown IDs warn with the closest known model
+	output, err = run("show", "--model", "smith-large-3", "hi")
+	assert.NoError(t, err)
+	assert.Contains(t, output, `Model "smith-large-3" is not a known model for agent "smith", passing through to CLI, did you mean "smith-large-2"?`)
+}
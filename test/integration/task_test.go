@@ -1,9 +1,11 @@
 package integration
 
 import (
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/grantcarthew/start/test/assert"
@@ -381,3 +383,200 @@ func ensureStartBinary(t *testing.T) {
 		}
 	}
 }
+
+// TestTask_Params tests typed task parameters in listing, help, preview and config test
+func TestTask_Params(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ensureStartBinary(t)
+
+	files := make(map[string]string)
+	for name, content := range showTestConfig {
+		files[name] = content
+	}
+	files["tasks.toml"] = `[tasks.scan]
+description = "Scan an area"
+prompt = "Scan {param:area} at depth {param:depth}: {instructions}"
+
+[tasks.scan.params.area]
+type = "enum"
+values = ["api", "web"]
+required = true
+description = "Area to scan"
+
+[tasks.scan.params.depth]
+type = "int"
+default = 2
+`
+	home := writeConfigFiles(t, files)
+
+	run := func(args ...string) (string, error) {
+		cmd := exec.Command(getBinaryPath(t), args...)
+		cmd.Env = []string{"HOME=" + home, "PATH=" + os.Getenv("PATH")}
+		output, err := cmd.CombinedOutput()
+		return string(output), err
+	}
+
+	output, err := run("task")
+	assert.NoError(t, err)
+	assert.Contains(t, output, "params: area=api|web (required), depth:int (default 2)")
+
+	output, err = run("task", "scan", "--help")
+	assert.NoError(t, err)
+	assert.Contains(t, output, "Parameters:")
+	assert.Contains(t, output, "Area to scan")
+	assert.Contains(t, output, "start task scan <area> [instructions]")
+
+	// Required params are positional, the remaining args are instructions
+	output, err = run("show", "task", "scan", "web", "--param", "depth=3", "check", "auth")
+	assert.NoError(t, err)
+	assert.Contains(t, output, "Parameters: area=web, depth=3")
+	assert.Contains(t, output, "Scan web at depth 3: check auth")
+
+	output, err = run("show", "task", "scan", "--param", "area=cli")
+	assert.Error(t, err)
+	assert.Contains(t, output, "must be one of: api, web")
+
+	output, err = run("config", "task", "test", "scan")
+	assert.NoError(t, err)
+	assert.Contains(t, output, "Sample prompt:")
+	assert.Contains(t, output, "Scan api at depth 2: {instructions}")
+}
+
+// TestTask_Steps tests a pipeline task chaining headless step outputs
+func TestTask_Steps(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ensureSmithBinary(t)
+	ensureStartBinary(t)
+	home := headlessTestConfig(t, "0")
+
+	smithBinPath, err := filepath.Abs(filepath.Join("..", "..", "bin", "smith"))
+	assert.NoError(t, err)
+
+	// broken always fails, so only steps that continue past it succeed
+	configDir := filepath.Join(home, ".config", "start")
+	agents, err := os.ReadFile(filepath.Join(configDir, "agents.toml"))
+	assert.NoError(t, err)
+	agents = append(agents, []byte(`
+[agents.broken]
+bin = "`+smithBinPath+`"
+command = "{bin} --model {model} '{prompt}'; exit 2"
+default_model = "test"
+
+  [agents.broken.models]
+  test = "broken-model"
+`)...)
+	assert.NoError(t, os.WriteFile(filepath.Join(configDir, "agents.toml"), agents, 0644))
+
+	tasks := `[tasks.summarise]
+prompt = "Summary of {instructions}"
+
+[tasks.release]
+description = "Summarise then write notes"
+
+[[tasks.release.steps]]
+name = "summary"
+task = "summarise"
+
+[[tasks.release.steps]]
+name = "lint"
+prompt = "Lint {step:summary}"
+agent = "broken"
+on_failure = "continue"
+
+[[tasks.release.steps]]
+name = "notes"
+prompt = "Notes from: {step:summary} [{step:lint}]"
+
+[tasks.strict]
+description = "Stops at the failing step"
+
+[[tasks.strict.steps]]
+name = "lint"
+prompt = "Lint"
+agent = "broken"
+
+[[tasks.strict.steps]]
+name = "notes"
+prompt = "Notes from {step:lint}"
+`
+	assert.NoError(t, os.WriteFile(filepath.Join(configDir, "tasks.toml"), []byte(tasks), 0644))
+
+	run := func(args ...string) (string, string, error) {
+		cmd := exec.Command(getBinaryPath(t), args...)
+		cmd.Env = []string{"HOME=" + home, "PATH=" + os.Getenv("PATH")}
+		var stderr strings.Builder
+		cmd.Stderr = &stderr
+		output, err := cmd.Output()
+		return string(output), stderr.String(), err
+	}
+
+	output, progress, err := run("task", "release", "v1.2")
+	if err != nil {
+		t.Logf("stderr: %s", progress)
+	}
+	assert.NoError(t, err)
+	assert.Equal(t, "Notes from: Summary of v1.2 []\n", output)
+	assert.Contains(t, progress, "Step 2/3: lint (agent: broken)")
+	assert.Contains(t, progress, "⚠ Step lint failed: agent exited with status 2, continuing")
+
+	// A failing step stops the pipeline with the agent's exit code and no usage
+	output, progress, err = run("task", "strict")
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 2 {
+		t.Errorf("Expected exit code 2 from the failing step, got: %v", err)
+	}
+	assert.Equal(t, "", output)
+	assert.Contains(t, progress, "✗ Step lint failed")
+	assert.NotContains(t, progress, "Step 2/2: notes")
+	assert.NotContains(t, progress, "Usage:")
+
+	output, _, err = run("show", "task", "release")
+	assert.NoError(t, err)
+	assert.Contains(t, output, "Steps (3):")
+	assert.Contains(t, output, "task: summarise")
+}
+
+// TestTask_ListOrder tests that task listings follow definition order and the order override
+func TestTask_ListOrder(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+
+	ensureStartBinary(t)
+
+	files := make(map[string]string)
+	for name, content := range showTestConfig {
+		files[name] = content
+	}
+	files["tasks.toml"] = `[tasks.zeta]
+prompt = "Zeta"
+
+[tasks.alpha]
+prompt = "Alpha"
+
+[tasks.mid]
+prompt = "Mid"
+order = 1
+`
+	home := writeConfigFiles(t, files)
+
+	for _, args := range [][]string{{"task"}, {"config", "task", "list"}} {
+		cmd := exec.Command(getBinaryPath(t), args...)
+		cmd.Env = []string{"HOME=" + home, "PATH=" + os.Getenv("PATH")}
+		output, err := cmd.CombinedOutput()
+		assert.NoError(t, err)
+
+		mid := strings.Index(string(output), "mid")
+		zeta := strings.Index(string(output), "zeta")
+		alpha := strings.Index(string(output), "alpha")
+		if mid < 0 || mid > zeta || zeta > alpha {
+			t.Errorf("%v: expected mid, zeta, alpha order, got:\n%s", args, output)
+		}
+	}
+}
@@ -4,10 +4,13 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 )
 
 type MockFileSystem struct {
-	Files map[string]string // path -> content
+	Files    map[string]string // path -> content
+	MkdirErr error             // Returned by MkdirAll when set
 }
 
 func NewMockFileSystem() *MockFileSystem {
@@ -37,6 +40,30 @@ func (m *MockFileSystem) Exists(path string) bool {
 	return ok
 }
 
+// IsDir reports whether any file lives under path
+func (m *MockFileSystem) IsDir(path string) bool {
+	prefix := strings.TrimSuffix(path, "/") + "/"
+	for file := range m.Files {
+		if strings.HasPrefix(file, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// WalkFiles returns the files under root in sorted order
+func (m *MockFileSystem) WalkFiles(root string) ([]string, error) {
+	prefix := strings.TrimSuffix(root, "/") + "/"
+	var files []string
+	for file := range m.Files {
+		if strings.HasPrefix(file, prefix) && !strings.Contains(file, "/.git/") {
+			files = append(files, file)
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
 func (m *MockFileSystem) Glob(pattern string) ([]string, error) {
 	var matches []string
 	for path := range m.Files {
@@ -49,7 +76,7 @@ func (m *MockFileSystem) Glob(pattern string) ([]string, error) {
 }
 
 func (m *MockFileSystem) MkdirAll(path string, perm os.FileMode) error {
-	return nil
+	return m.MkdirErr
 }
 
 func (m *MockFileSystem) TempFile(pattern string) (string, error) {
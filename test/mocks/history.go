@@ -0,0 +1,19 @@
+package mocks
+
+import "github.com/grantcarthew/start/internal/domain"
+
+// MockHistoryStore is an in-memory implementation of the HistoryStore interface
+type MockHistoryStore struct {
+	Entries []domain.HistoryEntry
+}
+
+// Append records the entry
+func (m *MockHistoryStore) Append(entry domain.HistoryEntry) error {
+	m.Entries = append(m.Entries, entry)
+	return nil
+}
+
+// List returns recorded entries, oldest first
+func (m *MockHistoryStore) List() ([]domain.HistoryEntry, error) {
+	return m.Entries, nil
+}
@@ -0,0 +1,33 @@
+package mocks
+
+import "time"
+
+// MockModelCache is an in-memory implementation of the ModelCache interface
+type MockModelCache struct {
+	Entries  map[string][]string
+	StoredAt map[string]time.Time
+}
+
+// NewMockModelCache creates an empty mock model cache
+func NewMockModelCache() *MockModelCache {
+	return &MockModelCache{
+		Entries:  make(map[string][]string),
+		StoredAt: make(map[string]time.Time),
+	}
+}
+
+// Get returns the model IDs stored for an agent
+func (m *MockModelCache) Get(agent string) ([]string, time.Time, bool) {
+	ids, ok := m.Entries[agent]
+	if !ok {
+		return nil, time.Time{}, false
+	}
+	return ids, m.StoredAt[agent], true
+}
+
+// Set stores the model IDs for an agent
+func (m *MockModelCache) Set(agent string, ids []string) error {
+	m.Entries[agent] = ids
+	m.StoredAt[agent] = time.Now()
+	return nil
+}
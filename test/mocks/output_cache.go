@@ -0,0 +1,40 @@
+package mocks
+
+import "time"
+
+// MockOutputCache is an in-memory implementation of the OutputCache interface
+type MockOutputCache struct {
+	Entries  map[string]string
+	StoredAt map[string]time.Time
+}
+
+// NewMockOutputCache creates an empty mock output cache
+func NewMockOutputCache() *MockOutputCache {
+	return &MockOutputCache{
+		Entries:  make(map[string]string),
+		StoredAt: make(map[string]time.Time),
+	}
+}
+
+// Get returns the output stored for key if it is younger than ttl
+func (m *MockOutputCache) Get(key string, ttl time.Duration) (string, time.Time, bool) {
+	output, ok := m.Entries[key]
+	if !ok || time.Since(m.StoredAt[key]) > ttl {
+		return "", time.Time{}, false
+	}
+	return output, m.StoredAt[key], true
+}
+
+// Set stores output for key
+func (m *MockOutputCache) Set(key, output string) error {
+	m.Entries[key] = output
+	m.StoredAt[key] = time.Now()
+	return nil
+}
+
+// Clear removes all entries
+func (m *MockOutputCache) Clear() error {
+	m.Entries = make(map[string]string)
+	m.StoredAt = make(map[string]time.Time)
+	return nil
+}
@@ -2,19 +2,24 @@ package mocks
 
 import (
 	"fmt"
+	"io"
+	"sync"
 )
 
 // MockRunner is a mock implementation of the Runner interface
 type MockRunner struct {
 	CalledWith   []CallRecord
+	StdinPath    string // Set by RedirectStdin
+	OnExec       func() // Called by Exec and ExecArgs, to inspect state at exec time
 	ShouldError  bool
 	ErrorMessage string
 }
 
-// CallRecord tracks a single call to Exec
+// CallRecord tracks a single call to Exec or ExecArgs
 type CallRecord struct {
 	Shell   string
 	Command string
+	Args    []string // Set for ExecArgs calls
 }
 
 func NewMockRunner() *MockRunner {
@@ -31,6 +36,9 @@ func (m *MockRunner) Exec(shell, command string) error {
 		Shell:   shell,
 		Command: command,
 	})
+	if m.OnExec != nil {
+		m.OnExec()
+	}
 
 	// Return error if configured
 	if m.ShouldError {
@@ -42,6 +50,70 @@ func (m *MockRunner) Exec(shell, command string) error {
 	return nil
 }
 
+// ExecArgs simulates argv process replacement
+func (m *MockRunner) ExecArgs(args []string) error {
+	m.CalledWith = append(m.CalledWith, CallRecord{
+		Args: args,
+	})
+	if m.OnExec != nil {
+		m.OnExec()
+	}
+
+	if m.ShouldError {
+		return fmt.Errorf("%s", m.ErrorMessage)
+	}
+
+	return nil
+}
+
+// RedirectStdin records the stdin file path
+func (m *MockRunner) RedirectStdin(path string) error {
+	m.StdinPath = path
+	return nil
+}
+
+// MockProcessRunner is a mock implementation of the ProcessRunner interface
+// Calls are safe for concurrent use
+type MockProcessRunner struct {
+	mu         sync.Mutex
+	CalledWith []CallRecord
+	Stdin      []string // Content read from stdin for each call
+	Output     string   // Written to stdout on each call
+	ExitCode   int
+	Err        error
+}
+
+// Run records the call and writes the configured output
+func (m *MockProcessRunner) Run(shell, command string, stdin io.Reader, stdout io.Writer) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.CalledWith = append(m.CalledWith, CallRecord{Shell: shell, Command: command})
+	return m.respond(stdin, stdout)
+}
+
+// RunArgs records the call and writes the configured output
+func (m *MockProcessRunner) RunArgs(args []string, stdin io.Reader, stdout io.Writer) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.CalledWith = append(m.CalledWith, CallRecord{Args: args})
+	return m.respond(stdin, stdout)
+}
+
+func (m *MockProcessRunner) respond(stdin io.Reader, stdout io.Writer) (int, error) {
+	input := ""
+	if stdin != nil {
+		data, _ := io.ReadAll(stdin)
+		input = string(data)
+	}
+	m.Stdin = append(m.Stdin, input)
+
+	if m.Err != nil {
+		return -1, m.Err
+	}
+	io.WriteString(stdout, m.Output)
+	return m.ExitCode, nil
+}
+
 // MockCommandRunner is a mock implementation of the CommandRunner interface
 type MockCommandRunner struct {
 	output  string
@@ -0,0 +1,42 @@
+package mocks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/grantcarthew/start/internal/domain"
+)
+
+// MockURLFetcher is an in-memory implementation of the URLFetcher interface
+type MockURLFetcher struct {
+	Responses map[string]domain.FetchedURL
+	Errors    map[string]error
+	Fetched   []string
+}
+
+// NewMockURLFetcher creates a mock URL fetcher with no responses
+func NewMockURLFetcher() *MockURLFetcher {
+	return &MockURLFetcher{
+		Responses: make(map[string]domain.FetchedURL),
+		Errors:    make(map[string]error),
+	}
+}
+
+// SetBody sets a fresh response for url
+func (m *MockURLFetcher) SetBody(url, body string) {
+	m.Responses[url] = domain.FetchedURL{Body: body, FetchedAt: time.Now()}
+}
+
+// Fetch returns the response or error configured for url
+func (m *MockURLFetcher) Fetch(ctx context.Context, url string, timeout time.Duration) (domain.FetchedURL, error) {
+	m.Fetched = append(m.Fetched, url)
+	if err, ok := m.Errors[url]; ok {
+		return domain.FetchedURL{}, err
+	}
+	fetched, ok := m.Responses[url]
+	if !ok {
+		return domain.FetchedURL{}, fmt.Errorf("failed to fetch %s: HTTP 404", url)
+	}
+	return fetched, nil
+}